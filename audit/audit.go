@@ -0,0 +1,204 @@
+// Package audit scans source code for references to environment variables.
+package audit
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/k1LoW/envdo/schema"
+)
+
+// defaultPatterns are the default patterns of environment variable lookups per file extension.
+// Each pattern has a capturing group for the key.
+var defaultPatterns = map[string][]string{
+	".go": {
+		`os\.(?:Getenv|LookupEnv)\(\s*"([A-Za-z_][A-Za-z0-9_]*)"`,
+	},
+	".js": {
+		`process\.env\.([A-Za-z_][A-Za-z0-9_]*)`,
+		`process\.env\[\s*["'` + "`" + `]([A-Za-z_][A-Za-z0-9_]*)["'` + "`" + `]\s*\]`,
+		`import\.meta\.env\.([A-Za-z_][A-Za-z0-9_]*)`,
+	},
+	".py": {
+		`os\.environ\[\s*["']([A-Za-z_][A-Za-z0-9_]*)["']\s*\]`,
+		`os\.(?:environ\.get|getenv)\(\s*["']([A-Za-z_][A-Za-z0-9_]*)["']`,
+	},
+	".rb": {
+		`ENV\[\s*["']([A-Za-z_][A-Za-z0-9_]*)["']\s*\]`,
+		`ENV\.fetch\(\s*["']([A-Za-z_][A-Za-z0-9_]*)["']`,
+	},
+	".rs": {
+		`env::var(?:_os)?\(\s*"([A-Za-z_][A-Za-z0-9_]*)"`,
+	},
+	".sh": {
+		`\$\{?([A-Z_][A-Z0-9_]*)`,
+	},
+}
+
+// aliases are file extensions sharing the patterns of another extension.
+var aliases = map[string]string{
+	".jsx":  ".js",
+	".mjs":  ".js",
+	".cjs":  ".js",
+	".ts":   ".js",
+	".tsx":  ".js",
+	".mts":  ".js",
+	".cts":  ".js",
+	".vue":  ".js",
+	".bash": ".sh",
+	".zsh":  ".sh",
+}
+
+// systemKeys are well-known variables provided by the system rather than by profiles.
+var systemKeys = []string{"HOME", "PATH", "PWD", "OLDPWD", "SHELL", "USER", "LOGNAME", "TERM", "TMPDIR", "LANG", "HOSTNAME", "CI"}
+
+var skipDirs = []string{".git", "node_modules", "vendor", ".venv", "dist", "target"}
+
+// Reference represents a reference to an environment variable in source code.
+type Reference struct {
+	Key  string `json:"key"`
+	File string `json:"file"`
+	Line int    `json:"line"`
+}
+
+// Scanner scans source code for references to environment variables.
+type Scanner struct {
+	patterns map[string][]*regexp.Regexp
+}
+
+// NewScanner creates a new Scanner with the default patterns.
+func NewScanner() *Scanner {
+	s := &Scanner{
+		patterns: make(map[string][]*regexp.Regexp),
+	}
+	for ext, exprs := range defaultPatterns {
+		for _, expr := range exprs {
+			s.patterns[ext] = append(s.patterns[ext], regexp.MustCompile(expr))
+		}
+	}
+	for ext, base := range aliases {
+		s.patterns[ext] = s.patterns[base]
+	}
+	return s
+}
+
+// AddPattern adds a pattern of environment variable lookups for files with the extension.
+// The pattern must have exactly one capturing group for the key.
+func (s *Scanner) AddPattern(ext, expr string) error {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return fmt.Errorf("invalid pattern %q: %w", expr, err)
+	}
+	if re.NumSubexp() != 1 {
+		return fmt.Errorf("pattern %q must have exactly one capturing group", expr)
+	}
+	if !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	s.patterns[ext] = append(slices.Clone(s.patterns[ext]), re)
+	return nil
+}
+
+// Scan scans paths for references to environment variables.
+// A path ending with "/..." (such as "./...") is scanned recursively, as are directories.
+func (s *Scanner) Scan(paths ...string) ([]Reference, error) {
+	var refs []Reference
+	for _, p := range paths {
+		p = strings.TrimSuffix(strings.TrimSuffix(p, "..."), string(filepath.Separator))
+		if p == "" {
+			p = "."
+		}
+		err := filepath.WalkDir(p, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				if path != p && slices.Contains(skipDirs, d.Name()) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			patterns, ok := s.patterns[filepath.Ext(path)]
+			if !ok {
+				return nil
+			}
+			found, err := scanFile(path, patterns)
+			if err != nil {
+				return err
+			}
+			refs = append(refs, found...)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return refs, nil
+}
+
+// Unused returns the keys of envs not referenced by refs, sorted by key.
+func Unused(envs map[string]string, refs []Reference) []string {
+	referenced := make(map[string]bool)
+	for _, r := range refs {
+		referenced[r.Key] = true
+	}
+	var unused []string
+	for k := range envs {
+		if !referenced[k] {
+			unused = append(unused, k)
+		}
+	}
+	slices.Sort(unused)
+	return unused
+}
+
+// Missing returns refs to keys defined neither in envs nor in the schema,
+// excluding well-known system variables and keys in ignore.
+func Missing(envs map[string]string, s *schema.Schema, refs []Reference, ignore []string) []Reference {
+	var missing []Reference
+	for _, r := range refs {
+		if _, ok := envs[r.Key]; ok {
+			continue
+		}
+		if s.Key(r.Key) != nil || slices.Contains(systemKeys, r.Key) || slices.Contains(ignore, r.Key) {
+			continue
+		}
+		missing = append(missing, r)
+	}
+	slices.SortFunc(missing, func(a, b Reference) int {
+		return cmp.Or(strings.Compare(a.Key, b.Key), strings.Compare(a.File, b.File), cmp.Compare(a.Line, b.Line))
+	})
+	return missing
+}
+
+func scanFile(path string, patterns []*regexp.Regexp) ([]Reference, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var refs []Reference
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	n := 0
+	for scanner.Scan() {
+		n++
+		line := scanner.Text()
+		for _, re := range patterns {
+			for _, m := range re.FindAllStringSubmatch(line, -1) {
+				refs = append(refs, Reference{Key: m[1], File: path, Line: n})
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", path, err)
+	}
+	return refs, nil
+}
@@ -0,0 +1,86 @@
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/k1LoW/envdo/schema"
+)
+
+func TestScanAndUnused(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"main.go":                   "package main\n\nvar a = os.Getenv(\"GO_KEY\")\nvar b, _ = os.LookupEnv(\"GO_LOOKUP\")\n",
+		"web/app.ts":                "const u = process.env.TS_KEY;\nconst v = process.env['TS_BRACKET'];\n",
+		"app.py":                    "x = os.environ.get('PY_KEY')\n",
+		"app.custom":                "read_env(CUSTOM_KEY)\n",
+		"node_modules/lib/index.js": "process.env.IGNORED\n",
+	}
+	for name, content := range files {
+		p := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := NewScanner()
+	if err := s.AddPattern("custom", `read_env\((\w+)\)`); err != nil {
+		t.Fatal(err)
+	}
+	refs, err := s.Scan(dir + "/...")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keys []string
+	for _, r := range refs {
+		keys = append(keys, r.Key)
+	}
+	slices.Sort(keys)
+	want := []string{"CUSTOM_KEY", "GO_KEY", "GO_LOOKUP", "PY_KEY", "TS_BRACKET", "TS_KEY"}
+	if !slices.Equal(keys, want) {
+		t.Errorf("want %v, got %v", want, keys)
+	}
+	for _, r := range refs {
+		if r.Key == "GO_LOOKUP" && r.Line != 4 {
+			t.Errorf("want line 4, got %d", r.Line)
+		}
+	}
+
+	envs := map[string]string{"GO_KEY": "", "TS_KEY": "", "DEAD_KEY": "", "IGNORED": ""}
+	if got, want := Unused(envs, refs), []string{"DEAD_KEY", "IGNORED"}; !slices.Equal(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
+
+func TestAddPatternInvalid(t *testing.T) {
+	s := NewScanner()
+	if err := s.AddPattern(".go", `getenv`); err == nil {
+		t.Error("want error but got none")
+	}
+}
+
+func TestMissing(t *testing.T) {
+	refs := []Reference{
+		{Key: "DEFINED", File: "a.go", Line: 1},
+		{Key: "NEW_KEY", File: "b.go", Line: 3},
+		{Key: "NEW_KEY", File: "a.go", Line: 5},
+		{Key: "IN_SCHEMA", File: "a.go", Line: 7},
+		{Key: "HOME", File: "run.sh", Line: 1},
+		{Key: "IGNORED", File: "run.sh", Line: 2},
+	}
+	envs := map[string]string{"DEFINED": "v"}
+	s := &schema.Schema{Keys: map[string]*schema.Key{"IN_SCHEMA": {}}}
+	got := Missing(envs, s, refs, []string{"IGNORED"})
+	want := []Reference{
+		{Key: "NEW_KEY", File: "a.go", Line: 5},
+		{Key: "NEW_KEY", File: "b.go", Line: 3},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
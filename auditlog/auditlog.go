@@ -0,0 +1,77 @@
+// Package auditlog records envdo invocations to an append-only local log.
+package auditlog
+
+import (
+	"encoding/json"
+	"os"
+	"os/user"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// Entry is a record of an envdo invocation.
+// Only the names of the injected keys are recorded, not their values.
+type Entry struct {
+	Time    time.Time `json:"time"`
+	User    string    `json:"user,omitempty"`
+	Dir     string    `json:"dir,omitempty"`
+	Profile string    `json:"profile"`
+	Sources []string  `json:"sources"`
+	Command []string  `json:"command,omitempty"`
+	Keys    []string  `json:"keys"`
+}
+
+// NewEntry creates a new Entry of the current user and working directory.
+func NewEntry(profile string, sources, command []string, envs map[string]string) Entry {
+	keys := make([]string, 0, len(envs))
+	for k := range envs {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	e := Entry{
+		Time:    time.Now(),
+		Profile: profile,
+		Sources: sources,
+		Command: command,
+		Keys:    keys,
+	}
+	if u, err := user.Current(); err == nil {
+		e.User = u.Username
+	}
+	if dir, err := os.Getwd(); err == nil {
+		e.Dir = dir
+	}
+	return e
+}
+
+// DefaultPath returns the path of the audit log in $XDG_STATE_HOME/envdo, falling back to ~/.local/state/envdo.
+func DefaultPath() string {
+	stateDir := os.Getenv("XDG_STATE_HOME")
+	if stateDir == "" {
+		if homeDir, err := os.UserHomeDir(); err == nil {
+			stateDir = filepath.Join(homeDir, ".local", "state")
+		}
+	}
+	return filepath.Join(stateDir, "envdo", "audit.log")
+}
+
+// Append appends the entry to the audit log at path as a JSON line.
+func Append(path string, e Entry) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(b, '\n')); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
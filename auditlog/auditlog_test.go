@@ -0,0 +1,50 @@
+package auditlog
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestAppend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "envdo", "audit.log")
+	envs := map[string]string{"B": "secret", "A": "1"}
+	for range 2 {
+		if err := Append(path, NewEntry("dev", []string{"/tmp/.env.dev"}, []string{"make", "test"}, envs)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), "secret") {
+		t.Errorf("values must not be recorded: %s", b)
+	}
+	scanner := bufio.NewScanner(strings.NewReader(string(b)))
+	n := 0
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatal(err)
+		}
+		if e.Profile != "dev" || !slices.Equal(e.Keys, []string{"A", "B"}) || !slices.Equal(e.Command, []string{"make", "test"}) {
+			t.Errorf("got %+v", e)
+		}
+		n++
+	}
+	if n != 2 {
+		t.Errorf("want 2 entries, got %d", n)
+	}
+}
+
+func TestDefaultPath(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", "/tmp/state")
+	if got, want := DefaultPath(), filepath.Join("/tmp/state", "envdo", "audit.log"); got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,160 @@
+// Package cache caches values of resolved secret references on disk, encrypted with age.
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"filippo.io/age"
+	"github.com/k1LoW/envdo/crypt"
+	"github.com/k1LoW/envdo/env/provider"
+)
+
+// DefaultTTL is the default time to live of cached values.
+const DefaultTTL = 10 * time.Minute
+
+// KeyName is the name of the age identity encrypting the cache in the OS credential store.
+const KeyName = "envdo-cache-key"
+
+// Cache is a TTL cache of resolved secret values stored in an age-encrypted file.
+type Cache struct {
+	path     string
+	identity *age.X25519Identity
+	ttl      time.Duration
+	now      func() time.Time
+	entries  map[string]entry
+	dirty    bool
+}
+
+type entry struct {
+	Value   string    `json:"value"`
+	Expires time.Time `json:"expires"`
+}
+
+// DefaultPath returns the path of the cache in $XDG_CACHE_HOME/envdo, falling back to the user cache directory.
+func DefaultPath() string {
+	cacheDir := os.Getenv("XDG_CACHE_HOME")
+	if cacheDir == "" {
+		cacheDir, _ = os.UserCacheDir()
+	}
+	return filepath.Join(cacheDir, "envdo", "secrets.age")
+}
+
+// Open opens the cache at path encrypted with identity. Values expire after ttl.
+// If the cache cannot be decrypted (e.g. the identity was replaced), it starts empty.
+func Open(path string, identity *age.X25519Identity, ttl time.Duration) (*Cache, error) {
+	c := &Cache{
+		path:     path,
+		identity: identity,
+		ttl:      ttl,
+		now:      time.Now,
+		entries:  make(map[string]entry),
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return c, nil
+		}
+		return nil, err
+	}
+	plaintext, err := crypt.Decrypt(b, []age.Identity{identity})
+	if err != nil {
+		c.dirty = true
+		return c, nil
+	}
+	if err := json.Unmarshal(plaintext, &c.entries); err != nil {
+		c.entries = make(map[string]entry)
+		c.dirty = true
+	}
+	return c, nil
+}
+
+// Get returns the cached value of key if it has not expired.
+func (c *Cache) Get(key string) (string, bool) {
+	e, ok := c.entries[key]
+	if !ok || !c.now().Before(e.Expires) {
+		return "", false
+	}
+	return e.Value, true
+}
+
+// Set caches the value of key.
+func (c *Cache) Set(key, value string) {
+	c.entries[key] = entry{Value: value, Expires: c.now().Add(c.ttl)}
+	c.dirty = true
+}
+
+// Save writes the cache without expired values to the file if it has been changed.
+func (c *Cache) Save() error {
+	if !c.dirty {
+		return nil
+	}
+	now := c.now()
+	for key, e := range c.entries {
+		if !now.Before(e.Expires) {
+			delete(c.entries, key)
+		}
+	}
+	b, err := json.Marshal(c.entries)
+	if err != nil {
+		return err
+	}
+	ciphertext, err := crypt.Encrypt(b, []age.Recipient{c.identity.Recipient()})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), ".secrets-*.age")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(ciphertext); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), c.path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	c.dirty = false
+	return nil
+}
+
+// Clear removes the cache at path.
+func Clear(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// Key returns the age identity encrypting the cache stored in the OS credential store,
+// generating and storing it if it does not exist.
+func Key(ctx context.Context, k *provider.Keychain) (*age.X25519Identity, error) {
+	s, err := k.Get(ctx, KeyName)
+	if err == nil {
+		return age.ParseX25519Identity(s)
+	}
+	if !errors.Is(err, provider.ErrSecretNotFound) {
+		return nil, err
+	}
+	id, err := age.GenerateX25519Identity()
+	if err != nil {
+		return nil, err
+	}
+	if err := k.Set(ctx, KeyName, id.String()); err != nil {
+		return nil, err
+	}
+	return id, nil
+}
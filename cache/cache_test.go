@@ -0,0 +1,76 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"filippo.io/age"
+)
+
+func TestCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "envdo", "secrets.age")
+	id, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	c, err := Open(path, id, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.now = func() time.Time { return now }
+	if _, ok := c.Get("vault://secret/app#KEY"); ok {
+		t.Error("empty cache should miss")
+	}
+	c.Set("vault://secret/app#KEY", "s3cr3t")
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), "s3cr3t") || strings.Contains(string(b), "vault://") {
+		t.Errorf("cache must be encrypted: %s", b)
+	}
+
+	c, err = Open(path, id, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.now = func() time.Time { return now.Add(30 * time.Second) }
+	if v, ok := c.Get("vault://secret/app#KEY"); !ok || v != "s3cr3t" {
+		t.Errorf("got %q, %v", v, ok)
+	}
+	c.now = func() time.Time { return now.Add(time.Minute) }
+	if _, ok := c.Get("vault://secret/app#KEY"); ok {
+		t.Error("expired value should miss")
+	}
+
+	// A cache encrypted with another identity starts empty
+	other, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err = Open(path, other, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.Get("vault://secret/app#KEY"); ok {
+		t.Error("cache of another identity should miss")
+	}
+
+	if err := Clear(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("cache should be removed: %v", err)
+	}
+	if err := Clear(path); err != nil {
+		t.Errorf("clearing a missing cache should succeed: %v", err)
+	}
+}
@@ -0,0 +1,242 @@
+// Package check runs hygiene checks of environment variables and their files.
+package check
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/k1LoW/envdo/env"
+	"github.com/k1LoW/envdo/schema"
+)
+
+// Names of checks.
+const (
+	Lint       = "lint"
+	Validate   = "validate"
+	Schema     = "schema"
+	Permission = "permission"
+	Gitignore  = "gitignore"
+	Expiry     = "expiry"
+	Shadow     = "shadow"
+	Required   = "required"
+	Example    = "example"
+)
+
+// Checks are the names of all checks.
+var Checks = []string{Lint, Validate, Schema, Permission, Gitignore, Expiry, Shadow, Required, Example}
+
+// exampleSuffixes are suffixes of .env files meant to be committed.
+var exampleSuffixes = []string{".example", ".sample", ".template", ".dist"}
+
+// Diagnostic represents a problem found by a check.
+type Diagnostic struct {
+	Check string `json:"check"`
+	env.Issue
+}
+
+// LintFiles checks the syntax and key names of files.
+func LintFiles(files []string) ([]Diagnostic, error) {
+	var ds []Diagnostic
+	for _, f := range files {
+		issues, err := env.Lint(f)
+		if err != nil {
+			return nil, err
+		}
+		ds = append(ds, diagnostics(Lint, issues)...)
+	}
+	return ds, nil
+}
+
+// ValidateValues checks values of envs against the types defined in the schema.
+func ValidateValues(envs map[string]string, s *schema.Schema) []Diagnostic {
+	var ds []Diagnostic
+	for _, k := range sortedKeys(envs) {
+		if err := s.Validate(k, envs[k]); err != nil {
+			ds = append(ds, Diagnostic{Check: Validate, Issue: env.Issue{Severity: env.SeverityError, Key: k, Message: err.Error()}})
+		}
+	}
+	return ds
+}
+
+// SchemaCoverage checks that keys of envs are documented in the schema.
+// It reports nothing if the schema defines no keys.
+func SchemaCoverage(envs map[string]string, s *schema.Schema) []Diagnostic {
+	if s == nil || len(s.Keys) == 0 {
+		return nil
+	}
+	var ds []Diagnostic
+	for _, k := range sortedKeys(envs) {
+		if s.Key(k) == nil {
+			ds = append(ds, Diagnostic{Check: Schema, Issue: env.Issue{Severity: env.SeverityWarning, Key: k, Message: fmt.Sprintf("key %s is not defined in the schema", k)}})
+		}
+	}
+	return ds
+}
+
+// Permissions checks that files are not accessible by other users.
+func Permissions(files []string) ([]Diagnostic, error) {
+	var ds []Diagnostic
+	for _, f := range files {
+		issues, err := env.CheckPermission(f)
+		if err != nil {
+			return nil, err
+		}
+		ds = append(ds, diagnostics(Permission, issues)...)
+	}
+	return ds, nil
+}
+
+// ExpiryDates checks the expiry annotations of keys in files at now.
+func ExpiryDates(files []string, now time.Time) ([]Diagnostic, error) {
+	var ds []Diagnostic
+	for _, f := range files {
+		issues, err := env.CheckExpiry(f, now)
+		if err != nil {
+			return nil, err
+		}
+		ds = append(ds, diagnostics(Expiry, issues)...)
+	}
+	return ds, nil
+}
+
+// RequiredKeys checks that keys annotated as required in files (# envdo:required),
+// marked as required in the schema or listed in required are defined with non-empty values in envs.
+func RequiredKeys(files []string, envs map[string]string, s *schema.Schema, required []string) ([]Diagnostic, error) {
+	keys := slices.Concat(s.RequiredKeys(), required)
+	for _, f := range files {
+		ks, err := env.RequiredKeys(f)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, ks...)
+	}
+	slices.Sort(keys)
+	var ds []Diagnostic
+	for _, k := range slices.Compact(keys) {
+		if envs[k] == "" {
+			ds = append(ds, Diagnostic{Check: Required, Issue: env.Issue{Severity: env.SeverityError, Key: k, Message: fmt.Sprintf("required key %s is missing or empty", k)}})
+		}
+	}
+	return ds, nil
+}
+
+// Shadowed reports definitions in entries overridden by other definitions of the same keys.
+// entries must be in priority order as returned by env.Env.Entries.
+func Shadowed(entries []env.Entry) []Diagnostic {
+	var ds []Diagnostic
+	for _, c := range env.Conflicts(entries) {
+		winner := c.Entries[0]
+		for _, e := range c.Entries[1:] {
+			ds = append(ds, Diagnostic{Check: Shadow, Issue: env.Issue{Severity: env.SeverityWarning, File: e.File, Line: e.Line, Key: e.Key, Message: fmt.Sprintf("key %s is shadowed by %s:%d", e.Key, winner.File, winner.Line)}})
+		}
+	}
+	return ds
+}
+
+// Gitignored checks that .env files in dir are ignored by git.
+// Example files such as .env.example are excluded.
+// It reports nothing if git is not installed or dir is not in a git repository.
+func Gitignored(dir string) ([]Diagnostic, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, nil
+	}
+	if err := exec.Command("git", "-C", dir, "rev-parse", "--is-inside-work-tree").Run(); err != nil {
+		return nil, nil
+	}
+	files, err := filepath.Glob(filepath.Join(dir, ".env*"))
+	if err != nil {
+		return nil, err
+	}
+	var ds []Diagnostic
+	for _, f := range files {
+		name := filepath.Base(f)
+		if name != ".env" && !strings.HasPrefix(name, ".env.") {
+			continue
+		}
+		if slices.ContainsFunc(exampleSuffixes, func(s string) bool { return strings.HasSuffix(name, s) }) {
+			continue
+		}
+		if fi, err := os.Stat(f); err != nil || fi.IsDir() {
+			continue
+		}
+		err := exec.Command("git", "-C", dir, "check-ignore", "-q", name).Run()
+		if err == nil {
+			continue
+		}
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			ds = append(ds, Diagnostic{Check: Gitignore, Issue: env.Issue{Severity: env.SeverityError, File: f, Message: fmt.Sprintf("%s is not ignored by git", name)}})
+			continue
+		}
+		return nil, fmt.Errorf("failed to run git check-ignore: %w", err)
+	}
+	return ds, nil
+}
+
+// FindExample returns the path of the example .env file in dir (.env.example, .env.sample, .env.template
+// or .env.dist in this order), or an empty string if there is none.
+func FindExample(dir string) string {
+	for _, suffix := range exampleSuffixes {
+		path := filepath.Join(dir, ".env"+suffix)
+		if fi, err := os.Stat(path); err == nil && !fi.IsDir() {
+			return path
+		}
+	}
+	return ""
+}
+
+// ExampleKeys compares the keys of envs with the keys of the example .env file.
+// Keys in the example but not defined in envs are errors, and keys in envs not documented in the example are warnings.
+func ExampleKeys(example string, envs map[string]string) ([]Diagnostic, error) {
+	entries, err := env.New("", "").ReadEntries([]string{example})
+	if err != nil {
+		return nil, err
+	}
+	var ds []Diagnostic
+	documented := make(map[string]bool)
+	for _, e := range entries {
+		if e.Unset || documented[e.Key] {
+			continue
+		}
+		documented[e.Key] = true
+		if _, ok := envs[e.Key]; !ok {
+			ds = append(ds, Diagnostic{Check: Example, Issue: env.Issue{Severity: env.SeverityError, File: example, Line: e.Line, Key: e.Key, Message: fmt.Sprintf("key %s is in %s but not defined", e.Key, filepath.Base(example))}})
+		}
+	}
+	for _, k := range sortedKeys(envs) {
+		if !documented[k] {
+			ds = append(ds, Diagnostic{Check: Example, Issue: env.Issue{Severity: env.SeverityWarning, File: example, Key: k, Message: fmt.Sprintf("key %s is not documented in %s", k, filepath.Base(example))}})
+		}
+	}
+	return ds, nil
+}
+
+// Failed reports whether ds contains errors, or warnings if strict is true.
+func Failed(ds []Diagnostic, strict bool) bool {
+	return slices.ContainsFunc(ds, func(d Diagnostic) bool {
+		return d.Severity == env.SeverityError || (strict && d.Severity == env.SeverityWarning)
+	})
+}
+
+func diagnostics(check string, issues []env.Issue) []Diagnostic {
+	ds := make([]Diagnostic, 0, len(issues))
+	for _, i := range issues {
+		ds = append(ds, Diagnostic{Check: check, Issue: i})
+	}
+	return ds
+}
+
+func sortedKeys(envs map[string]string) []string {
+	keys := make([]string, 0, len(envs))
+	for k := range envs {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,157 @@
+package check
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/k1LoW/envdo/env"
+	"github.com/k1LoW/envdo/schema"
+)
+
+func TestValidateAndSchemaCoverage(t *testing.T) {
+	envs := map[string]string{
+		"PORT":         "http",
+		"UNDOCUMENTED": "x",
+	}
+	s := &schema.Schema{Keys: map[string]*schema.Key{"PORT": {Type: schema.TypeInt}}}
+
+	ds := ValidateValues(envs, s)
+	if len(ds) != 1 || ds[0].Check != Validate || ds[0].Key != "PORT" || ds[0].Severity != env.SeverityError {
+		t.Errorf("unexpected validate diagnostics: %+v", ds)
+	}
+	ds = SchemaCoverage(envs, s)
+	if len(ds) != 1 || ds[0].Check != Schema || ds[0].Key != "UNDOCUMENTED" || ds[0].Severity != env.SeverityWarning {
+		t.Errorf("unexpected schema diagnostics: %+v", ds)
+	}
+	if ds := SchemaCoverage(envs, &schema.Schema{}); len(ds) != 0 {
+		t.Errorf("want no diagnostics without schema, got %+v", ds)
+	}
+}
+
+func TestRequiredKeys(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".env")
+	if err := os.WriteFile(path, []byte("# envdo:required\nAPI_KEY=\n# envdo:required\nPORT=8080\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	s := &schema.Schema{Keys: map[string]*schema.Key{"DATABASE_URL": {Required: true}, "PORT": {Required: true}}}
+	ds, err := RequiredKeys([]string{path}, map[string]string{"API_KEY": "", "PORT": "8080"}, s, []string{"PORT", "REDIS_URL"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ds) != 3 || ds[0].Key != "API_KEY" || ds[1].Key != "DATABASE_URL" || ds[2].Key != "REDIS_URL" {
+		t.Errorf("got %+v", ds)
+	}
+}
+
+func TestExampleKeys(t *testing.T) {
+	dir := t.TempDir()
+	if FindExample(dir) != "" {
+		t.Error("want no example file")
+	}
+	path := filepath.Join(dir, ".env.sample")
+	if err := os.WriteFile(path, []byte("# Database\nDATABASE_URL=\nPORT=8080\nAPI_KEY=\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if got := FindExample(dir); got != path {
+		t.Fatalf("got %q, want %q", got, path)
+	}
+	ds, err := ExampleKeys(path, map[string]string{"DATABASE_URL": "postgres://", "PORT": "", "DEBUG": "1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ds) != 2 {
+		t.Fatalf("got %+v", ds)
+	}
+	if ds[0].Key != "API_KEY" || ds[0].Line != 4 || ds[0].Severity != env.SeverityError {
+		t.Errorf("unexpected missing key diagnostic: %+v", ds[0])
+	}
+	if ds[1].Key != "DEBUG" || ds[1].Severity != env.SeverityWarning || ds[1].Message != "key DEBUG is not documented in .env.sample" {
+		t.Errorf("unexpected undocumented key diagnostic: %+v", ds[1])
+	}
+}
+
+func TestShadowed(t *testing.T) {
+	entries := []env.Entry{
+		{Key: "API_KEY", Value: "a", File: "/app/.env", Line: 1},
+		{Key: "PORT", Value: "8080", File: "/app/.env", Line: 2},
+		{Key: "API_KEY", Value: "b", File: "/config/envdo/.env", Line: 3},
+	}
+	ds := Shadowed(entries)
+	if len(ds) != 1 {
+		t.Fatalf("got %+v", ds)
+	}
+	want := Diagnostic{Check: Shadow, Issue: env.Issue{Severity: env.SeverityWarning, File: "/config/envdo/.env", Line: 3, Key: "API_KEY", Message: "key API_KEY is shadowed by /app/.env:1"}}
+	if ds[0] != want {
+		t.Errorf("got %+v, want %+v", ds[0], want)
+	}
+}
+
+func TestFailed(t *testing.T) {
+	warn := []Diagnostic{{Check: Lint, Issue: env.Issue{Severity: env.SeverityWarning}}}
+	errs := []Diagnostic{{Check: Lint, Issue: env.Issue{Severity: env.SeverityError}}}
+	if Failed(nil, true) {
+		t.Error("want not failed without diagnostics")
+	}
+	if Failed(warn, false) {
+		t.Error("want not failed with warnings")
+	}
+	if !Failed(warn, true) {
+		t.Error("want failed with warnings in strict mode")
+	}
+	if !Failed(errs, false) {
+		t.Error("want failed with errors")
+	}
+}
+
+func TestGitignored(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir := t.TempDir()
+	if out, err := exec.Command("git", "-C", dir, "init", "-q").CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v: %s", err, out)
+	}
+	for name, content := range map[string]string{
+		".gitignore":      ".env\n",
+		".env":            "A=1\n",
+		".env.production": "A=2\n",
+		".env.example":    "A=\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ds, err := Gitignored(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ds) != 1 || filepath.Base(ds[0].File) != ".env.production" {
+		t.Errorf("unexpected gitignore diagnostics: %+v", ds)
+	}
+}
+
+func TestWriteSARIF(t *testing.T) {
+	ds := []Diagnostic{
+		{Check: Lint, Issue: env.Issue{Severity: env.SeverityWarning, File: ".env", Line: 3, Message: "invalid line"}},
+	}
+	var buf bytes.Buffer
+	if err := WriteSARIF(&buf, ds, "0.0.0"); err != nil {
+		t.Fatal(err)
+	}
+	var got sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Version != "2.1.0" || len(got.Runs) != 1 || len(got.Runs[0].Results) != 1 {
+		t.Fatalf("unexpected SARIF log: %s", buf.String())
+	}
+	r := got.Runs[0].Results[0]
+	if r.RuleID != Lint || r.Level != "warning" || r.Locations[0].PhysicalLocation.Region.StartLine != 3 {
+		t.Errorf("unexpected SARIF result: %+v", r)
+	}
+}
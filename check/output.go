@@ -0,0 +1,131 @@
+package check
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+)
+
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID string `json:"id"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// WriteText writes diagnostics in a human-readable form.
+func WriteText(w io.Writer, ds []Diagnostic) error {
+	for _, d := range ds {
+		loc := ""
+		switch {
+		case d.File != "" && d.Line > 0:
+			loc = fmt.Sprintf("%s:%d: ", d.File, d.Line)
+		case d.File != "":
+			loc = d.File + ": "
+		}
+		if _, err := fmt.Fprintf(w, "%s%s: [%s] %s\n", loc, d.Severity, d.Check, d.Message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteJSON writes diagnostics as a JSON array.
+func WriteJSON(w io.Writer, ds []Diagnostic) error {
+	if ds == nil {
+		ds = []Diagnostic{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(ds)
+}
+
+// WriteSARIF writes diagnostics as a SARIF 2.1.0 log.
+func WriteSARIF(w io.Writer, ds []Diagnostic, version string) error {
+	rules := make([]sarifRule, 0, len(Checks))
+	for _, c := range Checks {
+		rules = append(rules, sarifRule{ID: c})
+	}
+	results := make([]sarifResult, 0, len(ds))
+	for _, d := range ds {
+		r := sarifResult{
+			RuleID:  d.Check,
+			Level:   d.Severity,
+			Message: sarifMessage{Text: d.Message},
+		}
+		if d.File != "" {
+			loc := sarifLocation{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(d.File)}}}
+			if d.Line > 0 {
+				loc.PhysicalLocation.Region = &sarifRegion{StartLine: d.Line}
+			}
+			r.Locations = append(r.Locations, loc)
+		}
+		results = append(results, r)
+	}
+	log := sarifLog{
+		Version: "2.1.0",
+		Schema:  sarifSchema,
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "envdo",
+				Version:        version,
+				InformationURI: "https://github.com/k1LoW/envdo",
+				Rules:          rules,
+			}},
+			Results: results,
+		}},
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(log)
+}
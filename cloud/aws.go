@@ -0,0 +1,123 @@
+package cloud
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// awsCredentials are the credentials returned by AWS STS.
+type awsCredentials struct {
+	AccessKeyID     string  `json:"AccessKeyId"`
+	SecretAccessKey string  `json:"SecretAccessKey"`
+	SessionToken    string  `json:"SessionToken"`
+	Expiration      awsTime `json:"Expiration"`
+}
+
+// awsTime is a time in the responses of AWS, in seconds since the epoch (the STS API) or RFC 3339 (the AWS CLI).
+type awsTime struct {
+	time.Time
+}
+
+func (t *awsTime) UnmarshalJSON(b []byte) error {
+	if bytes.HasPrefix(b, []byte(`"`)) {
+		var s string
+		if err := json.Unmarshal(b, &s); err != nil {
+			return err
+		}
+		v, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return err
+		}
+		t.Time = v
+		return nil
+	}
+	f, err := strconv.ParseFloat(string(b), 64)
+	if err != nil {
+		return fmt.Errorf("invalid time: %s", b)
+	}
+	sec, frac := math.Modf(f)
+	t.Time = time.Unix(int64(sec), int64(frac*1e9)).UTC()
+	return nil
+}
+
+// mintAWS assumes the role with the ID token (AssumeRoleWithWebIdentity), or with the AWS CLI (aws sts assume-role).
+func (c *Client) mintAWS(ctx context.Context, opts Options) (*Credentials, error) {
+	if opts.Role == "" {
+		return nil, errors.New("the role ARN is required to assume on AWS")
+	}
+	sessionName := opts.SessionName
+	if sessionName == "" {
+		sessionName = "envdo"
+	}
+	seconds := strconv.Itoa(int(opts.Duration.Seconds()))
+	var creds awsCredentials
+	if opts.IDToken != "" {
+		form := url.Values{}
+		form.Set("Action", "AssumeRoleWithWebIdentity")
+		form.Set("Version", "2011-06-15")
+		form.Set("RoleArn", opts.Role)
+		form.Set("RoleSessionName", sessionName)
+		form.Set("WebIdentityToken", opts.IDToken)
+		form.Set("DurationSeconds", seconds)
+		var res struct {
+			Response struct {
+				Result struct {
+					Credentials awsCredentials `json:"Credentials"`
+				} `json:"AssumeRoleWithWebIdentityResult"`
+			} `json:"AssumeRoleWithWebIdentityResponse"`
+		}
+		endpoint, err := c.awsSTSEndpoint(opts.Role)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.postForm(ctx, endpoint+"/", form, &res); err != nil {
+			return nil, fmt.Errorf("failed to assume %s: %w", opts.Role, err)
+		}
+		creds = res.Response.Result.Credentials
+	} else {
+		out, err := c.run(ctx, "aws", "sts", "assume-role", "--role-arn", opts.Role, "--role-session-name", sessionName, "--duration-seconds", seconds, "--output", "json")
+		if err != nil {
+			return nil, fmt.Errorf("failed to assume %s: %w", opts.Role, err)
+		}
+		var res struct {
+			Credentials awsCredentials `json:"Credentials"`
+		}
+		if err := json.Unmarshal(out, &res); err != nil {
+			return nil, fmt.Errorf("failed to assume %s: %w", opts.Role, err)
+		}
+		creds = res.Credentials
+	}
+	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+		return nil, fmt.Errorf("failed to assume %s: no credentials in the response", opts.Role)
+	}
+	envs := map[string]string{
+		"AWS_ACCESS_KEY_ID":     creds.AccessKeyID,
+		"AWS_SECRET_ACCESS_KEY": creds.SecretAccessKey,
+		"AWS_SESSION_TOKEN":     creds.SessionToken,
+	}
+	if !creds.Expiration.IsZero() {
+		envs["AWS_CREDENTIAL_EXPIRATION"] = creds.Expiration.Format(time.RFC3339)
+	}
+	return &Credentials{Envs: envs, Expiration: creds.Expiration.Time}, nil
+}
+
+// awsSTSEndpoint returns the STS endpoint of the partition of the role ARN (e.g. aws-cn of arn:aws-cn:iam::...).
+func (c *Client) awsSTSEndpoint(role string) (string, error) {
+	parts := strings.SplitN(role, ":", 3)
+	if len(parts) < 3 || parts[0] != "arn" {
+		return "", fmt.Errorf("invalid role ARN: %s", role)
+	}
+	endpoint, ok := c.endpoints.awsSTS[parts[1]]
+	if !ok {
+		return "", fmt.Errorf("unsupported AWS partition %s of %s", parts[1], role)
+	}
+	return endpoint, nil
+}
@@ -0,0 +1,77 @@
+package cloud
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// azureDefaultScope is the default OAuth scope of access tokens on Azure (Azure Resource Manager).
+const azureDefaultScope = "https://management.azure.com/.default"
+
+// mintAzure exchanges the ID token for an access token of the application with the federated credential,
+// or gets the access token with the Azure CLI (az account get-access-token).
+// The token is set to $AZURE_ACCESS_TOKEN, with $AZURE_CLIENT_ID and $AZURE_TENANT_ID if given.
+func (c *Client) mintAzure(ctx context.Context, opts Options) (*Credentials, error) {
+	scope := opts.Scope
+	if scope == "" {
+		scope = azureDefaultScope
+	}
+	var (
+		token      string
+		expiration time.Time
+	)
+	if opts.IDToken != "" {
+		if opts.Role == "" || opts.Tenant == "" {
+			return nil, errors.New("the client ID and the tenant ID are required to exchange the OIDC ID token on Azure")
+		}
+		form := url.Values{}
+		form.Set("client_id", opts.Role)
+		form.Set("scope", scope)
+		form.Set("grant_type", "client_credentials")
+		form.Set("client_assertion_type", "urn:ietf:params:oauth:client-assertion-type:jwt-bearer")
+		form.Set("client_assertion", opts.IDToken)
+		var res struct {
+			AccessToken string `json:"access_token"`
+			ExpiresIn   int    `json:"expires_in"`
+		}
+		if err := c.postForm(ctx, fmt.Sprintf("%s/%s/oauth2/v2.0/token", c.endpoints.azureLogin, url.PathEscape(opts.Tenant)), form, &res); err != nil {
+			return nil, fmt.Errorf("failed to exchange the OIDC ID token for %s: %w", opts.Role, err)
+		}
+		token, expiration = res.AccessToken, time.Now().Add(time.Duration(res.ExpiresIn)*time.Second)
+	} else {
+		args := []string{"account", "get-access-token", "--scope", scope, "--output", "json"}
+		if opts.Tenant != "" {
+			args = append(args, "--tenant", opts.Tenant)
+		}
+		out, err := c.run(ctx, "az", args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the access token: %w", err)
+		}
+		var res struct {
+			AccessToken string `json:"accessToken"`
+			ExpiresOn   int64  `json:"expires_on"`
+		}
+		if err := json.Unmarshal(out, &res); err != nil {
+			return nil, fmt.Errorf("failed to get the access token: %w", err)
+		}
+		token = res.AccessToken
+		if res.ExpiresOn > 0 {
+			expiration = time.Unix(res.ExpiresOn, 0)
+		}
+	}
+	if token == "" {
+		return nil, errors.New("failed to get the access token: empty token")
+	}
+	envs := map[string]string{"AZURE_ACCESS_TOKEN": token}
+	if opts.Role != "" {
+		envs["AZURE_CLIENT_ID"] = opts.Role
+	}
+	if opts.Tenant != "" {
+		envs["AZURE_TENANT_ID"] = opts.Tenant
+	}
+	return &Credentials{Envs: envs, Expiration: expiration}, nil
+}
@@ -0,0 +1,213 @@
+// Package cloud mints short-lived credentials of cloud providers (AWS, Google Cloud and Azure)
+// as environment variables, exchanging OIDC ID tokens (workload identity federation) or using the cloud CLIs.
+package cloud
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/k1LoW/envdo/internal/api"
+)
+
+// Names of clouds.
+const (
+	AWS   = "aws"
+	GCP   = "gcp"
+	Azure = "azure"
+)
+
+// Clouds are the names of the supported clouds.
+var Clouds = []string{AWS, GCP, Azure}
+
+// DefaultDuration is the default lifetime of minted credentials.
+const DefaultDuration = time.Hour
+
+// Credentials are short-lived credentials to be set as environment variables.
+type Credentials struct {
+	// Envs are the environment variables of the credentials (e.g. AWS_ACCESS_KEY_ID).
+	Envs map[string]string
+	// Expiration is the time the credentials expire, or zero if unknown.
+	Expiration time.Time
+}
+
+// Options are the options to mint credentials.
+type Options struct {
+	// Role is the role ARN on AWS, the service account email to impersonate on Google Cloud,
+	// or the client ID of the application on Azure.
+	Role string
+	// IDToken is the OIDC ID token exchanged for the credentials.
+	// If empty, the credentials are minted with the ambient credentials of the cloud CLI (aws, gcloud or az).
+	IDToken string
+	// Duration is the lifetime of the credentials (DefaultDuration if zero). Azure tokens have fixed lifetimes.
+	Duration time.Duration
+	// SessionName is the role session name on AWS ("envdo" if empty).
+	SessionName string
+	// WorkloadIdentityProvider is the full resource name of the workload identity provider on Google Cloud
+	// (projects/NUMBER/locations/global/workloadIdentityPools/POOL/providers/PROVIDER), required with IDToken.
+	WorkloadIdentityProvider string
+	// Tenant is the tenant ID on Azure, required with IDToken.
+	Tenant string
+	// Scope is the OAuth scope of the access token on Google Cloud and Azure
+	// (https://www.googleapis.com/auth/cloud-platform and https://management.azure.com/.default by default).
+	Scope string
+}
+
+// Client mints short-lived credentials.
+type Client struct {
+	client    *http.Client
+	endpoints endpoints
+	run       func(ctx context.Context, name string, args ...string) ([]byte, error)
+}
+
+// endpoints are the endpoints of the APIs minting credentials.
+type endpoints struct {
+	// awsSTS are the STS endpoints by AWS partition, as roles of a partition cannot be assumed
+	// with the endpoints of the others.
+	awsSTS     map[string]string
+	gcpSTS     string
+	gcpIAM     string
+	azureLogin string
+}
+
+// New creates a new Client.
+func New() *Client {
+	return &Client{
+		client: api.NewClient(),
+		endpoints: endpoints{
+			awsSTS: map[string]string{
+				"aws":        "https://sts.amazonaws.com",
+				"aws-cn":     "https://sts.cn-north-1.amazonaws.com.cn",
+				"aws-us-gov": "https://sts.us-gov-west-1.amazonaws.com",
+				"aws-iso":    "https://sts.us-iso-east-1.c2s.ic.gov",
+				"aws-iso-b":  "https://sts.us-isob-east-1.sc2s.sgov.gov",
+			},
+			gcpSTS:     "https://sts.googleapis.com",
+			gcpIAM:     "https://iamcredentials.googleapis.com",
+			azureLogin: "https://login.microsoftonline.com",
+		},
+		run: runCLI,
+	}
+}
+
+// Detect returns the cloud of role: AWS for role ARNs and Google Cloud for service account emails.
+// It returns an empty string for the others, such as client IDs of Azure.
+func Detect(role string) string {
+	switch {
+	case strings.HasPrefix(role, "arn:aws:"), strings.HasPrefix(role, "arn:aws-"):
+		return AWS
+	case strings.HasSuffix(role, ".iam.gserviceaccount.com"):
+		return GCP
+	default:
+		return ""
+	}
+}
+
+// Audience returns the default audience of OIDC ID tokens exchanged on cloud.
+func Audience(cloud string, opts Options) string {
+	switch cloud {
+	case AWS:
+		return "sts.amazonaws.com"
+	case GCP:
+		return "//iam.googleapis.com/" + opts.WorkloadIdentityProvider
+	case Azure:
+		return "api://AzureADTokenExchange"
+	default:
+		return ""
+	}
+}
+
+// Mint mints the credentials of cloud.
+func (c *Client) Mint(ctx context.Context, cloud string, opts Options) (*Credentials, error) {
+	if opts.Duration == 0 {
+		opts.Duration = DefaultDuration
+	}
+	switch cloud {
+	case AWS:
+		return c.mintAWS(ctx, opts)
+	case GCP:
+		return c.mintGCP(ctx, opts)
+	case Azure:
+		return c.mintAzure(ctx, opts)
+	default:
+		return nil, fmt.Errorf("unsupported cloud: %s (available: %s)", cloud, strings.Join(Clouds, ", "))
+	}
+}
+
+// GitHubActionsIDToken returns an OIDC ID token for audience issued by GitHub Actions,
+// which requires the id-token: write permission of the workflow.
+// ok is false outside GitHub Actions or without the permission ($ACTIONS_ID_TOKEN_REQUEST_URL is not set).
+func (c *Client) GitHubActionsIDToken(ctx context.Context, audience string) (token string, ok bool, err error) {
+	rawURL := os.Getenv("ACTIONS_ID_TOKEN_REQUEST_URL")
+	if rawURL == "" {
+		return "", false, nil
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", false, fmt.Errorf("invalid ACTIONS_ID_TOKEN_REQUEST_URL: %w", err)
+	}
+	if audience != "" {
+		q := u.Query()
+		q.Set("audience", audience)
+		u.RawQuery = q.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", false, err
+	}
+	req.Header.Set("Authorization", "Bearer "+os.Getenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN"))
+	var res struct {
+		Value string `json:"value"`
+	}
+	if err := api.DoJSON(c.client, req, &res); err != nil {
+		return "", false, fmt.Errorf("failed to get the OIDC ID token of GitHub Actions: %w", err)
+	}
+	if res.Value == "" {
+		return "", false, errors.New("failed to get the OIDC ID token of GitHub Actions: empty token")
+	}
+	return res.Value, true, nil
+}
+
+// postForm sends a POST request with the form to rawURL and decodes the JSON response into v.
+func (c *Client) postForm(ctx context.Context, rawURL string, form url.Values, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rawURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return api.DoJSON(c.client, req, v)
+}
+
+// postJSON sends a POST request with body encoded as JSON to rawURL with the bearer token if given
+// and decodes the JSON response into v.
+func (c *Client) postJSON(ctx context.Context, rawURL, token string, body, v any) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rawURL, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return api.DoJSON(c.client, req, v)
+}
+
+// runCLI runs the command of a cloud CLI and returns its stdout, suggesting OIDC ID tokens if it is not found.
+func runCLI(ctx context.Context, name string, args ...string) ([]byte, error) {
+	out, err := api.RunCLI(ctx, name, args...)
+	if errors.Is(err, api.ErrCLINotFound) {
+		return nil, fmt.Errorf("%w, or give an OIDC ID token", err)
+	}
+	return out, err
+}
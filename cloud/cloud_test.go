@@ -0,0 +1,226 @@
+package cloud
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestClient(t *testing.T, mux *http.ServeMux) *Client {
+	t.Helper()
+	ts := httptest.NewServer(mux)
+	t.Cleanup(ts.Close)
+	c := New()
+	c.endpoints = endpoints{
+		awsSTS:     map[string]string{"aws": ts.URL, "aws-cn": ts.URL + "/cn", "aws-us-gov": ts.URL + "/us-gov"},
+		gcpSTS:     ts.URL,
+		gcpIAM:     ts.URL,
+		azureLogin: ts.URL,
+	}
+	c.run = func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		t.Fatalf("unexpected command: %s %v", name, args)
+		return nil, nil
+	}
+	return c
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		role string
+		want string
+	}{
+		{"arn:aws:iam::123456789012:role/deploy", AWS},
+		{"arn:aws-cn:iam::123456789012:role/deploy", AWS},
+		{"deploy@my-project.iam.gserviceaccount.com", GCP},
+		{"00000000-0000-0000-0000-000000000000", ""},
+	}
+	for _, tt := range tests {
+		if got := Detect(tt.role); got != tt.want {
+			t.Errorf("Detect(%q) = %q, want %q", tt.role, got, tt.want)
+		}
+	}
+}
+
+func TestMintAWSWithIDToken(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Error(err)
+			return
+		}
+		for k, want := range map[string]string{"Action": "AssumeRoleWithWebIdentity", "RoleArn": "arn:aws:iam::1:role/deploy", "RoleSessionName": "ci", "WebIdentityToken": "id-token", "DurationSeconds": "900"} {
+			if got := r.PostForm.Get(k); got != want {
+				t.Errorf("%s = %q, want %q", k, got, want)
+			}
+		}
+		_, _ = w.Write([]byte(`{"AssumeRoleWithWebIdentityResponse":{"AssumeRoleWithWebIdentityResult":{"Credentials":{"AccessKeyId":"AKIA","SecretAccessKey":"secret","SessionToken":"session","Expiration":1.7356896E9}}}}`))
+	})
+	c := newTestClient(t, mux)
+	creds, err := c.Mint(context.Background(), AWS, Options{Role: "arn:aws:iam::1:role/deploy", IDToken: "id-token", SessionName: "ci", Duration: 15 * time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"AWS_ACCESS_KEY_ID":         "AKIA",
+		"AWS_SECRET_ACCESS_KEY":     "secret",
+		"AWS_SESSION_TOKEN":         "session",
+		"AWS_CREDENTIAL_EXPIRATION": "2025-01-01T00:00:00Z",
+	}
+	assertEnvs(t, creds.Envs, want)
+}
+
+func TestMintAWSPartitions(t *testing.T) {
+	mux := http.NewServeMux()
+	for _, partition := range []string{"cn", "us-gov"} {
+		mux.HandleFunc("/"+partition+"/", func(w http.ResponseWriter, r *http.Request) {
+			if err := r.ParseForm(); err != nil {
+				t.Error(err)
+				return
+			}
+			_, _ = w.Write([]byte(`{"AssumeRoleWithWebIdentityResponse":{"AssumeRoleWithWebIdentityResult":{"Credentials":{"AccessKeyId":"` + partition + `","SecretAccessKey":"secret","SessionToken":"session"}}}}`))
+		})
+	}
+	c := newTestClient(t, mux)
+	for role, want := range map[string]string{
+		"arn:aws-cn:iam::1:role/deploy":     "cn",
+		"arn:aws-us-gov:iam::1:role/deploy": "us-gov",
+	} {
+		creds, err := c.Mint(context.Background(), AWS, Options{Role: role, IDToken: "id-token"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := creds.Envs["AWS_ACCESS_KEY_ID"]; got != want {
+			t.Errorf("%s: got credentials of %q, want %q", role, got, want)
+		}
+	}
+	if _, err := c.Mint(context.Background(), AWS, Options{Role: "arn:aws-unknown:iam::1:role/deploy", IDToken: "id-token"}); err == nil {
+		t.Error("want error for an unknown partition")
+	}
+}
+
+func TestMintAWSWithCLI(t *testing.T) {
+	c := New()
+	c.run = func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		if got := name + " " + strings.Join(args, " "); got != "aws sts assume-role --role-arn arn:aws:iam::1:role/deploy --role-session-name envdo --duration-seconds 3600 --output json" {
+			t.Errorf("unexpected command: %s", got)
+		}
+		return []byte(`{"Credentials":{"AccessKeyId":"AKIA","SecretAccessKey":"secret","SessionToken":"session","Expiration":"2025-01-01T00:00:00+00:00"}}`), nil
+	}
+	creds, err := c.Mint(context.Background(), AWS, Options{Role: "arn:aws:iam::1:role/deploy"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !creds.Expiration.Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expiration = %v", creds.Expiration)
+	}
+	if creds.Envs["AWS_SESSION_TOKEN"] != "session" {
+		t.Errorf("got %v", creds.Envs)
+	}
+}
+
+func TestMintGCP(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/token", func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Error(err)
+			return
+		}
+		if req["audience"] != "//iam.googleapis.com/projects/1/locations/global/workloadIdentityPools/ci/providers/github" || req["subjectToken"] != "id-token" {
+			t.Errorf("unexpected request: %v", req)
+		}
+		_, _ = w.Write([]byte(`{"access_token":"federated","expires_in":3600}`))
+	})
+	mux.HandleFunc("/v1/projects/-/serviceAccounts/deploy@p.iam.gserviceaccount.com:generateAccessToken", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer federated" {
+			t.Errorf("Authorization = %q", got)
+		}
+		_, _ = w.Write([]byte(`{"accessToken":"impersonated","expireTime":"2025-01-01T00:00:00Z"}`))
+	})
+	c := newTestClient(t, mux)
+	opts := Options{Role: "deploy@p.iam.gserviceaccount.com", IDToken: "id-token", WorkloadIdentityProvider: "projects/1/locations/global/workloadIdentityPools/ci/providers/github"}
+	creds, err := c.Mint(context.Background(), GCP, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEnvs(t, creds.Envs, map[string]string{"CLOUDSDK_AUTH_ACCESS_TOKEN": "impersonated", "GOOGLE_OAUTH_ACCESS_TOKEN": "impersonated"})
+
+	opts.Role = ""
+	creds, err = c.Mint(context.Background(), GCP, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEnvs(t, creds.Envs, map[string]string{"CLOUDSDK_AUTH_ACCESS_TOKEN": "federated", "GOOGLE_OAUTH_ACCESS_TOKEN": "federated"})
+
+	if _, err := c.Mint(context.Background(), GCP, Options{IDToken: "id-token"}); err == nil {
+		t.Error("want error without the workload identity provider")
+	}
+}
+
+func TestMintAzure(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/tenant-1/oauth2/v2.0/token", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Error(err)
+			return
+		}
+		if r.PostForm.Get("client_id") != "client-1" || r.PostForm.Get("client_assertion") != "id-token" || r.PostForm.Get("scope") != azureDefaultScope {
+			t.Errorf("unexpected form: %v", r.PostForm)
+		}
+		_, _ = w.Write([]byte(`{"access_token":"azure-token","expires_in":3599}`))
+	})
+	c := newTestClient(t, mux)
+	creds, err := c.Mint(context.Background(), Azure, Options{Role: "client-1", Tenant: "tenant-1", IDToken: "id-token"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEnvs(t, creds.Envs, map[string]string{"AZURE_ACCESS_TOKEN": "azure-token", "AZURE_CLIENT_ID": "client-1", "AZURE_TENANT_ID": "tenant-1"})
+	if _, err := c.Mint(context.Background(), Azure, Options{Role: "client-1", IDToken: "id-token"}); err == nil {
+		t.Error("want error without the tenant")
+	}
+}
+
+func TestGitHubActionsIDToken(t *testing.T) {
+	c := New()
+	t.Setenv("ACTIONS_ID_TOKEN_REQUEST_URL", "")
+	if _, ok, err := c.GitHubActionsIDToken(context.Background(), "sts.amazonaws.com"); ok || err != nil {
+		t.Errorf("ok = %v, err = %v outside GitHub Actions", ok, err)
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer request-token" {
+			t.Errorf("Authorization = %q", got)
+		}
+		if got := r.URL.Query().Get("audience"); got != "sts.amazonaws.com" {
+			t.Errorf("audience = %q", got)
+		}
+		_, _ = w.Write([]byte(`{"value":"id-token"}`))
+	}))
+	t.Cleanup(ts.Close)
+	t.Setenv("ACTIONS_ID_TOKEN_REQUEST_URL", ts.URL+"/token?api-version=2.0")
+	t.Setenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN", "request-token")
+	token, ok, err := c.GitHubActionsIDToken(context.Background(), "sts.amazonaws.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok || token != "id-token" {
+		t.Errorf("got %q, %v", token, ok)
+	}
+}
+
+func assertEnvs(t *testing.T, got, want map[string]string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Errorf("got %v, want %v", got, want)
+		return
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s = %q, want %q", k, got[k], v)
+		}
+	}
+}
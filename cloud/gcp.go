@@ -0,0 +1,81 @@
+package cloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// gcpDefaultScope is the default OAuth scope of access tokens on Google Cloud.
+const gcpDefaultScope = "https://www.googleapis.com/auth/cloud-platform"
+
+// mintGCP exchanges the ID token for a federated access token with the workload identity provider
+// (impersonating the service account if given), or prints the access token with the gcloud CLI.
+// The token is set to $CLOUDSDK_AUTH_ACCESS_TOKEN (gcloud) and $GOOGLE_OAUTH_ACCESS_TOKEN (Terraform and client libraries).
+func (c *Client) mintGCP(ctx context.Context, opts Options) (*Credentials, error) {
+	scope := opts.Scope
+	if scope == "" {
+		scope = gcpDefaultScope
+	}
+	var (
+		token      string
+		expiration time.Time
+	)
+	if opts.IDToken != "" {
+		if opts.WorkloadIdentityProvider == "" {
+			return nil, errors.New("the workload identity provider is required to exchange the OIDC ID token on Google Cloud")
+		}
+		var res struct {
+			AccessToken string `json:"access_token"`
+			ExpiresIn   int    `json:"expires_in"`
+		}
+		if err := c.postJSON(ctx, c.endpoints.gcpSTS+"/v1/token", "", map[string]string{
+			"audience":           Audience(GCP, opts),
+			"grantType":          "urn:ietf:params:oauth:grant-type:token-exchange",
+			"requestedTokenType": "urn:ietf:params:oauth:token-type:access_token",
+			"scope":              scope,
+			"subjectToken":       opts.IDToken,
+			"subjectTokenType":   "urn:ietf:params:oauth:token-type:jwt",
+		}, &res); err != nil {
+			return nil, fmt.Errorf("failed to exchange the OIDC ID token with %s: %w", opts.WorkloadIdentityProvider, err)
+		}
+		token, expiration = res.AccessToken, time.Now().Add(time.Duration(res.ExpiresIn)*time.Second)
+		if opts.Role != "" {
+			var res struct {
+				AccessToken string    `json:"accessToken"`
+				ExpireTime  time.Time `json:"expireTime"`
+			}
+			if err := c.postJSON(ctx, fmt.Sprintf("%s/v1/projects/-/serviceAccounts/%s:generateAccessToken", c.endpoints.gcpIAM, url.PathEscape(opts.Role)), token, map[string]any{
+				"scope":    []string{scope},
+				"lifetime": fmt.Sprintf("%ds", int(opts.Duration.Seconds())),
+			}, &res); err != nil {
+				return nil, fmt.Errorf("failed to impersonate %s: %w", opts.Role, err)
+			}
+			token, expiration = res.AccessToken, res.ExpireTime
+		}
+	} else {
+		// The scope of gcloud is given by its configuration
+		args := []string{"auth", "print-access-token"}
+		if opts.Role != "" {
+			args = append(args, "--impersonate-service-account="+opts.Role)
+		}
+		out, err := c.run(ctx, "gcloud", args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the access token: %w", err)
+		}
+		token = strings.TrimSpace(string(out))
+	}
+	if token == "" {
+		return nil, errors.New("failed to get the access token: empty token")
+	}
+	return &Credentials{
+		Envs: map[string]string{
+			"CLOUDSDK_AUTH_ACCESS_TOKEN": token,
+			"GOOGLE_OAUTH_ACCESS_TOKEN":  token,
+		},
+		Expiration: expiration,
+	}, nil
+}
@@ -0,0 +1,213 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/k1LoW/envdo/config"
+	"github.com/k1LoW/envdo/env"
+	"github.com/k1LoW/envdo/trust"
+	"github.com/spf13/cobra"
+)
+
+// allowCmd represents the allow command.
+var allowCmd = &cobra.Command{
+	Use:   "allow [FILE...]",
+	Short: "Allow the shell hook to load the .env files of the directory",
+	Long: `Allow the shell hook (envdo hook) to load the .env files and ` + config.Filename + ` of the nearest directory
+containing them, from the current directory up. Review the files before allowing them.
+
+The command substitutions ($(command) values) of the allowed files are run without --allow-exec.
+With --profile, the .env files of the profile loaded in the current directory are allowed instead,
+and with FILE arguments, the files. The files are allowed with their current contents,
+so they must be allowed again after they change.
+
+Examples:
+  envdo allow
+  envdo allow -p staging
+  envdo allow .env.local`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return updateTrust(true, args)
+	},
+}
+
+// denyCmd represents the deny command.
+var denyCmd = &cobra.Command{
+	Use:   "deny [FILE...]",
+	Short: "Revoke the permission of the shell hook to load the .env files of the directory",
+	Long: `Revoke the permission of the shell hook (envdo hook) to load the .env files of the directory
+and to run their command substitutions, allowed with envdo allow.
+With --profile, the permission of the .env files of the profile is revoked instead, and with FILE arguments,
+that of the files.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return updateTrust(false, args)
+	},
+}
+
+// updateTrust allows or denies args, the .env files of the profile given by --profile or, if neither is given,
+// the files loaded by the shell hook in the nearest hook directory.
+func updateTrust(allow bool, args []string) error {
+	files, err := trustFiles(args)
+	if err != nil {
+		return err
+	}
+	s, err := trust.Open(trust.DefaultPath())
+	if err != nil {
+		return fmt.Errorf("failed to open the allowed files: %w", err)
+	}
+	for _, f := range files {
+		if allow {
+			err = s.Allow(f)
+		} else {
+			err = s.Deny(f)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if err := s.Save(); err != nil {
+		return fmt.Errorf("failed to save the allowed files: %w", err)
+	}
+	verb := "Allowed"
+	if !allow {
+		verb = "Denied"
+	}
+	logger.Info(fmt.Sprintf("%s %s", verb, strings.Join(files, ", ")))
+	return nil
+}
+
+// trustFiles returns the files allowed or denied by envdo allow and envdo deny with args.
+// The .env files of a profile are resolved like loading them in the current directory, except remote ones.
+func trustFiles(args []string) ([]string, error) {
+	if len(args) > 0 {
+		if profile != "" {
+			return nil, errors.New("FILE arguments and --profile cannot be used together")
+		}
+		files := make([]string, 0, len(args))
+		for _, f := range args {
+			abs, err := filepath.Abs(f)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, abs)
+		}
+		return files, nil
+	}
+	if profile != "" {
+		e, err := newEnv()
+		if err != nil {
+			return nil, err
+		}
+		var files []string
+		for _, f := range e.Files(profile) {
+			if !env.IsRemote(f) {
+				files = append(files, f)
+			}
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf("no .env files of profile %s found", profile)
+		}
+		return files, nil
+	}
+	pwd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	dir := findHookDir(pwd)
+	if dir == "" {
+		return nil, fmt.Errorf("no .env files or %s found in %s or its parents", config.Filename, pwd)
+	}
+	if err := os.Chdir(dir); err != nil {
+		return nil, err
+	}
+	_, files, err := hookFiles()
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no .env files to load found in %s", dir)
+	}
+	return files, nil
+}
+
+// findHookDir returns the nearest directory from dir up containing a default .env file or .envdo.yml,
+// or an empty string if none is found.
+func findHookDir(dir string) string {
+	for {
+		for _, name := range []string{env.Filename(""), config.Filename} {
+			if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+				return dir
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// hookFiles returns the profile loaded by the shell hook in the current directory (profile of .envdo.yml)
+// and the files that must be allowed to load it: the .env files outside $XDG_CONFIG_HOME/envdo and .envdo.yml.
+// It returns no files if no .env file of the profile is found outside $XDG_CONFIG_HOME/envdo.
+func hookFiles() (string, []string, error) {
+	c, err := loadConfig()
+	if err != nil {
+		return "", nil, err
+	}
+	e, err := newEnv()
+	if err != nil {
+		return "", nil, err
+	}
+	global := envdoConfigDir() + string(filepath.Separator)
+	var files []string
+	for _, f := range e.Files(c.Profile) {
+		if env.IsRemote(f) || strings.HasPrefix(f, global) {
+			continue
+		}
+		files = append(files, f)
+	}
+	if len(files) == 0 {
+		return c.Profile, nil, nil
+	}
+	if _, err := os.Stat(config.Filename); err == nil {
+		abs, err := filepath.Abs(config.Filename)
+		if err != nil {
+			return "", nil, err
+		}
+		files = append(files, abs)
+	}
+	return c.Profile, files, nil
+}
+
+func init() {
+	rootCmd.AddCommand(allowCmd)
+	rootCmd.AddCommand(denyCmd)
+	for _, c := range []*cobra.Command{allowCmd, denyCmd} {
+		c.Flags().StringVarP(&profile, "profile", "p", "", "profile name whose .env files are allowed or denied")
+	}
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestAllowProfile(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("sh is not available")
+	}
+	for _, k := range []string{"XDG_CONFIG_HOME", "XDG_STATE_HOME", "XDG_CACHE_HOME"} {
+		t.Setenv(k, t.TempDir())
+	}
+	t.Cleanup(func() {
+		profile = ""
+	})
+	dir := t.TempDir()
+	t.Chdir(dir)
+	path := filepath.Join(dir, ".env.staging")
+	if err := os.WriteFile(path, []byte("V=$(echo staging)\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := loadEnvs("staging"); err == nil || !strings.Contains(err.Error(), path) {
+		t.Fatalf("got %v, want error naming %s", err, path)
+	}
+
+	profile = "staging"
+	if err := updateTrust(true, nil); err != nil {
+		t.Fatal(err)
+	}
+	envs, _, err := loadEnvs("staging")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := envs["V"]; got != "staging" {
+		t.Errorf("got V=%q, want %q", got, "staging")
+	}
+
+	profile = ""
+	if err := updateTrust(false, []string{".env.staging"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := loadEnvs("staging"); err == nil {
+		t.Error("got nil, want error after deny")
+	}
+}
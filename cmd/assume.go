@@ -0,0 +1,166 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/k1LoW/envdo/cloud"
+	"github.com/k1LoW/envdo/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	assumeRole      string
+	assumeCloud     string
+	assumeDuration  time.Duration
+	assumeSession   string
+	assumeProvider  string
+	assumeTenant    string
+	assumeScope     string
+	assumeAudience  string
+	assumeTokenFile string
+)
+
+// assumeCmd represents the assume command.
+var assumeCmd = &cobra.Command{
+	Use:   "assume --role ROLE [flags] [-- COMMAND [ARG...]]",
+	Short: "Execute a command with short-lived cloud credentials",
+	Long: `Mint short-lived credentials of AWS, Google Cloud or Azure and execute a command with them
+set as the standard environment variables, in addition to the variables of the .env files of the profile.
+Without a command, the credentials are printed as export statements.
+
+The OIDC ID token of --token-file, or of GitHub Actions (with the id-token: write permission), is exchanged
+for the credentials with workload identity federation. Without an ID token, the ambient credentials
+of the cloud CLI (aws, gcloud or az) are used.
+
+  aws    assumes the role ARN of --role and sets AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY,
+         AWS_SESSION_TOKEN and AWS_CREDENTIAL_EXPIRATION.
+  gcp    gets an access token of the workload identity provider of --workload-identity-provider,
+         impersonating the service account of --role if given, and sets CLOUDSDK_AUTH_ACCESS_TOKEN
+         and GOOGLE_OAUTH_ACCESS_TOKEN.
+  azure  gets an access token of the application (client ID) of --role in the tenant of --tenant
+         and sets AZURE_ACCESS_TOKEN, AZURE_CLIENT_ID and AZURE_TENANT_ID.
+
+--cloud is detected from role ARNs (aws) and service account emails (gcp).
+
+Examples:
+  envdo assume --role arn:aws:iam::123456789012:role/deploy -- terraform apply
+  envdo assume --cloud gcp --workload-identity-provider projects/123/locations/global/workloadIdentityPools/ci/providers/github --role deploy@my-project.iam.gserviceaccount.com -- gcloud run deploy
+  envdo assume --cloud azure --role 00000000-0000-0000-0000-000000000000 --tenant 11111111-1111-1111-1111-111111111111 -- ./deploy.sh
+  eval "$(envdo assume --role arn:aws:iam::123456789012:role/readonly)"`,
+	Args: cobra.ArbitraryArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := assumeCloud
+		if c == "" {
+			c = cloud.Detect(assumeRole)
+		}
+		if c == "" {
+			return errors.New("--cloud is required unless --role is a role ARN of AWS or a service account email of Google Cloud")
+		}
+		opts := cloud.Options{
+			Role:                     assumeRole,
+			Duration:                 assumeDuration,
+			SessionName:              assumeSession,
+			WorkloadIdentityProvider: assumeProvider,
+			Tenant:                   assumeTenant,
+			Scope:                    assumeScope,
+		}
+		client := cloud.New()
+		token, err := oidcToken(cmd, client, c, opts)
+		if err != nil {
+			return err
+		}
+		opts.IDToken = token
+		creds, err := client.Mint(cmd.Context(), c, opts)
+		if err != nil {
+			return err
+		}
+		if !creds.Expiration.IsZero() {
+			logger.Info(fmt.Sprintf("Minted the credentials of %s valid until %s", c, creds.Expiration.Local().Format(time.DateTime)))
+		}
+		if len(args) == 0 {
+			return output.Write(os.Stdout, output.FormatExport, creds.Envs, output.Options{Shell: shell})
+		}
+
+		if err := checkAllowedCommand(profile, args); err != nil {
+			return err
+		}
+		envs, order, err := loadEnvs(profile)
+		if err != nil {
+			return err
+		}
+		envs, order, err = transformKeys(envs, order)
+		if err != nil {
+			return err
+		}
+		maps.Copy(envs, creds.Envs)
+		if err := recordAuditLog(profile, args, envs); err != nil {
+			return err
+		}
+		return runCommand(cmd, args, envs, order)
+	},
+}
+
+// oidcToken returns the OIDC ID token of --token-file or GitHub Actions, or an empty string if neither is available.
+func oidcToken(cmd *cobra.Command, client *cloud.Client, c string, opts cloud.Options) (string, error) {
+	if assumeTokenFile != "" {
+		b, err := os.ReadFile(assumeTokenFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read the OIDC ID token: %w", err)
+		}
+		return strings.TrimSpace(string(b)), nil
+	}
+	audience := assumeAudience
+	if audience == "" {
+		audience = cloud.Audience(c, opts)
+	}
+	token, ok, err := client.GitHubActionsIDToken(cmd.Context(), audience)
+	if err != nil {
+		return "", err
+	}
+	if ok {
+		logger.Debug("got the OIDC ID token of GitHub Actions", "audience", audience)
+	}
+	return token, nil
+}
+
+func init() {
+	rootCmd.AddCommand(assumeCmd)
+	assumeCmd.Flags().StringVar(&assumeRole, "role", "", "role ARN (aws), service account email to impersonate (gcp) or client ID of the application (azure)")
+	assumeCmd.Flags().StringVar(&assumeCloud, "cloud", "", "cloud of the credentials ("+strings.Join(cloud.Clouds, ", ")+") (detected from --role for aws and gcp)")
+	assumeCmd.Flags().DurationVar(&assumeDuration, "duration", cloud.DefaultDuration, "lifetime of the credentials (aws, gcp)")
+	assumeCmd.Flags().StringVar(&assumeSession, "session-name", "envdo", "role session name (aws)")
+	assumeCmd.Flags().StringVar(&assumeProvider, "workload-identity-provider", "", "full resource name of the workload identity provider exchanging the OIDC ID token (gcp)")
+	assumeCmd.Flags().StringVar(&assumeTenant, "tenant", "", "tenant ID (azure)")
+	assumeCmd.Flags().StringVar(&assumeScope, "scope", "", "OAuth scope of the access token (gcp and azure; default cloud-platform and Azure Resource Manager)")
+	assumeCmd.Flags().StringVar(&assumeAudience, "audience", "", "audience of the OIDC ID token requested from GitHub Actions (default the audience expected by the cloud)")
+	assumeCmd.Flags().StringVar(&assumeTokenFile, "token-file", "", "file of the OIDC ID token to exchange (default the token of GitHub Actions if available, else the cloud CLI is used)")
+	assumeCmd.Flags().StringVar(&shell, "shell", output.DefaultShell, "shell of the export statements printed without a command ("+strings.Join(output.Shells, ", ")+")")
+	assumeCmd.Flags().StringVarP(&profile, "profile", "p", "", "profile name")
+	addRunFlags(assumeCmd)
+}
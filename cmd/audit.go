@@ -0,0 +1,68 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/k1LoW/envdo/audit"
+	"github.com/spf13/cobra"
+)
+
+var (
+	auditSrcs     []string
+	auditPatterns []string
+)
+
+// auditCmd represents the audit command.
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Audit environment variables against source code",
+	Long: `Audit environment variables of a profile against references in source code.
+
+References are detected by per-language patterns such as os.Getenv("KEY") (Go),
+process.env.KEY (JavaScript/TypeScript), os.environ["KEY"] (Python) and ENV["KEY"] (Ruby).
+Additional patterns can be given with --pattern EXT=REGEXP, where REGEXP has one capturing group for the key.`,
+}
+
+func init() {
+	rootCmd.AddCommand(auditCmd)
+	auditCmd.PersistentFlags().StringVarP(&profile, "profile", "p", "", "profile name")
+	auditCmd.PersistentFlags().StringSliceVar(&auditSrcs, "src", []string{"./..."}, "source paths to scan")
+	auditCmd.PersistentFlags().StringArrayVar(&auditPatterns, "pattern", nil, "additional lookup pattern (EXT=REGEXP)")
+}
+
+// scanReferences scans the source paths for references to environment variables.
+func scanReferences() ([]audit.Reference, error) {
+	s := audit.NewScanner()
+	for _, p := range auditPatterns {
+		ext, expr, ok := strings.Cut(p, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid pattern %q: must be EXT=REGEXP", p)
+		}
+		if err := s.AddPattern(ext, expr); err != nil {
+			return nil, err
+		}
+	}
+	return s.Scan(auditSrcs...)
+}
@@ -0,0 +1,77 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/k1LoW/envdo/audit"
+	"github.com/k1LoW/envdo/schema"
+	"github.com/spf13/cobra"
+)
+
+var auditIgnores []string
+
+// auditMissingCmd represents the audit missing command.
+var auditMissingCmd = &cobra.Command{
+	Use:   "missing",
+	Short: "Report variables referenced in source code but not defined",
+	Long: `Report variables referenced in source code but defined neither in a profile nor in the schema,
+with the file:line locations of the references.
+
+Well-known system variables such as HOME and PATH are not reported.
+
+Examples:
+  envdo audit missing --src ./...
+  envdo audit missing -p production --ignore NODE_ENV`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		envs, _, err := loadEnvs(profile)
+		if err != nil {
+			return err
+		}
+		s, err := loadSchema()
+		if err != nil {
+			return fmt.Errorf("failed to load schema: %w", err)
+		}
+		refs, err := scanReferences()
+		if err != nil {
+			return fmt.Errorf("failed to scan source code: %w", err)
+		}
+		missing := audit.Missing(envs, s, refs, auditIgnores)
+		keys := make(map[string]struct{})
+		for _, r := range missing {
+			fmt.Printf("%s:%d: %s\n", r.File, r.Line, r.Key)
+			keys[r.Key] = struct{}{}
+		}
+		if len(keys) > 0 {
+			return fmt.Errorf("found %d missing variables", len(keys))
+		}
+		return nil
+	},
+}
+
+func init() {
+	auditCmd.AddCommand(auditMissingCmd)
+	auditMissingCmd.Flags().StringSliceVar(&auditIgnores, "ignore", nil, "keys to ignore")
+	auditMissingCmd.Flags().StringVar(&schemaPath, "schema", "", "schema file path (default "+schema.Filename+" in the current directory)")
+}
@@ -0,0 +1,64 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/k1LoW/envdo/audit"
+	"github.com/spf13/cobra"
+)
+
+// auditUnusedCmd represents the audit unused command.
+var auditUnusedCmd = &cobra.Command{
+	Use:   "unused",
+	Short: "Report variables not referenced in source code",
+	Long: `Report variables defined in a profile that no source code references.
+
+Examples:
+  envdo audit unused --src ./...
+  envdo audit unused -p production --src ./cmd/... --src ./web/...
+  envdo audit unused --pattern 'ex=System\.get_env\("(\w+)"\)'`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		envs, _, err := loadEnvs(profile)
+		if err != nil {
+			return err
+		}
+		refs, err := scanReferences()
+		if err != nil {
+			return fmt.Errorf("failed to scan source code: %w", err)
+		}
+		unused := audit.Unused(envs, refs)
+		for _, key := range unused {
+			fmt.Println(key)
+		}
+		if len(unused) > 0 {
+			return fmt.Errorf("found %d unused variables", len(unused))
+		}
+		return nil
+	},
+}
+
+func init() {
+	auditCmd.AddCommand(auditUnusedCmd)
+}
@@ -0,0 +1,107 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/k1LoW/envdo/check"
+	"github.com/spf13/cobra"
+)
+
+var (
+	examplePath   string
+	exampleFormat string
+	exampleStrict bool
+)
+
+// checkExampleCmd represents the check-example command.
+var checkExampleCmd = &cobra.Command{
+	Use:   "check-example",
+	Short: "Check that the environment variables are in sync with .env.example",
+	Long: `Compare the keys of the resolved environment variables of a profile with the example .env file
+(.env.example, .env.sample, .env.template or .env.dist in the current directory, or --example).
+
+Keys in the example but not defined locally are errors, and keys defined locally but not documented
+in the example are warnings. It exits with 1 if errors (or warnings with --strict) are found.
+
+Examples:
+  envdo check-example
+  envdo check-example -p production --example config/env.example`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		switch exampleFormat {
+		case "text", "json":
+		default:
+			return fmt.Errorf("unsupported format: %s", exampleFormat)
+		}
+		example := examplePath
+		if example == "" {
+			pwd, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+			if example = check.FindExample(pwd); example == "" {
+				return errors.New("no example file found: create .env.example or give --example")
+			}
+		}
+		e, err := newEnv()
+		if err != nil {
+			return err
+		}
+		envs, err := e.LoadEnvFiles(profile)
+		if err != nil {
+			return fmt.Errorf("failed to load environment variables: %w", err)
+		}
+		envs, _, err = transformKeys(envs, nil)
+		if err != nil {
+			return err
+		}
+		ds, err := check.ExampleKeys(example, envs)
+		if err != nil {
+			return err
+		}
+		if exampleFormat == "json" {
+			err = check.WriteJSON(os.Stdout, ds)
+		} else {
+			err = check.WriteText(os.Stdout, ds)
+		}
+		if err != nil {
+			return err
+		}
+		if check.Failed(ds, exampleStrict) {
+			return fmt.Errorf("the environment variables are out of sync with %s", example)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(checkExampleCmd)
+	checkExampleCmd.Flags().StringVarP(&profile, "profile", "p", "", "profile name")
+	checkExampleCmd.Flags().StringVar(&baseProfile, "base", "", "base profile to layer the profile on (.env for the default .env file)")
+	checkExampleCmd.Flags().StringVar(&examplePath, "example", "", "example .env file path (default .env.example, .env.sample, .env.template or .env.dist in the current directory)")
+	checkExampleCmd.Flags().StringVar(&exampleFormat, "format", "text", "output format (text, json)")
+	checkExampleCmd.Flags().BoolVar(&exampleStrict, "strict", false, "fail on keys not documented in the example too")
+}
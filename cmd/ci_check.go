@@ -0,0 +1,175 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/k1LoW/envdo/check"
+	"github.com/k1LoW/envdo/env"
+	"github.com/k1LoW/envdo/schema"
+	"github.com/k1LoW/envdo/version"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes of ci-check.
+const (
+	ciCheckExitFailed = 1
+	ciCheckExitError  = 2
+)
+
+var (
+	ciCheckFormat string
+	ciCheckStrict bool
+)
+
+// ciCheckCmd represents the ci-check command.
+var ciCheckCmd = &cobra.Command{
+	Use:   "ci-check",
+	Short: "Run all hygiene checks of environment variables for CI",
+	Long: `Run all hygiene checks of environment variables of a profile for CI.
+
+Checks:
+  lint        syntax and key names of the .env files
+  validate    values against the types defined in the schema
+  schema      keys not defined in the schema (only if the schema defines keys)
+  permission  .env files accessible by other users
+  gitignore   .env files in the current directory not ignored by git
+  expiry      keys past or near their expiry dates (# envdo:expires YYYY-MM-DD)
+  example     keys missing from or not documented in .env.example (only if it exists)
+
+Exit codes:
+  0  no errors were found (warnings are allowed unless --strict is given)
+  1  errors (or warnings with --strict) were found
+  2  checks could not be run
+
+Examples:
+  envdo ci-check
+  envdo ci-check -p production --format sarif > envdo.sarif`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ds, err := runChecks()
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return exitWithCode(cmd, ciCheckExitError)
+		}
+		switch ciCheckFormat {
+		case "json":
+			err = check.WriteJSON(os.Stdout, ds)
+		case "sarif":
+			err = check.WriteSARIF(os.Stdout, ds, version.Version)
+		default:
+			err = check.WriteText(os.Stdout, ds)
+		}
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return exitWithCode(cmd, ciCheckExitError)
+		}
+		if check.Failed(ds, ciCheckStrict) {
+			return exitWithCode(cmd, ciCheckExitFailed)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(ciCheckCmd)
+	ciCheckCmd.Flags().StringVarP(&profile, "profile", "p", "", "profile name")
+	ciCheckCmd.Flags().StringVar(&schemaPath, "schema", "", "schema file path (default "+schema.Filename+" in the current directory)")
+	ciCheckCmd.Flags().StringVar(&ciCheckFormat, "format", "text", "output format (text, json, sarif)")
+	ciCheckCmd.Flags().BoolVar(&ciCheckStrict, "strict", false, "treat warnings as failures")
+}
+
+// runChecks runs all checks for the profile.
+func runChecks() ([]check.Diagnostic, error) {
+	switch ciCheckFormat {
+	case "text", "json", "sarif":
+	default:
+		return nil, fmt.Errorf("unsupported format: %s", ciCheckFormat)
+	}
+	pwd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	e, err := newEnv()
+	if err != nil {
+		return nil, err
+	}
+	files := e.Files(profile)
+	envs, err := e.LoadEnvFiles(profile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load environment variables: %w", err)
+	}
+
+	var ds []check.Diagnostic
+	lint, err := check.LintFiles(files)
+	if err != nil {
+		return nil, err
+	}
+	ds = append(ds, lint...)
+
+	s, err := loadSchema()
+	if err != nil {
+		ds = append(ds, check.Diagnostic{Check: check.Schema, Issue: env.Issue{Severity: env.SeverityError, Message: err.Error()}})
+	} else {
+		ds = append(ds, check.ValidateValues(envs, s)...)
+		ds = append(ds, check.SchemaCoverage(envs, s)...)
+	}
+	c, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	required, err := check.RequiredKeys(files, envs, s, c.Required)
+	if err != nil {
+		return nil, err
+	}
+	ds = append(ds, required...)
+
+	perms, err := check.Permissions(files)
+	if err != nil {
+		return nil, err
+	}
+	ds = append(ds, perms...)
+
+	ignored, err := check.Gitignored(pwd)
+	if err != nil {
+		return nil, err
+	}
+	ds = append(ds, ignored...)
+
+	expiry, err := check.ExpiryDates(files, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	ds = append(ds, expiry...)
+
+	if example := check.FindExample(pwd); example != "" {
+		examples, err := check.ExampleKeys(example, envs)
+		if err != nil {
+			return nil, err
+		}
+		ds = append(ds, examples...)
+	}
+	return ds, nil
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCICheckExitCode(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		format  string
+		strict  bool
+		want    int
+	}{
+		{"no errors", "A=a\n", "json", false, 0},
+		{"warnings", "lower=a\n", "json", false, 0},
+		{"warnings with --strict", "lower=a\n", "json", true, ciCheckExitFailed},
+		{"unsupported format", "A=a\n", "xml", false, ciCheckExitError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+			t.Setenv("XDG_CACHE_HOME", t.TempDir())
+			t.Cleanup(func() {
+				ciCheckFormat = "text"
+				ciCheckStrict = false
+			})
+			dir := t.TempDir()
+			t.Chdir(dir)
+			if err := os.WriteFile(filepath.Join(dir, ".env"), []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			ciCheckFormat = tt.format
+			ciCheckStrict = tt.strict
+			err := ciCheckCmd.RunE(ciCheckCmd, nil)
+			wantExitCode(t, err, tt.want)
+		})
+	}
+}
@@ -0,0 +1,384 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	osexec "os/exec"
+	"os/signal"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/k1LoW/envdo/config"
+	"github.com/k1LoW/envdo/env"
+	"github.com/k1LoW/envdo/output"
+	"github.com/k1LoW/exec"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes when the command cannot be executed, following shell conventions.
+const (
+	exitTimeout              = 124
+	exitCommandNotExecutable = 126
+	exitCommandNotFound      = 127
+)
+
+var (
+	expandArgs bool
+	force      bool
+	timeout    time.Duration
+	killAfter  time.Duration
+
+	retries          int
+	retryDelay       time.Duration
+	retryOnExitCodes []int
+	killChildren     bool
+	redact           bool
+	redactMinLength  int
+)
+
+var percentVarRe = regexp.MustCompile(`%([A-Za-z_][A-Za-z0-9_]*)%`)
+
+// runCommand executes args with envs added to the environment of envdo in order.
+// It makes envdo exit with the exit code of the command if the command fails (128+N if it is killed by the signal N),
+// with exitTimeout if the command does not finish within --timeout, or with 128+N if envdo receives the signal N,
+// which is forwarded to the command.
+// Failures are retried up to --retries times, doubling --retry-delay after each attempt, unless envdo receives a signal.
+func runCommand(cmd *cobra.Command, args []string, envs map[string]string, order []string) error {
+	if retries < 0 {
+		return fmt.Errorf("invalid --retries %d: must not be negative", retries)
+	}
+	cmd.SilenceErrors = true
+	delay := retryDelay
+	for attempt := 1; ; attempt++ {
+		code, signaled, err := runOnce(args, envs, order)
+		if err != nil {
+			if exitErr := startError(cmd, args[0], err); exitErr != nil {
+				return exitErr
+			}
+			_, _ = fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return err
+		}
+		if code == 0 {
+			return nil
+		}
+		if signaled || attempt > retries || (len(retryOnExitCodes) > 0 && !slices.Contains(retryOnExitCodes, code)) {
+			return exitWithCode(cmd, code)
+		}
+		logger.Warn(fmt.Sprintf("%s exited with %d, retrying in %s (%d/%d)", args[0], code, delay, attempt, retries))
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// runOnce executes args with envs added in order once, and returns the exit code of the command and whether
+// envdo received a signal forwarded to the command. The exit code is 128+N if envdo received the signal N.
+// It returns an error if the command cannot be started.
+func runOnce(args []string, envs map[string]string, order []string) (int, bool, error) {
+	c := commandWithEnvs(args, envs, order)
+	if err := checkEnviron(c); err != nil {
+		return 0, false, err
+	}
+	if redact {
+		flush, err := redactOutput(c, envs)
+		if err != nil {
+			return 0, false, err
+		}
+		defer flush()
+	}
+	res, err := runWithTimeout(c)
+	switch {
+	case res.timedOut:
+		_, _ = fmt.Fprintf(os.Stderr, "Error: command timed out after %s: %s\n", timeout, args[0])
+		return exitTimeout, false, nil
+	case res.signal != nil:
+		return signalExitCode(res.signal), true, nil
+	}
+	if err != nil {
+		var exitError *exec.ExitError
+		if errors.As(err, &exitError) {
+			return exitCode(args[0], exitError), false, nil
+		}
+		return 0, false, err
+	}
+	return 0, false, nil
+}
+
+// redactOutput makes the stdout and stderr of c redact the values of the secret keys of envs
+// (classified like --mask, with mask_patterns of .envdo.yml and --mask-pattern) of at least --redact-min-length bytes.
+// The returned function writes the output held as the possible beginning of a secret.
+func redactOutput(c *osexec.Cmd, envs map[string]string) (func(), error) {
+	s, err := loadSchema()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load schema: %w", err)
+	}
+	conf, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	m, err := output.NewMasker(s, slices.Concat(conf.MaskPatterns, maskPatterns))
+	if err != nil {
+		return nil, err
+	}
+	secrets := make(map[string]string)
+	for k, v := range envs {
+		if m.IsMasked(k) {
+			secrets[k] = v
+		}
+	}
+	stdout := output.NewRedactor(os.Stdout, secrets, redactMinLength)
+	stderr := output.NewRedactor(os.Stderr, secrets, redactMinLength)
+	c.Stdout = stdout
+	c.Stderr = stderr
+	return func() {
+		_ = stdout.Flush()
+		_ = stderr.Flush()
+	}, nil
+}
+
+// exitCode returns the exit status of the command name like a shell, 128+N if it was killed by the signal N,
+// reporting the signal unless it is SIGINT or SIGPIPE, which a shell does not report either.
+func exitCode(name string, exitError *exec.ExitError) int {
+	code, sig := exitStatus(exitError)
+	if sig != 0 && sig != syscall.SIGINT && sig != syscall.SIGPIPE {
+		logger.Warn(fmt.Sprintf("%s was terminated by signal %d (%s)", name, int(sig), sig))
+	}
+	return code
+}
+
+// signalExitCode returns the exit code of envdo terminated by sig like a shell, 128+N for the signal N.
+func signalExitCode(sig os.Signal) int {
+	if s, ok := sig.(syscall.Signal); ok {
+		return 128 + int(s)
+	}
+	return 1
+}
+
+// waitResult is the result of waiting for a command.
+type waitResult struct {
+	// timedOut is true if the command was terminated at the deadline.
+	timedOut bool
+	// signal is the first signal sent to envdo and forwarded to the command, or nil.
+	signal os.Signal
+}
+
+// runWithTimeout runs c under the deadline of --timeout, if given. When the deadline is exceeded,
+// the command is terminated with SIGTERM and killed if it does not exit within --kill-after.
+func runWithTimeout(c *osexec.Cmd) (waitResult, error) {
+	if err := c.Start(); err != nil {
+		return waitResult{}, err
+	}
+	var deadline <-chan time.Time
+	if timeout > 0 {
+		t := time.NewTimer(timeout)
+		defer t.Stop()
+		deadline = t.C
+	}
+	return waitChild(c, deadline)
+}
+
+// waitChild waits for the started command c until it exits or the deadline is exceeded.
+// Termination signals sent to envdo are forwarded to the command, and to its whole process group
+// (process tree on Windows) with --kill-children, so that no grandchildren are left behind.
+// It returns whether the command timed out and the first signal forwarded to it.
+func waitChild(c *osexec.Cmd, deadline <-chan time.Time) (waitResult, error) {
+	done := make(chan error, 1)
+	go func() {
+		done <- c.Wait()
+	}()
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, forwardSignals...)
+	defer signal.Stop(sigCh)
+	var res waitResult
+	for {
+		select {
+		case err := <-done:
+			if res.signal != nil && killChildren {
+				// Kill the grandchildren left in the process group after the command exited
+				_ = exec.KillCommand(c)
+			}
+			return res, err
+		case sig := <-sigCh:
+			if res.signal == nil {
+				res.signal = sig
+			}
+			logger.Debug("forwarding a signal to the command", "signal", sig)
+			forwardSignal(c, sig)
+		case <-deadline:
+			_ = exec.TerminateCommand(c, syscall.SIGTERM)
+			select {
+			case <-done:
+			case <-time.After(killAfter):
+				_ = exec.KillCommand(c)
+				<-done
+			}
+			res.timedOut = true
+			return res, nil
+		}
+	}
+}
+
+// forwardSignal sends sig to the process group of c with --kill-children, or to c only.
+func forwardSignal(c *osexec.Cmd, sig os.Signal) {
+	if killChildren {
+		_ = exec.TerminateCommand(c, sig)
+		return
+	}
+	if err := c.Process.Signal(sig); err != nil {
+		_ = c.Process.Kill()
+	}
+}
+
+// checkAllowedCommand returns an error if allowed_commands of the configuration does not allow the profile
+// to be used with the command of args, unless --force is given.
+func checkAllowedCommand(profile string, args []string) error {
+	if force {
+		return nil
+	}
+	c, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	if c.CommandAllowed(profile, args[0]) {
+		return nil
+	}
+	name := profile
+	if name == "" {
+		name = env.DefaultProfileName
+	}
+	return fmt.Errorf("profile %s is not allowed to be used with %s (allowed_commands of %s: %s); use --force to run it anyway",
+		name, filepath.Base(args[0]), c.AllowedCommandsFile(name), strings.Join(c.AllowedCommands[name], ", "))
+}
+
+// startError returns the error making envdo exit with 127 if the command name is not found,
+// or 126 if it is not executable. Otherwise it returns nil.
+func startError(cmd *cobra.Command, name string, err error) error {
+	switch {
+	case errors.Is(err, osexec.ErrNotFound), errors.Is(err, fs.ErrNotExist):
+		_, _ = fmt.Fprintf(os.Stderr, "Error: command not found: %s\n", name)
+		return exitWithCode(cmd, exitCommandNotFound)
+	case errors.Is(err, fs.ErrPermission):
+		_, _ = fmt.Fprintf(os.Stderr, "Error: permission denied: %s\n", name)
+		return exitWithCode(cmd, exitCommandNotExecutable)
+	}
+	return nil
+}
+
+// checkEnviron fails if the arguments and environment of c exceed the limits of the OS,
+// instead of starting c failing with E2BIG (argument list too long), and warns if they are close to them.
+func checkEnviron(c *osexec.Cmd) error {
+	var errs []error
+	for _, i := range env.CheckEnviron(c.Env, c.Args) {
+		if i.Severity == env.SeverityError {
+			errs = append(errs, errors.New(i.Message))
+			continue
+		}
+		logger.Warn(i.Message)
+	}
+	return errors.Join(errs...)
+}
+
+// commandWithEnvs returns the command to execute args with envs added to the environment of envdo.
+// The keys in unsetKeys are removed from the environment and envs are added in order.
+func commandWithEnvs(args []string, envs map[string]string, order []string) *osexec.Cmd {
+	// Prepare environment for command execution
+	cmdEnvs := slices.DeleteFunc(os.Environ(), func(kv string) bool {
+		k, _, _ := strings.Cut(kv, "=")
+		return slices.ContainsFunc(unsetKeys, func(u string) bool { return sameEnvKey(k, u) })
+	})
+	for _, key := range env.OrderedKeys(envs, order) {
+		cmdEnvs = append(cmdEnvs, fmt.Sprintf("%s=%s", key, envs[key]))
+	}
+
+	if expandArgs {
+		args = expandCommandArgs(args, envs)
+	}
+	c := newCommand(args[0], args[1:]...)
+	if argv0 != "" {
+		c.Args[0] = argv0
+	}
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	c.Env = cmdEnvs
+	return c
+}
+
+// lookupEnv looks up key in the loaded envs, then in the environment of envdo.
+func lookupEnv(envs map[string]string, key string) (string, bool) {
+	if v, ok := envs[key]; ok {
+		return v, true
+	}
+	return os.LookupEnv(key)
+}
+
+// expandPercent expands %VAR% in s with envs like cmd.exe. Undefined variables are kept as they are.
+func expandPercent(s string, envs map[string]string) string {
+	return percentVarRe.ReplaceAllStringFunc(s, func(m string) string {
+		if v, ok := lookupEnv(envs, m[1:len(m)-1]); ok {
+			return v
+		}
+		return m
+	})
+}
+
+// expandDollar expands $VAR and ${VAR} in s with envs like a shell. Undefined variables are expanded to empty strings.
+func expandDollar(s string, envs map[string]string) string {
+	return os.Expand(s, func(key string) string {
+		v, _ := lookupEnv(envs, key)
+		return v
+	})
+}
+
+// addCommandFlags registers the flags of loading the environment variables of the profile and executing a command
+// with them, shared by the commands executing commands so that they accept the same flags (and the environment
+// variables of flagEnvs).
+func addCommandFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&baseProfile, "base", "", "base profile to layer the profile on (.env for the default .env file)")
+	cmd.Flags().StringVar(&prefix, "prefix", "", "add the prefix to keys (e.g. VITE_)")
+	cmd.Flags().StringVar(&stripPrefix, "strip-prefix", "", "strip the prefix from keys (e.g. APP_)")
+	cmd.Flags().StringArrayVar(&unset, "unset", nil, "remove the variable from the environment of the command, even if inherited from the shell (can be repeated)")
+	cmd.Flags().BoolVar(&force, "force", false, "run the command even if allowed_commands of "+config.Filename+" does not allow it for the profile")
+	cmd.Flags().BoolVar(&strict, "strict", false, "fail on warnings of the .env files such as invalid keys, expired keys and missing required keys")
+	cmd.Flags().BoolVar(&strictPerms, "strict-perms", false, "refuse to load .env files accessible by other users or owned by another user, instead of warning")
+	cmd.Flags().DurationVar(&timeout, "timeout", 0, "terminate the command with SIGTERM and exit with 124 if it does not finish within the duration (e.g. 30s)")
+	cmd.Flags().DurationVar(&killAfter, "kill-after", stopTimeout, "kill the command if it does not exit within the duration after SIGTERM sent by --timeout")
+}
+
+// addRunFlags registers the flags of addCommandFlags and those of running a command with runCommand.
+func addRunFlags(cmd *cobra.Command) {
+	addCommandFlags(cmd)
+	cmd.Flags().BoolVar(&expandArgs, "expand-args", false, "expand variables in the command arguments with the loaded environment variables ($VAR, or %VAR% on Windows)")
+	cmd.Flags().BoolVar(&redact, "redact", false, "replace values of secret keys in the output of the command with [REDACTED:KEY]")
+	cmd.Flags().IntVar(&redactMinLength, "redact-min-length", output.DefaultRedactMinLength, "minimum length of the values redacted by --redact")
+	cmd.Flags().IntVar(&retries, "retries", 0, "retry the command up to N times if it fails")
+	cmd.Flags().DurationVar(&retryDelay, "retry-delay", time.Second, "delay before the first retry, doubled after each retry")
+	cmd.Flags().IntSliceVar(&retryOnExitCodes, "retry-on-exit-codes", nil, "retry only if the command exits with one of the codes (e.g. 1,75) (default any non-zero code)")
+	cmd.Flags().StringVar(&argv0, "argv0", "", "argv[0] (process name shown by ps) of the command")
+}
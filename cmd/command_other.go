@@ -0,0 +1,63 @@
+//go:build !windows
+
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"os"
+	osexec "os/exec"
+	"syscall"
+
+	"github.com/k1LoW/exec"
+)
+
+// forwardSignals are the signals sent to envdo forwarded to the command.
+var forwardSignals = []os.Signal{syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP, syscall.SIGQUIT}
+
+// newCommand returns the command to execute name with args.
+func newCommand(name string, args ...string) *osexec.Cmd {
+	return exec.Command(name, args...)
+}
+
+// expandCommandArgs expands $VAR and ${VAR} in args with envs.
+func expandCommandArgs(args []string, envs map[string]string) []string {
+	expanded := make([]string, len(args))
+	for i, a := range args {
+		expanded[i] = expandDollar(a, envs)
+	}
+	return expanded
+}
+
+// sameEnvKey reports whether a and b are the same environment variable name.
+func sameEnvKey(a, b string) bool {
+	return a == b
+}
+
+// exitStatus returns the exit status of the command like a shell, 128+N if it was killed by the signal N,
+// and the signal (0 if it exited normally).
+func exitStatus(err *osexec.ExitError) (int, syscall.Signal) {
+	if ws, ok := err.Sys().(syscall.WaitStatus); ok && ws.Signaled() {
+		return 128 + int(ws.Signal()), ws.Signal()
+	}
+	return err.ExitCode(), 0
+}
@@ -0,0 +1,112 @@
+//go:build !windows
+
+package cmd
+
+import (
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// signalUntil sends SIGTERM to the test process repeatedly until done is closed, as the signal
+// may be sent before envdo starts waiting for it. The signal is also received by the test so that
+// it does not terminate the test process.
+func signalUntil(t *testing.T, done <-chan struct{}) {
+	t.Helper()
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+	deadline := time.After(10 * time.Second)
+	for {
+		if err := syscall.Kill(os.Getpid(), syscall.SIGTERM); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case <-done:
+			return
+		case <-deadline:
+			t.Fatal("timed out waiting for envdo to handle the signal")
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+}
+
+// waitFile waits until the file has the content.
+func waitFile(t *testing.T, path, want string) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		b, _ := os.ReadFile(path)
+		if string(b) == want {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("got %q in %s, want %q", b, path, want)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestRunCommandSignal(t *testing.T) {
+	resetCommandFlags(t)
+	// A command terminated by the forwarded signal is not retried
+	retries = 2
+	retryDelay = time.Millisecond
+	dir := t.TempDir()
+	ready := filepath.Join(dir, "ready")
+	args := []string{"sh", "-c", `echo >> "$READY"; exec sleep 10`}
+	done := make(chan struct{})
+	var err error
+	go func() {
+		defer close(done)
+		err = runCommand(&cobra.Command{}, args, map[string]string{"READY": ready}, nil)
+	}()
+	waitFile(t, ready, "\n")
+	signalUntil(t, done)
+	wantExitCode(t, err, 128+int(syscall.SIGTERM))
+}
+
+func TestRunWatch(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	dir := t.TempDir()
+	t.Chdir(dir)
+	path := filepath.Join(dir, ".env")
+	if err := os.WriteFile(path, []byte("V=1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(dir, "out")
+	envs, order, err := loadEnvs("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := newEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := []string{"sh", "-c", `echo "$V" >> out`}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		err = runWatch(&cobra.Command{}, args, envs, order, e.Files(""), func() (map[string]string, []string, error) {
+			return loadEnvs("")
+		})
+	}()
+	waitFile(t, out, "1\n")
+
+	// The command is restarted with the reloaded environment variables
+	if err := os.WriteFile(path, []byte("V=2\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	waitFile(t, out, "1\n2\n")
+
+	signalUntil(t, done)
+	if err != nil {
+		t.Errorf("got %v, want nil", err)
+	}
+}
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// resetCommandFlags resets the global flag variables of running commands after the test.
+func resetCommandFlags(t *testing.T) {
+	t.Helper()
+	t.Cleanup(func() {
+		retries = 0
+		retryDelay = 0
+		retryOnExitCodes = nil
+		timeout = 0
+		killAfter = 0
+	})
+}
+
+// wantExitCode fails unless err makes envdo exit with want (0 for nil).
+func wantExitCode(t *testing.T, err error, want int) {
+	t.Helper()
+	if want == 0 {
+		if err != nil {
+			t.Errorf("got %v, want nil", err)
+		}
+		return
+	}
+	var exitErr *exitCodeError
+	if !errors.As(err, &exitErr) || exitErr.code != want {
+		t.Errorf("got %v, want exit code %d", err, want)
+	}
+}
+
+func TestRunCommandExitCode(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("sh is not available")
+	}
+	tests := []struct {
+		name    string
+		args    []string
+		timeout time.Duration
+		want    int
+	}{
+		{"success", []string{"sh", "-c", "exit 0"}, 0, 0},
+		{"failure", []string{"sh", "-c", "exit 3"}, 0, 3},
+		{"env", []string{"sh", "-c", `exit "$CODE"`}, 0, 5},
+		{"killed by signal", []string{"sh", "-c", "kill -TERM $$"}, 0, 143},
+		{"not found", []string{"envdo-test-command-not-found"}, 0, exitCommandNotFound},
+		{"timeout", []string{"sleep", "10"}, 100 * time.Millisecond, exitTimeout},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetCommandFlags(t)
+			timeout = tt.timeout
+			killAfter = time.Second
+			err := runCommand(&cobra.Command{}, tt.args, map[string]string{"CODE": "5"}, nil)
+			wantExitCode(t, err, tt.want)
+		})
+	}
+}
+
+func TestRunCommandRetries(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("sh is not available")
+	}
+	tests := []struct {
+		name      string
+		retries   int
+		exitCodes []int
+		want      int
+		wantRuns  int
+	}{
+		// The command fails twice with 75 and succeeds on the third attempt
+		{"succeeds after retries", 2, nil, 0, 3},
+		{"retries exhausted", 1, nil, 75, 2},
+		{"retried exit code", 2, []int{75}, 0, 3},
+		{"not retried exit code", 2, []int{1}, 75, 1},
+		{"no retries", 0, nil, 75, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetCommandFlags(t)
+			retries = tt.retries
+			retryDelay = time.Millisecond
+			retryOnExitCodes = tt.exitCodes
+			runs := filepath.Join(t.TempDir(), "runs")
+			args := []string{"sh", "-c", `echo >> "$RUNS"; [ "$(wc -l < "$RUNS")" -ge 3 ] || exit 75`}
+			err := runCommand(&cobra.Command{}, args, map[string]string{"RUNS": runs}, nil)
+			wantExitCode(t, err, tt.want)
+			b, err := os.ReadFile(runs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.Count(string(b), "\n"); got != tt.wantRuns {
+				t.Errorf("got %d runs, want %d", got, tt.wantRuns)
+			}
+		})
+	}
+}
+
+func TestCheckAllowedCommand(t *testing.T) {
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	userConfig := filepath.Join(configHome, "envdo", "config.yml")
+	if err := os.MkdirAll(filepath.Dir(userConfig), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(userConfig, []byte("allowed_commands:\n  default: [kubectl]\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	t.Chdir(dir)
+	// The project configuration cannot loosen the restriction of the user configuration
+	project := "allowed_commands:\n  default: [\"*\"]\n  staging: [terraform]\n"
+	if err := os.WriteFile(filepath.Join(dir, ".envdo.yml"), []byte(project), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkAllowedCommand("", []string{"kubectl"}); err != nil {
+		t.Errorf("got %v, want nil", err)
+	}
+	err := checkAllowedCommand("", []string{"echo"})
+	if err == nil || !strings.Contains(err.Error(), userConfig) {
+		t.Errorf("got %v, want error naming %s", err, userConfig)
+	}
+	err = checkAllowedCommand("staging", []string{"echo"})
+	if err == nil || !strings.Contains(err.Error(), filepath.Join(dir, ".envdo.yml")) {
+		t.Errorf("got %v, want error naming .envdo.yml", err)
+	}
+}
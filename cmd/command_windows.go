@@ -0,0 +1,125 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	osexec "os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/k1LoW/exec"
+)
+
+// forwardSignals are the signals sent to envdo forwarded to the command.
+// Commands are started in a new process group, so they do not receive Ctrl+C of the console by themselves.
+var forwardSignals = []os.Signal{os.Interrupt}
+
+// batchSpecialChars are the characters making cmd.exe interpret an unquoted argument of a batch file.
+const batchSpecialChars = "\t &()[]{}^=;!'+,`~%|<>\""
+
+// newCommand returns the command to execute name with args.
+// name is resolved with PATHEXT, and batch files (.bat, .cmd) are executed via cmd /c
+// with the arguments quoted for cmd.exe.
+func newCommand(name string, args ...string) *osexec.Cmd {
+	if p, err := osexec.LookPath(name); err == nil {
+		switch strings.ToLower(filepath.Ext(p)) {
+		case ".bat", ".cmd":
+			c := exec.Command("cmd.exe")
+			// Pass the command line as is, as cmd.exe does not follow the quoting of os/exec
+			line, err := batchCommandLine(p, args)
+			if err != nil {
+				c.Err = err
+			}
+			c.SysProcAttr.CmdLine = line
+			return c
+		}
+		name = p
+	}
+	return exec.Command(name, args...)
+}
+
+// batchCommandLine returns the command line of cmd.exe executing the batch file script with args,
+// quoting the arguments so that cmd.exe passes them to the batch file without interpreting
+// metacharacters (& | < > ^ and %VAR%) in them. Arguments containing line breaks or NUL cannot be passed safely.
+func batchCommandLine(script string, args []string) (string, error) {
+	var b strings.Builder
+	b.WriteString(`cmd.exe /e:ON /v:OFF /d /c ""`)
+	b.WriteString(script)
+	b.WriteString(`"`)
+	for _, a := range args {
+		if strings.ContainsAny(a, "\r\n\x00") {
+			return "", fmt.Errorf("arguments of a batch file cannot contain line breaks or NUL: %q", a)
+		}
+		b.WriteByte(' ')
+		quote := a == "" || strings.ContainsAny(a, batchSpecialChars)
+		if quote {
+			b.WriteByte('"')
+		}
+		backslashes := 0
+		for _, r := range a {
+			switch r {
+			case '\\':
+				backslashes++
+				b.WriteRune(r)
+				continue
+			case '"':
+				// Double the backslashes before the quote, and escape the quote by doubling it
+				b.WriteString(strings.Repeat(`\`, backslashes))
+				b.WriteByte('"')
+			case '%':
+				// Break %VAR% so that cmd.exe does not expand it (%cd:~,% expands to an empty string)
+				b.WriteString("%%cd:~,")
+			}
+			backslashes = 0
+			b.WriteRune(r)
+		}
+		if quote {
+			b.WriteString(strings.Repeat(`\`, backslashes))
+			b.WriteByte('"')
+		}
+	}
+	b.WriteByte('"')
+	return b.String(), nil
+}
+
+// expandCommandArgs expands %VAR% in args with envs.
+func expandCommandArgs(args []string, envs map[string]string) []string {
+	expanded := make([]string, len(args))
+	for i, a := range args {
+		expanded[i] = expandPercent(a, envs)
+	}
+	return expanded
+}
+
+// sameEnvKey reports whether a and b are the same environment variable name.
+// Environment variable names are case-insensitive on Windows.
+func sameEnvKey(a, b string) bool {
+	return strings.EqualFold(a, b)
+}
+
+// exitStatus returns the exit code of the command. Commands are not killed by signals on Windows.
+func exitStatus(err *osexec.ExitError) (int, syscall.Signal) {
+	return err.ExitCode(), 0
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import "testing"
+
+func TestBatchCommandLine(t *testing.T) {
+	const script = `C:\bin\deploy.bat`
+	tests := []struct {
+		args    []string
+		want    string
+		wantErr bool
+	}{
+		{nil, `cmd.exe /e:ON /v:OFF /d /c ""C:\bin\deploy.bat""`, false},
+		{[]string{"a", "b"}, `cmd.exe /e:ON /v:OFF /d /c ""C:\bin\deploy.bat" a b"`, false},
+		{[]string{""}, `cmd.exe /e:ON /v:OFF /d /c ""C:\bin\deploy.bat" """`, false},
+		{[]string{"a b"}, `cmd.exe /e:ON /v:OFF /d /c ""C:\bin\deploy.bat" "a b""`, false},
+		// Metacharacters are quoted so that cmd.exe does not run another command
+		{[]string{"x & calc"}, `cmd.exe /e:ON /v:OFF /d /c ""C:\bin\deploy.bat" "x & calc""`, false},
+		{[]string{`x" & calc`}, `cmd.exe /e:ON /v:OFF /d /c ""C:\bin\deploy.bat" "x"" & calc""`, false},
+		{[]string{`x\" & calc`}, `cmd.exe /e:ON /v:OFF /d /c ""C:\bin\deploy.bat" "x\\"" & calc""`, false},
+		{[]string{`C:\dir\`}, `cmd.exe /e:ON /v:OFF /d /c ""C:\bin\deploy.bat" C:\dir\"`, false},
+		{[]string{`C:\my dir\`}, `cmd.exe /e:ON /v:OFF /d /c ""C:\bin\deploy.bat" "C:\my dir\\""`, false},
+		{[]string{"%PATH%"}, `cmd.exe /e:ON /v:OFF /d /c ""C:\bin\deploy.bat" "%%cd:~,%PATH%%cd:~,%""`, false},
+		{[]string{"a\nb"}, "", true},
+	}
+	for _, tt := range tests {
+		got, err := batchCommandLine(script, tt.args)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%q: want error", tt.args)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("%q: got %s, want %s", tt.args, got, tt.want)
+		}
+	}
+}
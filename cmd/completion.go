@@ -0,0 +1,64 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"github.com/k1LoW/envdo/env"
+	"github.com/spf13/cobra"
+)
+
+// registerCompletions registers the dynamic completion of profile names
+// to the --profile and --base flags of cmd and its subcommands.
+func registerCompletions(cmd *cobra.Command) {
+	if cmd.LocalFlags().Lookup("profile") != nil {
+		_ = cmd.RegisterFlagCompletionFunc("profile", completeProfiles)
+	}
+	if cmd.LocalFlags().Lookup("base") != nil {
+		_ = cmd.RegisterFlagCompletionFunc("base", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			names, directive := completeProfiles(cmd, args, toComplete)
+			return append([]string{env.Filename("")}, names...), directive
+		})
+	}
+	for _, c := range cmd.Commands() {
+		registerCompletions(c)
+	}
+}
+
+// completeProfiles completes the names of the profiles found in the search directories.
+func completeProfiles(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	e, err := newEnv()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	profiles, err := e.Profiles()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	names := make([]string, 0, len(profiles))
+	for _, p := range profiles {
+		if p.Name == env.DefaultProfileName {
+			continue
+		}
+		names = append(names, p.Name)
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
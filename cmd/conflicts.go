@@ -0,0 +1,106 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/k1LoW/envdo/env"
+	"github.com/spf13/cobra"
+)
+
+var conflictsFormat string
+
+// conflictsCmd represents the conflicts command.
+var conflictsCmd = &cobra.Command{
+	Use:   "conflicts",
+	Short: "List keys defined by more than one source",
+	Long: `List keys of a profile defined by more than one source (or more than once in a file),
+showing the origin of each definition and which one is loaded.
+
+Values are not printed.
+
+Examples:
+  envdo conflicts
+  envdo conflicts -p production --format json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		e, err := newEnv()
+		if err != nil {
+			return err
+		}
+		entries, err := e.Entries(profile)
+		if err != nil {
+			return err
+		}
+		conflicts := env.Conflicts(entries)
+		switch conflictsFormat {
+		case "json":
+			type definition struct {
+				File      string `json:"file"`
+				Line      int    `json:"line"`
+				Loaded    bool   `json:"loaded"`
+				SameValue bool   `json:"same_value"`
+			}
+			type conflict struct {
+				Key         string       `json:"key"`
+				Definitions []definition `json:"definitions"`
+			}
+			out := make([]conflict, 0, len(conflicts))
+			for _, c := range conflicts {
+				defs := make([]definition, 0, len(c.Entries))
+				for i, e := range c.Entries {
+					defs = append(defs, definition{File: e.File, Line: e.Line, Loaded: i == 0, SameValue: e.Value == c.Entries[0].Value})
+				}
+				out = append(out, conflict{Key: c.Key, Definitions: defs})
+			}
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(out)
+		case "text":
+			for _, c := range conflicts {
+				fmt.Println(c.Key)
+				for i, e := range c.Entries {
+					status := "loaded"
+					if i > 0 {
+						status = "overridden"
+						if e.Value == c.Entries[0].Value {
+							status += ", same value"
+						}
+					}
+					fmt.Printf("  %s:%d (%s)\n", e.File, e.Line, status)
+				}
+			}
+			return nil
+		default:
+			return fmt.Errorf("unsupported format: %s", conflictsFormat)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(conflictsCmd)
+	conflictsCmd.Flags().StringVarP(&profile, "profile", "p", "", "profile name")
+	conflictsCmd.Flags().StringVar(&conflictsFormat, "format", "text", "output format (text, json)")
+}
@@ -0,0 +1,130 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/k1LoW/envdo/env"
+	"github.com/k1LoW/envdo/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	diffProfiles   []string
+	diffAgainst    string
+	diffFormat     string
+	diffShowValues bool
+	diffExitCode   bool
+)
+
+// diffMarks are the marks of the types of changes in the text format.
+var diffMarks = map[string]string{
+	env.ChangeAdded:   "+",
+	env.ChangeRemoved: "-",
+	env.ChangeChanged: "~",
+}
+
+// diffCmd represents the diff command.
+var diffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Show differences between the environments of two profiles",
+	Long: `Show keys added, removed and changed from the resolved environment of a profile
+to that of another profile. Use .env for the default profile.
+
+Values are masked unless --show-values is given.
+
+Examples:
+  envdo diff -p staging -p production
+  envdo diff -p staging --against production --format json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		profiles := diffProfiles
+		if diffAgainst != "" {
+			profiles = append(profiles, diffAgainst)
+		}
+		if len(profiles) != 2 {
+			return errors.New("specify two profiles with -p (and --against)")
+		}
+		var environments [2]map[string]string
+		for i, p := range profiles {
+			envs, _, err := loadEnvs(env.BaseProfile(p))
+			if err != nil {
+				return fmt.Errorf("failed to load environment variables of %s: %w", p, err)
+			}
+			environments[i] = envs
+		}
+		changes := env.Diff(environments[0], environments[1])
+		if !diffShowValues {
+			for i := range changes {
+				if changes[i].From != "" {
+					changes[i].From = output.MaskedValue
+				}
+				if changes[i].To != "" {
+					changes[i].To = output.MaskedValue
+				}
+			}
+		}
+		switch diffFormat {
+		case "text":
+			for _, c := range changes {
+				switch {
+				case !diffShowValues:
+					fmt.Printf("%s %s\n", diffMarks[c.Type], c.Key)
+				case c.Type == env.ChangeAdded:
+					fmt.Printf("+ %s=%s\n", c.Key, c.To)
+				case c.Type == env.ChangeRemoved:
+					fmt.Printf("- %s=%s\n", c.Key, c.From)
+				default:
+					fmt.Printf("~ %s=%s -> %s\n", c.Key, c.From, c.To)
+				}
+			}
+		case "json":
+			if changes == nil {
+				changes = []env.Change{}
+			}
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(changes); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unsupported format: %s", diffFormat)
+		}
+		if diffExitCode && len(changes) > 0 {
+			return exitWithCode(cmd, 1)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+	diffCmd.Flags().StringArrayVarP(&diffProfiles, "profile", "p", nil, "profile name (given twice, or once with --against)")
+	diffCmd.Flags().StringVar(&diffAgainst, "against", "", "profile name to compare against")
+	diffCmd.Flags().StringVar(&diffFormat, "format", "text", "output format (text, json)")
+	diffCmd.Flags().BoolVar(&diffShowValues, "show-values", false, "show values instead of masking them")
+	diffCmd.Flags().BoolVar(&diffExitCode, "exit-code", false, "exit with 1 if there are differences")
+}
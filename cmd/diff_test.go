@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDiffExitCode(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	dir := t.TempDir()
+	t.Chdir(dir)
+	for name, content := range map[string]string{
+		".env.staging":    "HOST=staging.example.com\nURL=https://${HOST}\n",
+		".env.production": "HOST=example.com\nURL=https://${HOST}\n",
+		".env.copy":       "HOST=example.com\nURL=https://example.com\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Cleanup(func() {
+		diffProfiles = nil
+		diffExitCode = false
+	})
+	diffExitCode = true
+
+	// The environments are compared after variables are expanded
+	diffProfiles = []string{"production", "copy"}
+	if err := diffCmd.RunE(diffCmd, nil); err != nil {
+		t.Errorf("got %v, want no differences", err)
+	}
+
+	diffProfiles = []string{"staging", "production"}
+	err := diffCmd.RunE(diffCmd, nil)
+	var exitErr *exitCodeError
+	if !errors.As(err, &exitErr) || exitErr.code != 1 {
+		t.Errorf("got %v, want exit code 1", err)
+	}
+}
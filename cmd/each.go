@@ -0,0 +1,256 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	osexec "os/exec"
+	"sync"
+	"sync/atomic"
+
+	"github.com/k1LoW/envdo/env"
+	"github.com/k1LoW/exec"
+	"github.com/spf13/cobra"
+)
+
+var (
+	eachProfiles []string
+	eachJobs     int
+)
+
+// eachCmd represents the each command.
+var eachCmd = &cobra.Command{
+	Use:   "each -p PROFILE [-p PROFILE...] -- COMMAND [ARG...]",
+	Short: "Execute a command once per profile",
+	Long: `Execute a command once per profile with the environment variables of the profile,
+prefixing each line of the output with the profile name, and summarize the exit codes.
+Use .env for the default profile.
+
+The commands run one by one by default, and up to -j at a time in parallel.
+envdo exits with 1 if the command fails or times out (--timeout) for any profile. Signals sent to envdo are forwarded to the running
+commands, the remaining profiles are skipped, and envdo exits with 128+N for the signal N.
+
+Examples:
+  envdo each -p dev -p staging -p prod -- ./smoke-test.sh
+  envdo each -p dev -p staging -p prod -j 3 -- ./smoke-test.sh`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(eachProfiles) == 0 {
+			return errors.New("specify profiles with -p")
+		}
+		if eachJobs < 1 {
+			return fmt.Errorf("invalid -j %d: must be at least 1", eachJobs)
+		}
+		// Commands are prepared one by one, because loading environment variables sets the keys to unset
+		runs := make([]*eachRun, len(eachProfiles))
+		var mu sync.Mutex
+		for i, p := range eachProfiles {
+			c, err := eachCommand(env.BaseProfile(p), args)
+			if err != nil {
+				return fmt.Errorf("failed to prepare the command for %s: %w", p, err)
+			}
+			runs[i] = &eachRun{
+				profile: p,
+				cmd:     c,
+				stdout:  newPrefixWriter(os.Stdout, &mu, p),
+				stderr:  newPrefixWriter(os.Stderr, &mu, p),
+			}
+			c.Stdin = nil
+			c.Stdout = runs[i].stdout
+			c.Stderr = runs[i].stderr
+		}
+
+		sem := make(chan struct{}, eachJobs)
+		var (
+			wg       sync.WaitGroup
+			signaled atomic.Value
+		)
+		for _, r := range runs {
+			sem <- struct{}{}
+			// Do not start the remaining commands after a signal was forwarded
+			if signaled.Load() != nil {
+				<-sem
+				r.skipped = true
+				continue
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() { <-sem }()
+				r.run()
+				if r.signal != nil {
+					signaled.CompareAndSwap(nil, r.signal)
+				}
+			}()
+		}
+		wg.Wait()
+
+		failed := false
+		width := 0
+		for _, r := range runs {
+			width = max(width, len(r.profile))
+		}
+		for _, r := range runs {
+			status := "ok"
+			switch {
+			case r.skipped:
+				status = "skipped"
+				failed = true
+			case r.err != nil:
+				status = fmt.Sprintf("failed to start: %v", r.err)
+				failed = true
+			case r.timedOut:
+				status = fmt.Sprintf("timed out after %s (exit %d)", timeout, r.code)
+				failed = true
+			case r.code != 0:
+				status = fmt.Sprintf("failed (exit %d)", r.code)
+				failed = true
+			}
+			logger.Info(fmt.Sprintf("%-*s  %s", width, r.profile, status))
+		}
+		if sig, ok := signaled.Load().(os.Signal); ok {
+			return exitWithCode(cmd, signalExitCode(sig))
+		}
+		if failed {
+			return exitWithCode(cmd, 1)
+		}
+		return nil
+	},
+}
+
+// eachRun is a run of the command for a profile.
+type eachRun struct {
+	profile string
+	cmd     *osexec.Cmd
+	stdout  *prefixWriter
+	stderr  *prefixWriter
+	code    int
+	err     error
+	// signal is the signal forwarded to the command, or nil.
+	signal os.Signal
+	// timedOut is true if the command was terminated by --timeout.
+	timedOut bool
+	// skipped is true if the command was not started because envdo received a signal.
+	skipped bool
+}
+
+// run runs the command like runCommand, forwarding signals sent to envdo to the command
+// (and its process group with --kill-children), and records its exit code (exitTimeout if it timed out).
+func (r *eachRun) run() {
+	res, err := runWithTimeout(r.cmd)
+	_ = r.stdout.Flush()
+	_ = r.stderr.Flush()
+	r.signal = res.signal
+	if res.timedOut {
+		r.timedOut, r.code = true, exitTimeout
+		return
+	}
+	var exitError *exec.ExitError
+	switch {
+	case errors.As(err, &exitError):
+		r.code = exitCode(fmt.Sprintf("%s of %s", r.cmd.Args[0], r.profile), exitError)
+	case err != nil:
+		r.err = err
+	}
+}
+
+// eachCommand prepares the command of args with the environment variables of the profile.
+func eachCommand(profile string, args []string) (*osexec.Cmd, error) {
+	if err := checkAllowedCommand(profile, args); err != nil {
+		return nil, err
+	}
+	envs, order, err := loadEnvs(profile)
+	if err != nil {
+		return nil, err
+	}
+	envs, order, err = transformKeys(envs, order)
+	if err != nil {
+		return nil, err
+	}
+	if err := recordAuditLog(profile, args, envs); err != nil {
+		return nil, err
+	}
+	c := commandWithEnvs(args, envs, order)
+	if err := checkEnviron(c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// prefixWriter writes each line with the prefix "[name] " to w, holding mu while writing a line
+// so lines of parallel commands are not mixed.
+type prefixWriter struct {
+	w      io.Writer
+	mu     *sync.Mutex
+	prefix []byte
+	buf    []byte
+}
+
+func newPrefixWriter(w io.Writer, mu *sync.Mutex, name string) *prefixWriter {
+	return &prefixWriter{w: w, mu: mu, prefix: []byte("[" + name + "] ")}
+}
+
+// Write writes the complete lines in p, buffering the last incomplete line.
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		if err := w.writeLine(w.buf[:i+1]); err != nil {
+			return 0, err
+		}
+		w.buf = w.buf[i+1:]
+	}
+}
+
+// Flush writes the buffered incomplete line with a newline.
+func (w *prefixWriter) Flush() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+	line := append(w.buf, '\n')
+	w.buf = nil
+	return w.writeLine(line)
+}
+
+func (w *prefixWriter) writeLine(line []byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, err := w.w.Write(w.prefix); err != nil {
+		return err
+	}
+	_, err := w.w.Write(line)
+	return err
+}
+
+func init() {
+	rootCmd.AddCommand(eachCmd)
+	eachCmd.Flags().StringArrayVarP(&eachProfiles, "profile", "p", nil, "profile name (can be repeated, .env for the default profile)")
+	eachCmd.Flags().IntVarP(&eachJobs, "jobs", "j", 1, "number of commands to run in parallel")
+	addCommandFlags(eachCmd)
+}
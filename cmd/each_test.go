@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestEachExitCode(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("sh is not available")
+	}
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	dir := t.TempDir()
+	t.Chdir(dir)
+	for name, content := range map[string]string{".env.ok": "CODE=0\n", ".env.ng": "CODE=3\n"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Cleanup(func() {
+		eachProfiles = nil
+	})
+	args := []string{"sh", "-c", `exit "$CODE"`}
+
+	eachProfiles = []string{"ok"}
+	if err := eachCmd.RunE(eachCmd, args); err != nil {
+		t.Errorf("got %v, want nil", err)
+	}
+
+	// A command exceeding --timeout fails like the command of the single profile
+	t.Cleanup(func() {
+		timeout = 0
+		killAfter = 0
+	})
+	timeout, killAfter = 100*time.Millisecond, time.Second
+	eachProfiles = []string{"ok"}
+	err := eachCmd.RunE(eachCmd, []string{"sleep", "5"})
+	var exitErr *exitCodeError
+	if !errors.As(err, &exitErr) || exitErr.code != 1 {
+		t.Errorf("got %v, want exit code 1 after the timeout", err)
+	}
+	var mu sync.Mutex
+	r := &eachRun{profile: "x", cmd: newCommand("sleep", "5"), stdout: newPrefixWriter(io.Discard, &mu, "x"), stderr: newPrefixWriter(io.Discard, &mu, "x")}
+	r.run()
+	if !r.timedOut || r.code != exitTimeout {
+		t.Errorf("got timedOut %v and code %d, want true and %d", r.timedOut, r.code, exitTimeout)
+	}
+	timeout = 0
+
+	eachProfiles = []string{"ok", "ng"}
+	err = eachCmd.RunE(eachCmd, args)
+	if !errors.As(err, &exitErr) || exitErr.code != 1 {
+		t.Errorf("got %v, want exit code 1", err)
+	}
+}
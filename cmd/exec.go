@@ -0,0 +1,94 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"errors"
+
+	"github.com/k1LoW/envdo/env"
+	"github.com/spf13/cobra"
+)
+
+var envFiles []string
+
+// execCmd represents the exec command.
+var execCmd = &cobra.Command{
+	Use:   "exec [flags] -- COMMAND [ARG...]",
+	Short: "Execute a command with environment variables from explicit files",
+	Long: `Execute a command with environment variables loaded from the files given by --env-file,
+instead of searching the current directory and $XDG_CONFIG_HOME/envdo.
+
+--env-file can be repeated. Later files override earlier ones.
+Without --env-file, the .env files of the profile are searched as envdo does.
+
+--env-file - reads .env format from stdin and merges it at the highest priority,
+over the other --env-file files or the .env files of the profile if it is the only one.
+
+--env-file also accepts URLs of remote env files: https://, s3://bucket/key (fetched with the AWS CLI)
+and gs://bucket/object (fetched with the gcloud CLI), using their ambient credentials.
+
+Examples:
+  envdo exec --env-file ./base.env --env-file ./secrets/ci.env -- make test
+  envdo exec --env-file s3://config-bucket/app/.env.prod -- ./server
+  envdo exec -p dev -- npm start
+  generate-secrets | envdo exec --env-file - -- ./run.sh`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var (
+			envs  map[string]string
+			order []string
+			err   error
+		)
+		if files := explicitEnvFiles(); len(files) > 0 {
+			if profile != "" {
+				return errors.New("--env-file and --profile cannot be used together")
+			}
+			envs, order, err = loadEnvFileArgs(files)
+		} else {
+			if err := checkAllowedCommand(profile, args); err != nil {
+				return err
+			}
+			envs, order, err = loadEnvs(profile)
+		}
+		if err != nil {
+			return err
+		}
+		envs, order, err = transformKeys(envs, order)
+		if err != nil {
+			return err
+		}
+		if err := recordAuditLog(profile, args, envs); err != nil {
+			return err
+		}
+		return runCommand(cmd, args, envs, order)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(execCmd)
+	execCmd.Flags().StringVarP(&profile, "profile", "p", "", "profile name")
+	addRunFlags(execCmd)
+	execCmd.Flags().StringArrayVar(&envFiles, "env-file", nil, "env file to load, the URL of a remote env file (https://, s3:// or gs://) or - to read stdin at the highest priority (can be repeated, later files override earlier ones)")
+	execCmd.Flags().StringArrayVar(&envURLs, "env-url", nil, "load the remote env file at the URL (https://, s3:// or gs://) with lower priority than .env files, pinned by #sha256=<hex> if given (can be repeated; $"+env.URLTokenEnv+" is sent as a bearer token to the hosts in $"+env.URLTokenHostsEnv+")")
+	execCmd.Flags().StringArrayVar(&fromK8s, "from-k8s", nil, "load the data of the Kubernetes Secret or ConfigMap (secret/NAME or configmap/NAME) with kubectl with lower priority than .env files (can be repeated)")
+	execCmd.Flags().StringVar(&k8sNamespace, "k8s-namespace", "", "namespace of --from-k8s (default the current namespace of the kubeconfig)")
+}
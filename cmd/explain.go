@@ -0,0 +1,125 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/k1LoW/envdo/env"
+	"github.com/k1LoW/envdo/output"
+)
+
+var (
+	explain           bool
+	dryRun            bool
+	explainShowValues bool
+)
+
+// writeExplain writes each variable of envs, the environment variables passed to the command, in order
+// with the file and line it came from and the lower-priority definitions it overrides,
+// followed by the variables unset by !KEY or unset KEY lines.
+// Keys transformed by --key-case, rename, --strip-prefix or --prefix are shown with the keys in the files,
+// and values differing from the definitions (e.g. resolved secret references) are noted.
+// Values of secret keys are masked unless --show-values is given.
+func writeExplain(w io.Writer, profile string, envs map[string]string, order []string) error {
+	e, err := newEnv()
+	if err != nil {
+		return err
+	}
+	entries, err := e.Entries(profile)
+	if err != nil {
+		return err
+	}
+	stdin, err := readStdinEntries()
+	if err != nil {
+		return err
+	}
+	entries = slices.Concat(stdin, entries)
+	var m *output.Masker
+	if !explainShowValues {
+		s, err := loadSchema()
+		if err != nil {
+			return fmt.Errorf("failed to load schema: %w", err)
+		}
+		c, err := loadConfig()
+		if err != nil {
+			return err
+		}
+		if m, err = output.NewMasker(s, slices.Concat(c.MaskPatterns, maskPatterns)); err != nil {
+			return err
+		}
+	}
+	transform, err := keyTransform()
+	if err != nil {
+		return err
+	}
+	vars := make(map[string]env.Var)
+	for _, v := range env.ResolveVars(entries) {
+		vars[v.Key] = v
+	}
+	origins, err := keyOrigins(transform, slices.Collect(maps.Keys(vars)))
+	if err != nil {
+		return err
+	}
+	for _, k := range env.OrderedKeys(envs, order) {
+		masked := m != nil && m.IsMasked(k)
+		value := envs[k]
+		if masked {
+			value = output.MaskedValue
+		}
+		v, ok := vars[origins[k]]
+		if !ok {
+			// Added by --env-url or --from-k8s
+			_, _ = fmt.Fprintf(w, "%s=%s (defined outside the .env files)\n", k, escapeNewlines(value))
+			continue
+		}
+		source := fmt.Sprintf("%s:%d", v.File, v.Line)
+		if v.Key != k {
+			source = fmt.Sprintf("%s at %s", v.Key, source)
+		}
+		switch {
+		case v.Value == envs[k]:
+		case masked:
+			source += ", resolved"
+		default:
+			source += ", resolved from " + escapeNewlines(v.Value)
+		}
+		_, _ = fmt.Fprintf(w, "%s=%s (%s)\n", k, escapeNewlines(value), source)
+		for _, o := range v.Overrides {
+			_, _ = fmt.Fprintf(w, "  overrides %s:%d\n", o.File, o.Line)
+		}
+	}
+	for _, k := range env.UnsetKeys(entries) {
+		i := slices.IndexFunc(entries, func(en env.Entry) bool { return en.Key == k })
+		_, _ = fmt.Fprintf(w, "unset %s (%s:%d)\n", k, entries[i].File, entries[i].Line)
+	}
+	return nil
+}
+
+// escapeNewlines returns s with newlines escaped to write it on a line.
+func escapeNewlines(s string) string {
+	return strings.ReplaceAll(s, "\n", `\n`)
+}
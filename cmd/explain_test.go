@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteExplain(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	dir := t.TempDir()
+	t.Chdir(dir)
+	if err := os.WriteFile(filepath.Join(dir, ".env"), []byte("HOST=localhost\nURL=http://${HOST}/\nDB_PASSWORD=hunter2\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		prefix = ""
+		explainShowValues = false
+	})
+	prefix = "APP_"
+	envs, order, err := loadEnvs("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	envs, order, err = transformKeys(envs, order)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Values added outside the .env files, such as those of --env-url
+	envs["EXTRA"] = "remote"
+	path := filepath.Join(dir, ".env")
+
+	tests := []struct {
+		showValues bool
+		want       string
+	}{
+		{false, "APP_HOST=localhost (HOST at " + path + ":1)\n" +
+			"APP_URL=http://localhost/ (URL at " + path + ":2, resolved from http://${HOST}/)\n" +
+			"APP_DB_PASSWORD=*** (DB_PASSWORD at " + path + ":3)\n" +
+			"EXTRA=remote (defined outside the .env files)\n"},
+		{true, "APP_HOST=localhost (HOST at " + path + ":1)\n" +
+			"APP_URL=http://localhost/ (URL at " + path + ":2, resolved from http://${HOST}/)\n" +
+			"APP_DB_PASSWORD=hunter2 (DB_PASSWORD at " + path + ":3)\n" +
+			"EXTRA=remote (defined outside the .env files)\n"},
+	}
+	for _, tt := range tests {
+		explainShowValues = tt.showValues
+		buf := new(bytes.Buffer)
+		if err := writeExplain(buf, "", envs, order); err != nil {
+			t.Fatal(err)
+		}
+		if got := buf.String(); got != tt.want {
+			t.Errorf("show values %v: got\n%s\nwant\n%s", tt.showValues, got, tt.want)
+		}
+	}
+}
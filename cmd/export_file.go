@@ -0,0 +1,67 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/k1LoW/envdo/output"
+)
+
+var (
+	exportFile string
+	githubEnv  bool
+)
+
+// writeExportFile appends envs in KEY=value form to the file given by --export-file,
+// or $GITHUB_ENV with --github-env, in order. With --github-env, values of secret keys (including keys matching
+// mask_patterns of .envdo.yml and --mask-pattern) are also masked in the GitHub Actions logs.
+func writeExportFile(envs map[string]string, order []string) error {
+	path := exportFile
+	if githubEnv {
+		if path != "" {
+			return errors.New("--export-file and --github-env cannot be used together")
+		}
+		path = os.Getenv("GITHUB_ENV")
+		if path == "" {
+			return errors.New("--github-env requires GITHUB_ENV to be set (run in GitHub Actions)")
+		}
+		m, err := secretMasker()
+		if err != nil {
+			return err
+		}
+		if err := output.WriteGitHubMasks(os.Stdout, envs, m); err != nil {
+			return err
+		}
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if err := output.WriteEnvFile(f, envs, order); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return f.Close()
+}
@@ -0,0 +1,113 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/k1LoW/envdo/env"
+	"github.com/spf13/cobra"
+)
+
+// flagEnvs are the environment variables giving the flags not given on the command line,
+// so wrappers and CI can configure envdo without changing the command lines. This is the only place
+// envdo reads its ENVDO_* settings, and the precedence is flags > environment variables > .envdo.yml
+// (and config.yml of the configuration directory). The values of list flags are split by the path list separator.
+// Flags bypassing safety checks (--force and --allow-exec) are only given on the command line,
+// and credentials ($ENVDO_URL_TOKEN) are not flags not to be shown in process lists.
+var flagEnvs = []struct {
+	env  string
+	flag string
+	list bool
+}{
+	{"ENVDO_PROFILE", "profile", false},
+	{"ENVDO_BASE", "base", false},
+	{"ENVDO_ENV_FILE", "env-file", false},
+	{"ENVDO_CONFIG_DIR", "config-dir", false},
+	{"ENVDO_DIR", "env-dir", true},
+	{"ENVDO_SHELL", "shell", false},
+	{"ENVDO_SCHEMA", "schema", false},
+	{"ENVDO_KEY_CASE", "key-case", false},
+	{"ENVDO_PREFIX", "prefix", false},
+	{"ENVDO_STRIP_PREFIX", "strip-prefix", false},
+	{"ENVDO_NO_EXPAND", "no-expand", false},
+	{"ENVDO_EXPAND_ARGS", "expand-args", false},
+	{"ENVDO_MASK", "mask", false},
+	{"ENVDO_REDACT", "redact", false},
+	{"ENVDO_STRICT", "strict", false},
+	{"ENVDO_STRICT_PERMS", "strict-perms", false},
+	{"ENVDO_CI", "forbid-pwd-env", false},
+	{"ENVDO_AUDIT_LOG", "audit-log", false},
+	{"ENVDO_TIMEOUT", "timeout", false},
+	{"ENVDO_RETRIES", "retries", false},
+	{"ENVDO_UP", "up", false},
+	{"ENVDO_NO_CACHE", "no-cache", false},
+	{"ENVDO_KILL_CHILDREN", "kill-children", false},
+	{"ENVDO_SYNC_REPO", "repo", false},
+	{"ENVDO_VERBOSE", "verbose", false},
+	{"ENVDO_QUIET", "quiet", false},
+}
+
+// applyFlagEnvs sets the flags of cmd not given on the command line to the values of their environment variables
+// in flagEnvs. Empty values are ignored. The flags are marked as changed, so they take precedence over .envdo.yml.
+func applyFlagEnvs(cmd *cobra.Command) error {
+	for _, fe := range flagEnvs {
+		v := os.Getenv(fe.env)
+		if v == "" {
+			continue
+		}
+		f := cmd.Flags().Lookup(fe.flag)
+		if f == nil || f.Changed {
+			continue
+		}
+		values := []string{v}
+		if fe.list {
+			values = filepath.SplitList(v)
+		}
+		for _, v := range values {
+			if err := cmd.Flags().Set(fe.flag, v); err != nil {
+				return fmt.Errorf("invalid $%s: %w", fe.env, err)
+			}
+		}
+	}
+	return nil
+}
+
+// envdoConfigDir returns the configuration directory of envdo given by --config-dir,
+// or $XDG_CONFIG_HOME/envdo by default.
+func envdoConfigDir() string {
+	if configDir != "" {
+		return configDir
+	}
+	return filepath.Join(env.DefaultConfigDir(), "envdo")
+}
+
+// defaultEnv returns the default environment loader with the profile files and age identities
+// in the configuration directory of envdo.
+func defaultEnv() *env.Env {
+	e := env.Default()
+	e.SetProfileDir(envdoConfigDir())
+	e.SetDecryptor(env.NewAgeDecryptor(envdoConfigDir()))
+	return e
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// newFlagEnvTestCmd returns a command with the flags of flagEnvs tested, bound to the global flag variables,
+// and resets the variables after the test.
+func newFlagEnvTestCmd(t *testing.T) *cobra.Command {
+	t.Helper()
+	t.Cleanup(func() {
+		profile = ""
+		envDirs = nil
+		configDir = ""
+		noExpand = false
+	})
+	c := &cobra.Command{Use: "test"}
+	c.Flags().StringVarP(&profile, "profile", "p", "", "")
+	c.Flags().StringArrayVar(&envDirs, "env-dir", nil, "")
+	c.Flags().StringVar(&configDir, "config-dir", "", "")
+	c.Flags().BoolVar(&noExpand, "no-expand", false, "")
+	return c
+}
+
+func TestFlagEnvsPrecedence(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		env    string
+		args   []string
+		want   string
+	}{
+		{"none", "", "", nil, ""},
+		{"config", "config", "", nil, "config"},
+		{"env over config", "config", "env", nil, "env"},
+		{"flag over env", "config", "env", []string{"-p", "flag"}, "flag"},
+		{"flag over config", "config", "", []string{"--profile", "flag"}, "flag"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if tt.config != "" {
+				if err := os.WriteFile(filepath.Join(dir, ".envdo.yml"), []byte("profile: "+tt.config+"\n"), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			t.Chdir(dir)
+			t.Setenv("ENVDO_PROFILE", tt.env)
+			c := newFlagEnvTestCmd(t)
+			if err := c.ParseFlags(tt.args); err != nil {
+				t.Fatal(err)
+			}
+			if err := rootCmd.PersistentPreRunE(c, nil); err != nil {
+				t.Fatal(err)
+			}
+			if profile != tt.want {
+				t.Errorf("got profile %q, want %q", profile, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlagEnvsList(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("ENVDO_DIR", "a"+string(os.PathListSeparator)+"b")
+	c := newFlagEnvTestCmd(t)
+	if err := applyFlagEnvs(c); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a", "b"}; !slices.Equal(envDirs, want) {
+		t.Errorf("got %v, want %v", envDirs, want)
+	}
+
+	c = newFlagEnvTestCmd(t)
+	if err := c.ParseFlags([]string{"--env-dir", "c"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := applyFlagEnvs(c); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"c"}; !slices.Equal(envDirs, want) {
+		t.Errorf("got %v, want %v", envDirs, want)
+	}
+}
+
+func TestFlagEnvsInvalid(t *testing.T) {
+	t.Setenv("ENVDO_NO_EXPAND", "maybe")
+	c := newFlagEnvTestCmd(t)
+	if err := applyFlagEnvs(c); err == nil {
+		t.Error("want error")
+	}
+	t.Setenv("ENVDO_NO_EXPAND", "1")
+	c = newFlagEnvTestCmd(t)
+	if err := applyFlagEnvs(c); err != nil {
+		t.Fatal(err)
+	}
+	if !noExpand {
+		t.Error("want --no-expand from $ENVDO_NO_EXPAND")
+	}
+}
+
+func TestEnvdoConfigDir(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("ENVDO_CONFIG_DIR", "")
+	c := newFlagEnvTestCmd(t)
+	if err := applyFlagEnvs(c); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := envdoConfigDir(), filepath.Join(dir, "envdo"); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	custom := t.TempDir()
+	t.Setenv("ENVDO_CONFIG_DIR", custom)
+	c = newFlagEnvTestCmd(t)
+	if err := applyFlagEnvs(c); err != nil {
+		t.Fatal(err)
+	}
+	// ENVDO_CONFIG_DIR is the configuration directory of envdo itself, not its parent.
+	if got := envdoConfigDir(); got != custom {
+		t.Errorf("got %q, want %q", got, custom)
+	}
+	if got, want := defaultEnv().ProfilePath("staging"), filepath.Join(custom, ".env.staging"); got != want {
+		t.Errorf("got profile path %q, want %q", got, want)
+	}
+}
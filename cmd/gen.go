@@ -0,0 +1,86 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/k1LoW/envdo/schema"
+	"github.com/spf13/cobra"
+)
+
+var (
+	genOutput  string
+	schemaPath string
+)
+
+// genCmd represents the gen command.
+var genCmd = &cobra.Command{
+	Use:   "gen",
+	Short: "Generate code and configuration from a profile",
+	Long: `Generate code and configuration from the resolved environment variables of a profile.
+
+Types of values are taken from the schema file (.envdo.schema.yml) if defined,
+otherwise they are inferred from the values.`,
+}
+
+func init() {
+	rootCmd.AddCommand(genCmd)
+	genCmd.PersistentFlags().StringVarP(&profile, "profile", "p", "", "profile name")
+	genCmd.PersistentFlags().StringVarP(&genOutput, "output", "o", "", "output file path (default stdout)")
+	genCmd.PersistentFlags().StringVar(&schemaPath, "schema", "", "schema file path (default "+schema.Filename+" in the current directory)")
+}
+
+// loadSchema loads the schema file specified by --schema or found in the current directory.
+func loadSchema() (*schema.Schema, error) {
+	if schemaPath != "" {
+		return schema.Load(schemaPath)
+	}
+	pwd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	return schema.Find(pwd)
+}
+
+// writeGenOutput writes the output of fn to the file specified by --output or stdout.
+func writeGenOutput(fn func(w io.Writer) error) error {
+	var buf bytes.Buffer
+	if err := fn(&buf); err != nil {
+		return err
+	}
+	if genOutput == "" {
+		_, err := buf.WriteTo(os.Stdout)
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(genOutput), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(genOutput, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", genOutput, err)
+	}
+	return nil
+}
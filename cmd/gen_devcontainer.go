@@ -0,0 +1,83 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/k1LoW/envdo/gen"
+	"github.com/spf13/cobra"
+)
+
+var (
+	genDevcontainerFile    string
+	genDevcontainerSection string
+)
+
+// genDevcontainerCmd represents the gen devcontainer command.
+var genDevcontainerCmd = &cobra.Command{
+	Use:   "devcontainer",
+	Short: "Update remoteEnv/containerEnv of devcontainer.json",
+	Long: `Write or update the remoteEnv (or containerEnv) section of devcontainer.json from a profile.
+
+Secret keys are referenced via ${localEnv:KEY} instead of their values.
+The rest of the file, including comments, is kept as it is.
+The file is updated in place unless --output is given.
+
+Examples:
+  envdo gen devcontainer
+  envdo gen devcontainer --section containerEnv -p dev`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		envs, _, err := loadEnvs(profile)
+		if err != nil {
+			return err
+		}
+		s, err := loadSchema()
+		if err != nil {
+			return fmt.Errorf("failed to load schema: %w", err)
+		}
+		src, err := os.ReadFile(genDevcontainerFile)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		b, err := gen.Devcontainer(src, envs, s, genDevcontainerSection)
+		if err != nil {
+			return fmt.Errorf("failed to update %s: %w", genDevcontainerFile, err)
+		}
+		if genOutput == "" {
+			genOutput = genDevcontainerFile
+		}
+		return writeGenOutput(func(w io.Writer) error {
+			_, err := w.Write(b)
+			return err
+		})
+	},
+}
+
+func init() {
+	genCmd.AddCommand(genDevcontainerCmd)
+	genDevcontainerCmd.Flags().StringVar(&genDevcontainerFile, "file", ".devcontainer/devcontainer.json", "devcontainer.json path")
+	genDevcontainerCmd.Flags().StringVar(&genDevcontainerSection, "section", gen.DevcontainerRemoteEnv, "section to write (remoteEnv, containerEnv)")
+}
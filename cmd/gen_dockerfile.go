@@ -0,0 +1,64 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/k1LoW/envdo/gen"
+	"github.com/spf13/cobra"
+)
+
+var genDockerfileAs string
+
+// genDockerfileCmd represents the gen dockerfile command.
+var genDockerfileCmd = &cobra.Command{
+	Use:   "dockerfile",
+	Short: "Generate Dockerfile ENV or ARG instructions",
+	Long: `Generate Dockerfile ENV or ARG instructions of the non-secret keys of a profile.
+
+Secret keys are classified by the schema file, or by their names if not defined.
+
+Examples:
+  envdo gen dockerfile
+  envdo gen dockerfile --as args -p production`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		envs, _, err := loadEnvs(profile)
+		if err != nil {
+			return err
+		}
+		s, err := loadSchema()
+		if err != nil {
+			return fmt.Errorf("failed to load schema: %w", err)
+		}
+		return writeGenOutput(func(w io.Writer) error {
+			return gen.Dockerfile(w, envs, s, genDockerfileAs)
+		})
+	},
+}
+
+func init() {
+	genCmd.AddCommand(genDockerfileCmd)
+	genDockerfileCmd.Flags().StringVar(&genDockerfileAs, "as", gen.DockerfileEnv, "instruction to generate (env, args)")
+}
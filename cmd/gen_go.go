@@ -0,0 +1,62 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/k1LoW/envdo/gen"
+	"github.com/spf13/cobra"
+)
+
+var genGoPackage string
+
+// genGoCmd represents the gen go command.
+var genGoCmd = &cobra.Command{
+	Use:   "go",
+	Short: "Generate a Go config struct and its loader",
+	Long: `Generate a Go config struct with env tags and a Load function from a profile.
+
+Examples:
+  envdo gen go --package config -o config/config_gen.go
+  envdo gen go -p production --package config`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		envs, _, err := loadEnvs(profile)
+		if err != nil {
+			return err
+		}
+		s, err := loadSchema()
+		if err != nil {
+			return fmt.Errorf("failed to load schema: %w", err)
+		}
+		return writeGenOutput(func(w io.Writer) error {
+			return gen.Go(w, envs, s, genGoPackage)
+		})
+	},
+}
+
+func init() {
+	genCmd.AddCommand(genGoCmd)
+	genGoCmd.Flags().StringVar(&genGoPackage, "package", "config", "package name of the generated code")
+}
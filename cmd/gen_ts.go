@@ -0,0 +1,67 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/k1LoW/envdo/gen"
+	"github.com/spf13/cobra"
+)
+
+var genTSZod bool
+
+// genTSCmd represents the gen ts command.
+var genTSCmd = &cobra.Command{
+	Use:   "ts",
+	Short: "Generate TypeScript typings of process.env",
+	Long: `Generate a TypeScript declaration file (env.d.ts) declaring the keys of process.env from a profile.
+
+With --zod, a module exporting a zod schema of the environment variables is generated instead.
+
+Examples:
+  envdo gen ts -o env.d.ts
+  envdo gen ts --zod -o src/env.ts`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		envs, _, err := loadEnvs(profile)
+		if err != nil {
+			return err
+		}
+		s, err := loadSchema()
+		if err != nil {
+			return fmt.Errorf("failed to load schema: %w", err)
+		}
+		return writeGenOutput(func(w io.Writer) error {
+			if genTSZod {
+				return gen.Zod(w, envs, s)
+			}
+			return gen.TypeScript(w, envs, s)
+		})
+	},
+}
+
+func init() {
+	genCmd.AddCommand(genTSCmd)
+	genTSCmd.Flags().BoolVar(&genTSZod, "zod", false, "generate a zod schema instead of a declaration file")
+}
@@ -0,0 +1,97 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/k1LoW/envdo/env"
+	"github.com/spf13/cobra"
+)
+
+var (
+	generateType  string
+	generateBytes int
+	generateForce bool
+)
+
+// generateCmd represents the generate command.
+var generateCmd = &cobra.Command{
+	Use:   "generate KEY [KEY...]",
+	Short: "Generate random values of keys into a profile",
+	Long: `Generate cryptographically random values of keys and write them into the profile file
+in the $XDG_CONFIG_HOME/envdo directory, for bootstrapping local secrets per machine.
+
+Keys already defined in the profile file are kept unless --force is given.
+The profile file is created with 0600 permissions.
+
+Types:
+  random  alphanumeric characters (--bytes characters)
+  hex     random bytes encoded in hex
+  base64  random bytes encoded in base64
+  uuid    a version 4 UUID (--bytes is ignored)
+
+Examples:
+  envdo generate SECRET_KEY
+  envdo generate SECRET_KEY --type hex --bytes 32 -p dev
+  envdo generate INSTANCE_ID --type uuid`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := defaultEnv().ProfilePath(profile)
+		var keys, kept []string
+		if err := env.ModifyFile(path, func(existing map[string]string) (map[string]string, error) {
+			generated := make(map[string]string)
+			for _, k := range args {
+				if _, ok := existing[k]; ok && !generateForce {
+					kept = append(kept, k)
+					continue
+				}
+				v, err := env.GenerateValue(generateType, generateBytes)
+				if err != nil {
+					return nil, err
+				}
+				generated[k] = v
+				keys = append(keys, k)
+			}
+			return generated, nil
+		}); err != nil {
+			return fmt.Errorf("failed to update %s: %w", path, err)
+		}
+		if len(kept) > 0 {
+			logger.Info(fmt.Sprintf("Kept %s already defined in %s (use --force to regenerate)", strings.Join(kept, ", "), path))
+		}
+		if len(keys) == 0 {
+			return nil
+		}
+		logger.Info(fmt.Sprintf("Generated %s into %s", strings.Join(keys, ", "), path))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(generateCmd)
+	generateCmd.Flags().StringVarP(&profile, "profile", "p", "", "profile name to write into")
+	generateCmd.Flags().StringVar(&generateType, "type", env.ValueTypeRandom, "type of the values ("+strings.Join(env.ValueTypes, ", ")+")")
+	generateCmd.Flags().IntVar(&generateBytes, "bytes", 32, "number of characters (random) or bytes (hex, base64) of the values")
+	generateCmd.Flags().BoolVar(&generateForce, "force", false, "regenerate the values of keys already defined in the profile file")
+}
@@ -0,0 +1,72 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// getCmd represents the get command.
+var getCmd = &cobra.Command{
+	Use:   "get KEY",
+	Short: "Print the value of a key in a .env file of a profile",
+	Long: `Print the value of a key as written in the .env file of a profile (chosen like envdo set).
+
+Unlike envdo which, the value is not merged with other files or resolved.
+
+Examples:
+  envdo get PORT
+  envdo get API_URL -p staging`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := editTarget()
+		if err != nil {
+			return err
+		}
+		e, err := newEnv()
+		if err != nil {
+			return err
+		}
+		entries, err := e.ReadEntries([]string{path})
+		if err != nil {
+			return err
+		}
+		for _, en := range entries {
+			if en.Key != args[0] {
+				continue
+			}
+			if en.Unset {
+				break
+			}
+			fmt.Println(en.Value)
+			return nil
+		}
+		return fmt.Errorf("%s is not defined in %s", args[0], path)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(getCmd)
+	addEditFlags(getCmd)
+}
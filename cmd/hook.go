@@ -0,0 +1,284 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/k1LoW/envdo/env"
+	"github.com/k1LoW/envdo/output"
+	"github.com/k1LoW/envdo/trust"
+	"github.com/spf13/cobra"
+)
+
+// hookStateEnv is the environment variable keeping the state of the shell hook.
+const hookStateEnv = "ENVDO_HOOK"
+
+var hookExport bool
+
+// hookScripts are the scripts of the shell hook by shell.
+var hookScripts = map[string]string{
+	output.ShellZsh: `_envdo_hook() {
+  trap -- '' SIGINT
+  eval "$(command envdo hook zsh --export)"
+  trap - SIGINT
+}
+typeset -ag precmd_functions
+if (( ! ${precmd_functions[(I)_envdo_hook]} )); then
+  precmd_functions=(_envdo_hook $precmd_functions)
+fi
+typeset -ag chpwd_functions
+if (( ! ${chpwd_functions[(I)_envdo_hook]} )); then
+  chpwd_functions=(_envdo_hook $chpwd_functions)
+fi
+`,
+	output.ShellBash: `_envdo_hook() {
+  local previous_exit_status=$?
+  trap -- '' SIGINT
+  eval "$(command envdo hook bash --export)"
+  trap - SIGINT
+  return $previous_exit_status
+}
+if [[ ";${PROMPT_COMMAND[*]:-};" != *";_envdo_hook;"* ]]; then
+  PROMPT_COMMAND="_envdo_hook${PROMPT_COMMAND:+;$PROMPT_COMMAND}"
+fi
+`,
+	output.ShellFish: `function __envdo_hook --on-variable PWD --on-event fish_prompt
+  command envdo hook fish --export | source
+end
+`,
+}
+
+// hookState is the state of the shell hook, kept in $ENVDO_HOOK of the shell.
+type hookState struct {
+	// Dir is the directory whose environment variables are loaded.
+	Dir string `json:"dir"`
+	// Fingerprint identifies the versions of the loaded files.
+	Fingerprint string `json:"fingerprint"`
+	// Previous are the values of the changed variables before loading, nil for unset variables.
+	Previous map[string]*string `json:"previous"`
+}
+
+// hookCmd represents the hook command.
+var hookCmd = &cobra.Command{
+	Use:   "hook SHELL",
+	Short: "Print the shell hook loading .env files automatically",
+	Long: `Print the shell hook that loads the environment variables of .env files when entering a directory
+and unloads them when leaving it, like direnv. Supported shells are zsh, bash and fish.
+
+The .env files of the nearest directory containing a .env file or .envdo.yml are loaded with the profile of .envdo.yml,
+only if they are allowed with envdo allow. The files must be allowed again after they change.
+
+Examples:
+  # ~/.zshrc
+  eval "$(envdo hook zsh)"
+
+  # ~/.bashrc
+  eval "$(envdo hook bash)"
+
+  # ~/.config/fish/config.fish
+  envdo hook fish | source`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{output.ShellZsh, output.ShellBash, output.ShellFish},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		script, ok := hookScripts[args[0]]
+		if !ok {
+			return fmt.Errorf("unsupported shell: %s (supported: zsh, bash, fish)", args[0])
+		}
+		if hookExport {
+			return writeHookChanges(os.Stdout, args[0])
+		}
+		_, err := io.WriteString(os.Stdout, script)
+		return err
+	},
+}
+
+// writeHookChanges writes the statements for the shell to load the environment variables of the current directory,
+// unloading those of the directory loaded before.
+func writeHookChanges(w io.Writer, shell string) error {
+	var state hookState
+	if s := os.Getenv(hookStateEnv); s != "" {
+		if b, err := base64.StdEncoding.DecodeString(s); err == nil {
+			_ = json.Unmarshal(b, &state)
+		}
+	}
+	pwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	dir := findHookDir(pwd)
+	var (
+		profile     string
+		files       []string
+		fingerprint string
+	)
+	if dir != "" {
+		if err := os.Chdir(dir); err != nil {
+			return err
+		}
+		profile, files, err = hookFiles()
+		if err != nil {
+			return err
+		}
+		allowed, err := allAllowed(files)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			logger.Warn(fmt.Sprintf("%s is not allowed to be loaded; review it and run envdo allow", strings.Join(files, ", ")))
+			files = nil
+		}
+		if len(files) == 0 {
+			dir = ""
+		}
+	}
+	if dir != "" {
+		e, err := newEnv()
+		if err != nil {
+			return err
+		}
+		fingerprint = fileFingerprint(append(e.Files(profile), files...))
+	}
+	if dir == state.Dir && fingerprint == state.Fingerprint {
+		return nil
+	}
+
+	// Restore the variables changed for the directory loaded before
+	set := make(map[string]string)
+	var unset []string
+	for k, v := range state.Previous {
+		if v == nil {
+			unset = append(unset, k)
+			continue
+		}
+		set[k] = *v
+	}
+	if dir == "" {
+		if state.Dir != "" {
+			logger.Info(fmt.Sprintf("Unloaded %s", state.Dir))
+		}
+		unset = append(unset, hookStateEnv)
+		slices.Sort(unset)
+		return output.WriteEnvChanges(w, shell, set, unset)
+	}
+
+	// Load on top of the environment before loading the directory loaded before, so list values
+	// (e.g. PATH+=./bin) and references to variables extend the saved values instead of the loaded ones
+	for k, v := range state.Previous {
+		if v == nil {
+			_ = os.Unsetenv(k)
+			continue
+		}
+		_ = os.Setenv(k, *v)
+	}
+	envs, order, err := loadEnvs(profile)
+	if err != nil {
+		return err
+	}
+	envs, _, err = transformKeys(envs, order)
+	if err != nil {
+		return err
+	}
+	next := hookState{Dir: dir, Fingerprint: fingerprint, Previous: make(map[string]*string)}
+	previous := func(k string) *string {
+		if v, ok := state.Previous[k]; ok {
+			return v
+		}
+		if v, ok := os.LookupEnv(k); ok {
+			return &v
+		}
+		return nil
+	}
+	for k, v := range envs {
+		next.Previous[k] = previous(k)
+		set[k] = v
+	}
+	unset = slices.DeleteFunc(unset, func(k string) bool {
+		_, ok := envs[k]
+		return ok
+	})
+	for _, k := range unsetKeys {
+		if _, ok := envs[k]; ok {
+			continue
+		}
+		next.Previous[k] = previous(k)
+		delete(set, k)
+		if !slices.Contains(unset, k) {
+			unset = append(unset, k)
+		}
+	}
+	b, err := json.Marshal(next)
+	if err != nil {
+		return err
+	}
+	set[hookStateEnv] = base64.StdEncoding.EncodeToString(b)
+	slices.Sort(unset)
+	logger.Info(fmt.Sprintf("Loaded %s", strings.Join(files, ", ")))
+	return output.WriteEnvChanges(w, shell, set, unset)
+}
+
+// allAllowed reports whether all files are allowed by envdo allow.
+func allAllowed(files []string) (bool, error) {
+	s, err := trust.Open(trust.DefaultPath())
+	if err != nil {
+		return false, fmt.Errorf("failed to open the allowed files: %w", err)
+	}
+	for _, f := range files {
+		ok, err := s.Allowed(f)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// fileFingerprint returns a digest of the paths, sizes and modification times of the local files.
+func fileFingerprint(files []string) string {
+	h := sha256.New()
+	for _, f := range files {
+		if env.IsRemote(f) {
+			_, _ = fmt.Fprintln(h, f)
+			continue
+		}
+		fi, err := os.Stat(f)
+		if err != nil {
+			_, _ = fmt.Fprintln(h, f)
+			continue
+		}
+		_, _ = fmt.Fprintln(h, f, fi.Size(), fi.ModTime().UnixNano())
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func init() {
+	rootCmd.AddCommand(hookCmd)
+	hookCmd.Flags().BoolVar(&hookExport, "export", false, "print the statements to load the environment variables of the current directory (used by the hook)")
+	_ = hookCmd.Flags().MarkHidden("export")
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/k1LoW/envdo/trust"
+)
+
+// evalHookChanges applies the statements written by the bash hook to the environment of the process, like the shell.
+func evalHookChanges(t *testing.T, out string) {
+	t.Helper()
+	for line := range strings.Lines(out) {
+		line = strings.TrimSpace(line)
+		if k, ok := strings.CutPrefix(line, "unset "); ok {
+			if err := os.Unsetenv(k); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		kv, ok := strings.CutPrefix(line, "export ")
+		if !ok {
+			t.Fatalf("unexpected statement: %s", line)
+		}
+		k, v, _ := strings.Cut(kv, "=")
+		t.Setenv(k, strings.Trim(v, "'"))
+	}
+}
+
+func TestHookReloadList(t *testing.T) {
+	for _, k := range []string{"XDG_CONFIG_HOME", "XDG_STATE_HOME", "XDG_CACHE_HOME"} {
+		t.Setenv(k, t.TempDir())
+	}
+	dir := t.TempDir()
+	t.Chdir(dir)
+	path := filepath.Join(dir, ".env")
+	if err := os.WriteFile(path, []byte("LIST+=b\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	allow := func() {
+		s, err := trust.Open(trust.DefaultPath())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := s.Allow(path); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.Save(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	allow()
+	t.Setenv("LIST", "a")
+	t.Setenv(hookStateEnv, "")
+
+	want := "a" + string(os.PathListSeparator) + "b"
+	for i := range 3 {
+		if i > 0 {
+			// Reload after the file changes
+			mtime := time.Now().Add(time.Duration(i) * time.Second)
+			if err := os.Chtimes(path, mtime, mtime); err != nil {
+				t.Fatal(err)
+			}
+		}
+		buf := new(bytes.Buffer)
+		if err := writeHookChanges(buf, "bash"); err != nil {
+			t.Fatal(err)
+		}
+		evalHookChanges(t, buf.String())
+		if got := os.Getenv("LIST"); got != want {
+			t.Errorf("load %d: got LIST=%q, want %q", i+1, got, want)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/k1LoW/envdo/env"
+	"github.com/spf13/cobra"
+)
+
+// importCmd represents the import command.
+var importCmd = &cobra.Command{
+	Use:   "import [PATTERN...]",
+	Short: "Import environment variables into a profile",
+	Long: `Import environment variables from external sources into a profile file
+in the $XDG_CONFIG_HOME/envdo directory.
+
+With patterns, the variables of the current environment whose keys match one of them
+(glob patterns such as AWS_*; quote them not to be expanded by the shell) are imported.
+
+Existing keys in the profile file are overwritten and other lines are kept.
+The profile file is created with 0600 permissions.
+
+Examples:
+  envdo import 'AWS_*' -p sandbox
+  envdo import GITHUB_TOKEN NPM_TOKEN`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		envs, err := matchEnviron(os.Environ(), args)
+		if err != nil {
+			return err
+		}
+		return writeProfile(profile, envs)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+	importCmd.Flags().StringVarP(&profile, "profile", "p", "", "profile name to import into")
+}
+
+// matchEnviron returns the variables of environ (KEY=value) whose keys match one of patterns.
+// It returns an error if a pattern matches no variables.
+func matchEnviron(environ, patterns []string) (map[string]string, error) {
+	envs := make(map[string]string)
+	for _, p := range patterns {
+		matched := false
+		for _, kv := range environ {
+			k, v, _ := strings.Cut(kv, "=")
+			ok, err := path.Match(p, k)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern %s: %w", p, err)
+			}
+			if ok {
+				envs[k] = v
+				matched = true
+			}
+		}
+		if !matched {
+			return nil, fmt.Errorf("no environment variables match %s", p)
+		}
+	}
+	return envs, nil
+}
+
+// writeProfile writes envs into the profile file in the config directory.
+func writeProfile(profile string, envs map[string]string) error {
+	path := defaultEnv().ProfilePath(profile)
+	if err := env.UpdateFile(path, envs); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	logger.Info(fmt.Sprintf("Imported %d variables into %s", len(envs), path))
+	return nil
+}
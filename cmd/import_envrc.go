@@ -0,0 +1,63 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/k1LoW/envdo/env"
+	"github.com/spf13/cobra"
+)
+
+// importEnvrcCmd represents the import envrc command.
+var importEnvrcCmd = &cobra.Command{
+	Use:   "envrc [PATH]",
+	Short: "Import variables from a direnv .envrc file",
+	Long: `Import variables from a direnv .envrc file into a profile.
+
+Only 'export KEY=value' (and 'KEY=value') assignments, 'dotenv' and 'dotenv_if_exists'
+are evaluated. Other statements are skipped with a warning.
+
+Examples:
+  envdo import envrc
+  envdo import envrc ./services/api/.envrc -p api`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := ".envrc"
+		if len(args) > 0 {
+			path = args[0]
+		}
+		envs, warnings, err := env.LoadEnvrc(path)
+		if err != nil {
+			return fmt.Errorf("failed to load %s: %w", path, err)
+		}
+		for _, w := range warnings {
+			logger.Warn(w)
+		}
+		return writeProfile(profile, envs)
+	},
+}
+
+func init() {
+	importCmd.AddCommand(importEnvrcCmd)
+	importEnvrcCmd.Flags().StringVarP(&profile, "profile", "p", "", "profile name to import into")
+}
@@ -0,0 +1,56 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"github.com/k1LoW/envdo/platform"
+	"github.com/spf13/cobra"
+)
+
+var flyApp string
+
+// importFlyCmd represents the import fly command.
+var importFlyCmd = &cobra.Command{
+	Use:   "fly",
+	Short: "Import app secrets from Fly.io",
+	Long: `Import app secrets from Fly.io into a profile using flyctl.
+
+Fly.io does not expose secret values through its API, so the values are read
+from a running machine of the app via 'flyctl ssh console'.
+
+Examples:
+  envdo import fly --app my-app -p production`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		envs, err := platform.NewFly().Secrets(cmd.Context(), flyApp)
+		if err != nil {
+			return err
+		}
+		return writeProfile(profile, envs)
+	},
+}
+
+func init() {
+	importCmd.AddCommand(importFlyCmd)
+	importFlyCmd.Flags().StringVarP(&profile, "profile", "p", "", "profile name to import into")
+	importFlyCmd.Flags().StringVarP(&flyApp, "app", "a", "", "Fly.io app name")
+}
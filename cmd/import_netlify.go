@@ -0,0 +1,69 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/k1LoW/envdo/platform"
+	"github.com/spf13/cobra"
+)
+
+var (
+	netlifySite    string
+	netlifyAccount string
+	netlifyContext string
+)
+
+// importNetlifyCmd represents the import netlify command.
+var importNetlifyCmd = &cobra.Command{
+	Use:   "netlify",
+	Short: "Import site environment variables from Netlify",
+	Long: `Import site environment variables of a Netlify deploy context into a profile.
+
+The Netlify API is accessed with the personal access token in $NETLIFY_AUTH_TOKEN.
+
+Examples:
+  envdo import netlify --site my-site-id --context production -p production
+  NETLIFY_SITE_ID=my-site-id envdo import netlify --context deploy-preview -p preview`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		token := os.Getenv("NETLIFY_AUTH_TOKEN")
+		if token == "" {
+			return fmt.Errorf("NETLIFY_AUTH_TOKEN is not set")
+		}
+		envs, err := platform.NewNetlify(token).Envs(cmd.Context(), netlifySite, netlifyAccount, netlifyContext)
+		if err != nil {
+			return err
+		}
+		return writeProfile(profile, envs)
+	},
+}
+
+func init() {
+	importCmd.AddCommand(importNetlifyCmd)
+	importNetlifyCmd.Flags().StringVarP(&profile, "profile", "p", "", "profile name to import into")
+	importNetlifyCmd.Flags().StringVar(&netlifySite, "site", os.Getenv("NETLIFY_SITE_ID"), "Netlify site id (default $NETLIFY_SITE_ID)")
+	importNetlifyCmd.Flags().StringVar(&netlifyAccount, "account", "", "Netlify account id (looked up from the site if empty)")
+	importNetlifyCmd.Flags().StringVar(&netlifyContext, "context", "production", "deploy context (production, deploy-preview, branch-deploy, dev)")
+}
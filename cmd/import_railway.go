@@ -0,0 +1,70 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/k1LoW/envdo/platform"
+	"github.com/spf13/cobra"
+)
+
+var (
+	railwayProject     string
+	railwayEnvironment string
+	railwayService     string
+)
+
+// importRailwayCmd represents the import railway command.
+var importRailwayCmd = &cobra.Command{
+	Use:   "railway",
+	Short: "Import variables from Railway",
+	Long: `Import variables of a Railway project environment (and service) into a profile.
+
+The Railway API is accessed with the project token in $RAILWAY_TOKEN
+or the account/team token in $RAILWAY_API_TOKEN.
+
+Examples:
+  envdo import railway --project PROJECT_ID --environment ENVIRONMENT_ID --service SERVICE_ID -p production`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		token := os.Getenv("RAILWAY_API_TOKEN")
+		projectToken := os.Getenv("RAILWAY_TOKEN")
+		if token == "" && projectToken == "" {
+			return fmt.Errorf("RAILWAY_TOKEN or RAILWAY_API_TOKEN is not set")
+		}
+		envs, err := platform.NewRailway(token, projectToken).Variables(cmd.Context(), railwayProject, railwayEnvironment, railwayService)
+		if err != nil {
+			return err
+		}
+		return writeProfile(profile, envs)
+	},
+}
+
+func init() {
+	importCmd.AddCommand(importRailwayCmd)
+	importRailwayCmd.Flags().StringVarP(&profile, "profile", "p", "", "profile name to import into")
+	importRailwayCmd.Flags().StringVar(&railwayProject, "project", "", "Railway project id")
+	importRailwayCmd.Flags().StringVar(&railwayEnvironment, "environment", "", "Railway environment id")
+	importRailwayCmd.Flags().StringVar(&railwayService, "service", "", "Railway service id (shared variables if empty)")
+}
@@ -0,0 +1,121 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/k1LoW/envdo/env"
+	"github.com/k1LoW/envdo/output"
+	"github.com/k1LoW/envdo/schema"
+	"github.com/spf13/cobra"
+)
+
+var listFormat string
+
+// listCmd represents the list command.
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List resolved environment variables with their origins",
+	Long: `List the merged environment variables of a profile, showing which file each key
+came from and which definitions it overrides.
+
+Examples:
+  envdo list
+  envdo list -p production --format json
+  envdo list --mask`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		e, err := newEnv()
+		if err != nil {
+			return err
+		}
+		if err := guardPwdEnv(e, profile); err != nil {
+			return err
+		}
+		if profile != "" && len(e.Files(profile)) == 0 {
+			return fmt.Errorf("environment file %s not found in any search directory", env.Filename(profile))
+		}
+		entries, err := e.Entries(profile)
+		if err != nil {
+			return err
+		}
+		vars := env.ResolveVars(entries)
+		m, err := newMasker()
+		if err != nil {
+			return err
+		}
+		if m != nil {
+			for i := range vars {
+				if m.IsMasked(vars[i].Key) {
+					vars[i].Value = output.MaskedValue
+				}
+			}
+		}
+		switch listFormat {
+		case "table":
+			tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(tw, "KEY\tVALUE\tSOURCE\tOVERRIDES")
+			for _, v := range vars {
+				overrides := make([]string, 0, len(v.Overrides))
+				for _, o := range v.Overrides {
+					overrides = append(overrides, fmt.Sprintf("%s:%d", o.File, o.Line))
+				}
+				_, _ = fmt.Fprintf(tw, "%s\t%s\t%s:%d\t%s\n", v.Key, strings.ReplaceAll(v.Value, "\n", `\n`), v.File, v.Line, strings.Join(overrides, ", "))
+			}
+			return tw.Flush()
+		case "json":
+			if vars == nil {
+				vars = []env.Var{}
+			}
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(vars)
+		case "dotenv":
+			envs := make(map[string]string, len(vars))
+			for _, v := range vars {
+				envs[v.Key] = v.Value
+			}
+			b, err := env.Marshal(envs)
+			if err != nil {
+				return err
+			}
+			_, err = os.Stdout.Write(b)
+			return err
+		default:
+			return fmt.Errorf("unsupported format: %s", listFormat)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(listCmd)
+	listCmd.Flags().StringVarP(&profile, "profile", "p", "", "profile name")
+	listCmd.Flags().StringVar(&listFormat, "format", "table", "output format (table, json, dotenv)")
+	listCmd.Flags().BoolVar(&mask, "mask", false, "mask values of secret keys (TOKEN, SECRET, PASSWORD, KEY, ...) with ***")
+	listCmd.Flags().StringArrayVar(&maskPatterns, "mask-pattern", nil, "mask values of keys matching the regular expression (implies --mask)")
+	listCmd.Flags().StringVar(&schemaPath, "schema", "", "schema file path (default "+schema.Filename+" in the current directory)")
+}
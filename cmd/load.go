@@ -0,0 +1,659 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/k1LoW/envdo/auditlog"
+	"github.com/k1LoW/envdo/cache"
+	"github.com/k1LoW/envdo/check"
+	"github.com/k1LoW/envdo/config"
+	"github.com/k1LoW/envdo/env"
+	"github.com/k1LoW/envdo/env/provider"
+	"github.com/k1LoW/envdo/output"
+	"github.com/k1LoW/envdo/platform"
+	"github.com/k1LoW/envdo/trust"
+)
+
+// stdinEnvFile is the --env-file reading .env format from stdin.
+const stdinEnvFile = "-"
+
+var (
+	forbidPwdEnv bool
+	walkUp       bool
+	envDirs      []string
+	baseProfile  string
+	strict       bool
+	strictPerms  bool
+	auditLog     bool
+
+	diagnosticsFormat string
+
+	mask         bool
+	maskPatterns []string
+
+	unset        []string
+	envURLs      []string
+	fromK8s      []string
+	k8sNamespace string
+	noCache      bool
+	allowExec    bool
+	noExpand     bool
+
+	// unsetKeys are the keys removed from the environment of commands, set by finishLoad.
+	unsetKeys []string
+	// stdinEntries are the definitions read from stdin by --env-file -, read once by finishLoad.
+	stdinEntries []env.Entry
+	stdinRead    bool
+)
+
+// newEnv creates the environment loader with the base profile given by --base,
+// searching parent directories with --up (or walk_up of the configuration) and the directories
+// given by --env-dir.
+// The env_files and unset of the configuration found by loadConfig and --unset are applied, and an invalid $DOTENV_KEY is an error.
+func newEnv() (*env.Env, error) {
+	c, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	e := defaultEnv()
+	e.SetLogger(logger)
+	if k := os.Getenv(env.DotenvKeyEnv); k != "" {
+		v, err := env.NewVault(k)
+		if err != nil {
+			return nil, err
+		}
+		e.SetVault(v)
+	}
+	if baseProfile != "" {
+		e.SetBase(baseProfile)
+	}
+	e.SetWalkUp(walkUp || c.WalkUp)
+	dirs, err := envDirPaths()
+	if err != nil {
+		return nil, err
+	}
+	e.SetEnvDirs(dirs)
+	e.SetExtraFiles(c.EnvFiles)
+	e.SetUnset(slices.Concat(c.Unset, unset))
+	return e, nil
+}
+
+// envDirPaths returns the absolute paths of the directories given by --env-dir.
+func envDirPaths() ([]string, error) {
+	var dirs []string
+	for _, d := range envDirs {
+		abs, err := filepath.Abs(d)
+		if err != nil {
+			return nil, err
+		}
+		fi, err := os.Stat(abs)
+		if err != nil {
+			return nil, fmt.Errorf("invalid env dir: %w", err)
+		}
+		if !fi.IsDir() {
+			return nil, fmt.Errorf("invalid env dir: %s is not a directory", d)
+		}
+		if !slices.Contains(dirs, abs) {
+			dirs = append(dirs, abs)
+		}
+	}
+	return dirs, nil
+}
+
+// loadEnvs loads the environment variables of the profile, and returns them with their keys in definition order.
+func loadEnvs(profile string) (map[string]string, []string, error) {
+	e, err := newEnv()
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := guardPwdEnv(e, profile); err != nil {
+		return nil, nil, err
+	}
+	if err := generateRandomValues(e.Files(profile)); err != nil {
+		return nil, nil, err
+	}
+	envs, err := e.LoadEnvFiles(profile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load environment variables: %w", err)
+	}
+	return finishLoad(e, e.Files(profile), envs)
+}
+
+// explicitEnvFiles returns the files given by --env-file except "-" (stdin), which finishLoad reads.
+func explicitEnvFiles() []string {
+	return slices.DeleteFunc(slices.Clone(envFiles), func(f string) bool { return f == stdinEnvFile })
+}
+
+// readStdinEntries reads the definitions in .env format from stdin in priority order if --env-file - is given.
+// Stdin is read only once, so reloads of --watch get the same definitions.
+func readStdinEntries() ([]env.Entry, error) {
+	if stdinRead || !slices.Contains(envFiles, stdinEnvFile) {
+		return stdinEntries, nil
+	}
+	entries, err := env.ParseEntries(os.Stdin, stdinEnvFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read environment variables from stdin: %w", err)
+	}
+	stdinEntries, stdinRead = entries, true
+	return entries, nil
+}
+
+// loadEnvFileArgs loads the files given by --env-file. Later files override earlier ones.
+// Files may be URLs of remote env files (https://, s3:// and gs://).
+func loadEnvFileArgs(files []string) (map[string]string, []string, error) {
+	var local []string
+	for _, f := range files {
+		if env.IsRemote(f) {
+			continue
+		}
+		if _, err := os.Stat(f); err != nil {
+			return nil, nil, fmt.Errorf("failed to load environment variables: %w", err)
+		}
+		local = append(local, f)
+	}
+	e, err := newEnv()
+	if err != nil {
+		return nil, nil, err
+	}
+	priority := slices.Clone(local)
+	slices.Reverse(priority)
+	if err := generateRandomValues(priority); err != nil {
+		return nil, nil, err
+	}
+	envs, err := e.LoadFiles(files)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load environment variables: %w", err)
+	}
+	return finishLoad(e, priority, envs)
+}
+
+// loadEnvURLs adds the variables of the remote env files given by --env-url to envs
+// with lower priority than envs, except the unset keys. Later URLs override earlier ones.
+func loadEnvURLs(e *env.Env, envs map[string]string, unset []string) error {
+	if len(envURLs) == 0 {
+		return nil
+	}
+	for _, u := range envURLs {
+		if !env.IsRemote(u) {
+			return fmt.Errorf("invalid --env-url %s: the scheme must be one of %s", u, strings.Join(env.RemoteSchemes, ", "))
+		}
+	}
+	remote, err := e.LoadFiles(envURLs)
+	if err != nil {
+		return fmt.Errorf("failed to load environment variables: %w", err)
+	}
+	for k, v := range remote {
+		if _, ok := envs[k]; !ok && !slices.Contains(unset, k) {
+			envs[k] = v
+		}
+	}
+	return nil
+}
+
+// loadK8s adds the data of the Kubernetes Secrets and ConfigMaps given by --from-k8s to envs
+// with lower priority than envs, except the unset keys. Later resources override earlier ones.
+func loadK8s(envs map[string]string, unset []string) error {
+	if len(fromK8s) == 0 {
+		return nil
+	}
+	kube := platform.NewKubernetes()
+	data := make(map[string]string)
+	for _, r := range fromK8s {
+		d, err := kube.Data(context.Background(), k8sNamespace, r)
+		if err != nil {
+			return fmt.Errorf("failed to load environment variables: %w", err)
+		}
+		logger.Debug("merged a Kubernetes resource", "resource", r, "keys", len(d))
+		maps.Copy(data, d)
+	}
+	for k, v := range data {
+		if _, ok := envs[k]; !ok && !slices.Contains(unset, k) {
+			envs[k] = v
+		}
+	}
+	return nil
+}
+
+// finishLoad merges the variables read from stdin by --env-file - into envs loaded from files given in priority order
+// at the highest priority, adds the variables of --env-url and --from-k8s,
+// reports diagnostics of the files, generates values of envs, expands variables in them unless --no-expand is given,
+// resolves secret references (e.g. op://vault/item/field) in them with the providers enabled by .envdo.yml,
+// runs the allowed command substitutions and checks the values.
+// It returns envs with the keys in definition order, in which the variables are written and passed to commands.
+func finishLoad(e *env.Env, files []string, envs map[string]string) (map[string]string, []string, error) {
+	entries, err := e.ReadEntries(files)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Values are generated only for the directives of the .env files, not for those of stdin, --env-url and --from-k8s
+	fileVars := env.ResolveVars(entries)
+	stdin, err := readStdinEntries()
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(stdin) > 0 {
+		for _, v := range env.ResolveVars(stdin) {
+			envs[v.Key] = v.Value
+		}
+		for _, k := range env.UnsetKeys(stdin) {
+			delete(envs, k)
+		}
+		entries = slices.Concat(stdin, entries)
+	}
+	unsetKeys = slices.Concat(e.Unset(), env.UnsetKeys(entries))
+	if err := loadEnvURLs(e, envs, unsetKeys); err != nil {
+		return nil, nil, err
+	}
+	if err := loadK8s(envs, unsetKeys); err != nil {
+		return nil, nil, err
+	}
+	if err := reportDiagnostics(e, files, envs); err != nil {
+		return nil, nil, err
+	}
+	if err := env.GenerateValues(envs, fileVars, time.Now()); err != nil {
+		return nil, nil, err
+	}
+	if !noExpand {
+		if err := expandValues(entries, envs); err != nil {
+			return nil, nil, err
+		}
+	}
+	commands, err := commandSubstitutions(entries, envs)
+	if err != nil {
+		return nil, nil, err
+	}
+	c, err := loadConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+	r, err := provider.Available(c.Providers...)
+	if err != nil {
+		return nil, nil, err
+	}
+	if r.HasReferences(envs) {
+		if c.Concurrency > 0 {
+			r.SetConcurrency(c.Concurrency)
+		}
+		sc := openSecretCache(c)
+		if sc != nil {
+			r.SetCache(sc)
+		}
+		if err := r.Resolve(context.Background(), envs); err != nil {
+			return nil, nil, err
+		}
+		if sc != nil {
+			if err := sc.Save(); err != nil {
+				logger.Warn(fmt.Sprintf("failed to save the secret cache: %v", err))
+			}
+		}
+	}
+	if err := env.SubstituteCommands(context.Background(), envs, commands); err != nil {
+		return nil, nil, err
+	}
+	if err := checkValues(envs, c.MaxValueSize); err != nil {
+		return nil, nil, err
+	}
+	return envs, env.DefinitionOrder(entries), nil
+}
+
+// checkValues reports the values of envs larger than maxValueSize bytes (max_value_size of .envdo.yml)
+// or containing binary or control characters. NUL bytes, or any problem with --strict, are errors.
+func checkValues(envs map[string]string, maxValueSize int) error {
+	var errs []error
+	for _, i := range env.CheckValues(envs, maxValueSize) {
+		if i.Severity == env.SeverityError || strict {
+			errs = append(errs, errors.New(i.Message))
+			continue
+		}
+		logger.Warn(i.Message)
+	}
+	return errors.Join(errs...)
+}
+
+// expandValues expands $VAR and ${VAR} in the values of envs defined in entries given in priority order,
+// before secret references are resolved. Single-quoted values, command substitutions, generated values
+// and the values of --env-url and --from-k8s are kept as they are.
+func expandValues(entries []env.Entry, envs map[string]string) error {
+	defined := make(map[string]env.Var)
+	for _, v := range env.ResolveVars(entries) {
+		defined[v.Key] = v
+	}
+	vars := make([]env.Var, 0, len(envs))
+	for _, k := range slices.Sorted(maps.Keys(envs)) {
+		v, ok := defined[k]
+		_, command := env.CommandSubstitution(envs[k])
+		vars = append(vars, env.Var{Key: k, Value: envs[k], File: v.File, Line: v.Line, Literal: !ok || v.Literal || command || v.Value != envs[k]})
+	}
+	expanded, err := env.Expand(vars)
+	if err != nil {
+		return err
+	}
+	for _, v := range expanded {
+		envs[v.Key] = v.Value
+	}
+	return nil
+}
+
+// commandSubstitutions returns the keys of the command substitutions ($(command) values) of envs defined in
+// the local .env files of entries given in priority order, except single-quoted values. They are found before
+// secret references are resolved, so that resolved values are never run as commands.
+// It returns an error if the files of any of them are not allowed by --allow-exec or envdo allow,
+// so cloning a repository never runs its commands.
+func commandSubstitutions(entries []env.Entry, envs map[string]string) ([]string, error) {
+	var (
+		keys, denied, deniedFiles []string
+		store                     *trust.Store
+	)
+	for _, v := range env.ResolveVars(entries) {
+		if _, ok := env.CommandSubstitution(v.Value); !ok || v.Literal || envs[v.Key] != v.Value || env.IsRemote(v.File) {
+			continue
+		}
+		if !allowExec {
+			if store == nil {
+				s, err := trust.Open(trust.DefaultPath())
+				if err != nil {
+					return nil, fmt.Errorf("failed to open the allowed files: %w", err)
+				}
+				store = s
+			}
+			if ok, _ := store.Allowed(v.File); !ok {
+				denied = append(denied, v.Key)
+				if !slices.Contains(deniedFiles, v.File) {
+					deniedFiles = append(deniedFiles, v.File)
+				}
+				continue
+			}
+		}
+		keys = append(keys, v.Key)
+	}
+	if len(denied) > 0 {
+		return nil, fmt.Errorf("the command substitutions of %s in %s are not allowed; review the files and run them with --allow-exec or allow them with envdo allow %s", strings.Join(denied, ", "), strings.Join(deniedFiles, ", "), strings.Join(deniedFiles, " "))
+	}
+	return keys, nil
+}
+
+// openSecretCache opens the cache of resolved secret values with the TTL of cache_ttl of .envdo.yml.
+// It returns nil if the cache is disabled by --no-cache or cache_ttl: 0,
+// or if the key of the cache is not available in the OS credential store.
+func openSecretCache(c *config.Config) *cache.Cache {
+	if noCache {
+		return nil
+	}
+	ttl := cache.DefaultTTL
+	if c.CacheTTL != "" {
+		ttl, _ = time.ParseDuration(c.CacheTTL)
+	}
+	if ttl == 0 {
+		return nil
+	}
+	key, err := cache.Key(context.Background(), provider.NewKeychain())
+	if err != nil {
+		logger.Debug("disabled the secret cache without the key in the OS credential store", "error", err)
+		return nil
+	}
+	sc, err := cache.Open(cache.DefaultPath(), key, ttl)
+	if err != nil {
+		logger.Debug("disabled the secret cache", "error", err)
+		return nil
+	}
+	return sc
+}
+
+// generateRandomValues generates values of random directives (KEY=random:N) in files.
+func generateRandomValues(files []string) error {
+	for _, f := range files {
+		keys, err := env.GenerateRandomValues(f)
+		if err != nil {
+			return fmt.Errorf("failed to generate random values in %s: %w", f, err)
+		}
+		for _, k := range keys {
+			logger.Info(fmt.Sprintf("Generated a random value of %s in %s", k, f))
+		}
+	}
+	return nil
+}
+
+// reportDiagnostics reports problems of the .env files given in priority order and envs loaded from them to stderr.
+// By default problems of the syntax and key names (invalid characters, lowercase letters, leading digits
+// and duplicate keys in a file), keys past or near their expiry dates and missing required keys
+// (including required of .envdo.yml) are reported as warnings, and so are files accessible by other users
+// or owned by another user like ssh checks keys.
+// With --diagnostics json, all problems (lint, permission, shadowed definitions, expiry and required keys)
+// are written as a JSON array.
+// It returns an error if any of them are found and --strict is given, or if the permissions are too open
+// and --strict-perms is given.
+func reportDiagnostics(e *env.Env, files []string, envs map[string]string) error {
+	lint, err := check.LintFiles(files)
+	if err != nil {
+		return err
+	}
+	expiry, err := check.ExpiryDates(files, time.Now())
+	if err != nil {
+		return err
+	}
+	s, err := loadSchema()
+	if err != nil {
+		return fmt.Errorf("failed to load schema: %w", err)
+	}
+	c, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	required, err := check.RequiredKeys(files, envs, s, c.Required)
+	if err != nil {
+		return err
+	}
+	perms, err := check.Permissions(slices.DeleteFunc(slices.Clone(files), env.IsRemote))
+	if err != nil {
+		return err
+	}
+	// Group-accessible files are only reported by the json diagnostics, as they are common on shared checkouts
+	openPerms := slices.DeleteFunc(slices.Clone(perms), func(d check.Diagnostic) bool { return d.Severity != env.SeverityError })
+	switch diagnosticsFormat {
+	case "":
+		for _, d := range slices.Concat(lint, expiry, openPerms) {
+			logger.Warn(formatDiagnostic(d))
+		}
+		for _, d := range required {
+			logger.Warn(d.Message)
+		}
+	case "json":
+		ds := slices.Concat(lint, perms)
+		entries, err := e.ReadEntries(files)
+		if err != nil {
+			return err
+		}
+		ds = append(ds, check.Shadowed(entries)...)
+		ds = append(ds, expiry...)
+		ds = append(ds, required...)
+		if err := check.WriteJSON(os.Stderr, ds); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported diagnostics format: %s", diagnosticsFormat)
+	}
+	if strictPerms && len(openPerms) > 0 {
+		return fmt.Errorf("refused to load the .env files accessible by other users or owned by another user (%d problems); run chmod 600 on them (--strict-perms)", len(openPerms))
+	}
+	if strict && len(lint) > 0 {
+		return fmt.Errorf("found %d problems such as invalid keys in the .env files (--strict)", len(lint))
+	}
+	if strict && len(expiry) > 0 {
+		return fmt.Errorf("found %d expired or expiring keys (--strict)", len(expiry))
+	}
+	if strict && len(required) > 0 {
+		keys := make([]string, 0, len(required))
+		for _, d := range required {
+			keys = append(keys, d.Key)
+		}
+		return fmt.Errorf("required keys are missing or empty: %s (--strict)", strings.Join(keys, ", "))
+	}
+	return nil
+}
+
+// formatDiagnostic formats d as "file:line: message".
+func formatDiagnostic(d check.Diagnostic) string {
+	if d.Line == 0 {
+		return fmt.Sprintf("%s: %s", d.File, d.Message)
+	}
+	return fmt.Sprintf("%s:%d: %s", d.File, d.Line, d.Message)
+}
+
+// transformKeys transforms the keys of envs by --key-case (or key_case of .envdo.yml),
+// the rename mapping of .envdo.yml and --rename, --strip-prefix and --prefix in this order.
+// It returns the transformed envs with order, the keys in definition order, transformed likewise.
+func transformKeys(envs map[string]string, order []string) (map[string]string, []string, error) {
+	transform, err := keyTransform()
+	if err != nil {
+		return nil, nil, err
+	}
+	envs, err = transform(envs)
+	if err != nil {
+		return nil, nil, err
+	}
+	origins, err := keyOrigins(transform, order)
+	if err != nil {
+		return nil, nil, err
+	}
+	index := make(map[string]int, len(order))
+	for i, k := range order {
+		index[k] = i
+	}
+	return envs, slices.SortedFunc(maps.Keys(origins), func(a, b string) int {
+		return cmp.Compare(index[origins[a]], index[origins[b]])
+	}), nil
+}
+
+// keyTransform returns the function transforming the keys of envs by --key-case (or key_case of .envdo.yml),
+// the rename mapping of .envdo.yml and --rename, --strip-prefix and --prefix in this order.
+func keyTransform() (func(map[string]string) (map[string]string, error), error) {
+	c, err := loadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	kc := keyCase
+	if kc == "" {
+		kc = c.KeyCase
+	}
+	mapping := maps.Clone(c.Rename)
+	if mapping == nil {
+		mapping = make(map[string]string)
+	}
+	maps.Copy(mapping, rename)
+	return func(envs map[string]string) (map[string]string, error) {
+		envs, err := env.TransformCase(envs, kc)
+		if err != nil {
+			return nil, err
+		}
+		envs = env.Rename(envs, mapping)
+		envs = env.StripPrefix(envs, stripPrefix)
+		return env.AddPrefix(envs, prefix), nil
+	}, nil
+}
+
+// keyOrigins returns the keys transformed by transform mapped to the original keys.
+func keyOrigins(transform func(map[string]string) (map[string]string, error), keys []string) (map[string]string, error) {
+	// Transform the keys mapped to themselves to know the original key of each transformed key.
+	origins := make(map[string]string, len(keys))
+	for _, k := range keys {
+		origins[k] = k
+	}
+	return transform(origins)
+}
+
+// loadConfig loads the configuration found like the .env files: .envdo.yml in the current directory
+// (or the nearest parent directory with --up) layered on config.yml in the configuration directory of envdo.
+func loadConfig() (*config.Config, error) {
+	pwd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	return config.Discover(pwd, walkUp, envdoConfigDir())
+}
+
+// newMasker returns the masker of printed values, or nil if neither --mask nor --mask-pattern is given.
+func newMasker() (*output.Masker, error) {
+	if !mask && len(maskPatterns) == 0 {
+		return nil, nil
+	}
+	return secretMasker()
+}
+
+// secretMasker returns the masker of the values of secret keys: keys classified as secrets by the schema
+// and keys matching mask_patterns of .envdo.yml or --mask-pattern.
+func secretMasker() (*output.Masker, error) {
+	s, err := loadSchema()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load schema: %w", err)
+	}
+	c, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	return output.NewMasker(s, slices.Concat(c.MaskPatterns, maskPatterns))
+}
+
+// guardPwdEnv returns an error if a .env file of the profile exists in the working directory
+// when --forbid-pwd-env is given or ENVDO_CI is set.
+func guardPwdEnv(e *env.Env, profile string) error {
+	if !forbidPwdEnv {
+		return nil
+	}
+	pwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	for _, f := range e.Files(profile) {
+		if filepath.Dir(f) == pwd {
+			return fmt.Errorf("unexpected %s in the working directory (forbidden by --forbid-pwd-env or ENVDO_CI)", f)
+		}
+	}
+	return nil
+}
+
+// recordAuditLog appends the invocation to the audit log
+// when --audit-log is given or ENVDO_AUDIT_LOG is set.
+func recordAuditLog(profile string, command []string, envs map[string]string) error {
+	if !auditLog {
+		return nil
+	}
+	e, err := newEnv()
+	if err != nil {
+		return err
+	}
+	entry := auditlog.NewEntry(profile, e.Files(profile), command, envs)
+	if err := auditlog.Append(auditlog.DefaultPath(), entry); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/k1LoW/envdo/env"
+	"github.com/spf13/cobra"
+)
+
+var profilesFormat string
+
+// profilesCmd represents the profiles command.
+var profilesCmd = &cobra.Command{
+	Use:   "profiles",
+	Short: "List available profiles",
+	Long: `List the profiles found in the current directory and $XDG_CONFIG_HOME/envdo,
+with the files backing each profile, the numbers of variables and the last-modified times.
+
+Examples:
+  envdo profiles
+  envdo profiles --format json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		e, err := newEnv()
+		if err != nil {
+			return err
+		}
+		profiles, err := e.Profiles()
+		if err != nil {
+			return err
+		}
+		switch profilesFormat {
+		case "table":
+			tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(tw, "PROFILE\tVARS\tFILE\tMODIFIED")
+			for _, p := range profiles {
+				for i, f := range p.Files {
+					name, vars := "", formatVars(f.Vars)
+					if i == 0 {
+						name = p.Name
+					}
+					_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", name, vars, f.Path, f.Modified.Local().Format(time.DateTime))
+				}
+			}
+			return tw.Flush()
+		case "json":
+			if profiles == nil {
+				profiles = []env.Profile{}
+			}
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(profiles)
+		default:
+			return fmt.Errorf("unsupported format: %s", profilesFormat)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(profilesCmd)
+	profilesCmd.Flags().StringVar(&profilesFormat, "format", "table", "output format (table, json)")
+}
+
+// formatVars formats the number of variables, or "?" if it is unknown.
+func formatVars(n *int) string {
+	if n == nil {
+		return "?"
+	}
+	return strconv.Itoa(*n)
+}
@@ -0,0 +1,45 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"github.com/k1LoW/envdo/env/provider"
+	"github.com/spf13/cobra"
+)
+
+// providersCmd represents the providers command.
+var providersCmd = &cobra.Command{
+	Use:   "providers",
+	Short: "Manage secret providers",
+	Long: `Manage the secret providers resolving references (op://, vault://, ...) in .env files.
+
+In addition to the built-in providers, executables named ` + provider.PluginPrefix + `<scheme> in $PATH
+are provider plugins resolving <scheme>:// references. For each reference, a plugin is run with
+{"version": 1, "ref": "<scheme>://..."} on stdin and writes {"value": "..."} to stdout,
+or {"error": "..."} with a non-zero exit code.
+
+providers of .envdo.yml enables only the listed providers, including plugins.`,
+}
+
+func init() {
+	rootCmd.AddCommand(providersCmd)
+}
@@ -0,0 +1,92 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"text/tabwriter"
+
+	"github.com/k1LoW/envdo/env/provider"
+	"github.com/spf13/cobra"
+)
+
+var providersFormat string
+
+// providerInfo is a secret provider listed by envdo providers list.
+type providerInfo struct {
+	Scheme  string `json:"scheme"`
+	Type    string `json:"type"`
+	Path    string `json:"path,omitempty"`
+	Enabled bool   `json:"enabled"`
+}
+
+// providersListCmd represents the providers list command.
+var providersListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the secret providers",
+	Long: `List the built-in secret providers and the provider plugins found in $PATH,
+and whether they are enabled by providers of .envdo.yml (all of them if not set).
+
+Examples:
+  envdo providers list
+  envdo providers list --format json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := loadConfig()
+		if err != nil {
+			return err
+		}
+		enabled := func(scheme string) bool {
+			return len(c.Providers) == 0 || slices.Contains(c.Providers, scheme)
+		}
+		var providers []providerInfo
+		for _, s := range provider.Schemes {
+			providers = append(providers, providerInfo{Scheme: s, Type: "builtin", Enabled: enabled(s)})
+		}
+		for _, p := range provider.FindPlugins(os.Getenv("PATH")) {
+			providers = append(providers, providerInfo{Scheme: p.Scheme(), Type: "plugin", Path: p.Path(), Enabled: enabled(p.Scheme())})
+		}
+		switch providersFormat {
+		case "table":
+			tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(tw, "SCHEME\tTYPE\tENABLED\tPATH")
+			for _, p := range providers {
+				_, _ = fmt.Fprintf(tw, "%s\t%s\t%t\t%s\n", p.Scheme, p.Type, p.Enabled, p.Path)
+			}
+			return tw.Flush()
+		case "json":
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(providers)
+		default:
+			return fmt.Errorf("unsupported format: %s", providersFormat)
+		}
+	},
+}
+
+func init() {
+	providersCmd.AddCommand(providersListCmd)
+	providersListCmd.Flags().StringVar(&providersFormat, "format", "table", "output format (table, json)")
+}
@@ -0,0 +1,61 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/k1LoW/envdo/platform"
+	"github.com/spf13/cobra"
+)
+
+var flyStage bool
+
+// pushFlyCmd represents the push fly command.
+var pushFlyCmd = &cobra.Command{
+	Use:   "fly",
+	Short: "Push environment variables to Fly.io app secrets",
+	Long: `Push the environment variables of a profile to Fly.io app secrets using flyctl.
+
+Examples:
+  envdo push fly --app my-app -p production
+  envdo push fly --app my-app -p production --stage`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		envs, _, err := loadEnvs(profile)
+		if err != nil {
+			return err
+		}
+		if err := platform.NewFly().SetSecrets(cmd.Context(), flyApp, envs, flyStage); err != nil {
+			return err
+		}
+		logger.Info(fmt.Sprintf("Pushed %d variables to Fly.io app %s", len(envs), flyApp))
+		return nil
+	},
+}
+
+func init() {
+	pushCmd.AddCommand(pushFlyCmd)
+	pushFlyCmd.Flags().StringVarP(&profile, "profile", "p", "", "profile name")
+	pushFlyCmd.Flags().StringVarP(&flyApp, "app", "a", "", "Fly.io app name")
+	pushFlyCmd.Flags().BoolVar(&flyStage, "stage", false, "set secrets without restarting machines")
+}
@@ -0,0 +1,80 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/k1LoW/envdo/crypt"
+	"github.com/k1LoW/envdo/env"
+	"github.com/spf13/cobra"
+)
+
+// receiveCmd represents the receive command.
+var receiveCmd = &cobra.Command{
+	Use:   "receive [FILE]",
+	Short: "Decrypt shared variables and import them into a profile",
+	Long: `Decrypt variables shared by 'envdo share' and import them into a profile.
+
+The encrypted text is read from FILE or stdin and decrypted with the age identity
+in $AGE_KEY_FILE or $XDG_CONFIG_HOME/envdo/age/keys.txt.
+
+Examples:
+  envdo receive staging.age.txt -p staging
+  pbpaste | envdo receive`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var (
+			ciphertext []byte
+			err        error
+		)
+		if len(args) > 0 && args[0] != "-" {
+			ciphertext, err = os.ReadFile(args[0])
+		} else {
+			ciphertext, err = io.ReadAll(os.Stdin)
+		}
+		if err != nil {
+			return err
+		}
+		ids, err := crypt.LoadIdentities(envdoConfigDir())
+		if err != nil {
+			return err
+		}
+		plaintext, err := crypt.Decrypt(bytes.TrimSpace(ciphertext), ids)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt: %w", err)
+		}
+		envs, err := env.Parse(bytes.NewReader(plaintext))
+		if err != nil {
+			return err
+		}
+		return writeProfile(profile, envs)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(receiveCmd)
+	receiveCmd.Flags().StringVarP(&profile, "profile", "p", "", "profile name to import into")
+}
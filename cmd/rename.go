@@ -0,0 +1,58 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/k1LoW/envdo/env"
+	"github.com/spf13/cobra"
+)
+
+// renameCmd represents the rename command.
+var renameCmd = &cobra.Command{
+	Use:   "rename OLD NEW",
+	Short: "Rename a key in a .env file of a profile",
+	Long: `Rename a key in the .env file of a profile (chosen like envdo set),
+keeping its values, comments and the order of the lines.
+
+Examples:
+  envdo rename DB_URL DATABASE_URL
+  envdo rename DB_URL DATABASE_URL -p staging`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := editTarget()
+		if err != nil {
+			return err
+		}
+		if err := env.RenameKey(path, args[0], args[1]); err != nil {
+			return err
+		}
+		logger.Info(fmt.Sprintf("Renamed %s to %s in %s", args[0], args[1], path))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(renameCmd)
+	addEditFlags(renameCmd)
+}
@@ -25,14 +25,30 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
+	"github.com/k1LoW/envdo/config"
 	"github.com/k1LoW/envdo/env"
+	"github.com/k1LoW/envdo/output"
+	"github.com/k1LoW/envdo/schema"
 	"github.com/k1LoW/envdo/version"
-	"github.com/k1LoW/exec"
 	"github.com/spf13/cobra"
 )
 
-var profile string
+var (
+	profile     string
+	format      string
+	name        string
+	prefix      string
+	stripPrefix string
+	rename      map[string]string
+	keyCase     string
+	argv0       string
+	shell       string
+	chdir       string
+	configDir   string
+)
 
 // rootCmd represents the base command when called without any subcommands.
 var rootCmd = &cobra.Command{
@@ -46,57 +62,209 @@ Current directory values take priority over config directory values.
 Examples:
   envdo -- echo $MY_VAR
   envdo --profile production -- node app.js
-  envdo -p dev -- npm start`,
+  envdo -p dev -- npm start
+  envdo --watch -- go run ./cmd/server
+  envdo -p ci --timeout 5m -- ./integration-test.sh
+  envdo -C services/api -p dev -- go run .
+  envdo -p dev --prefix VITE_ -- npm run dev
+  generate-secrets | envdo --env-file - -- ./run.sh
+  envdo -p production --format k8s-configmap --name app-config
+  envdo -p ci --github-env`,
+	Args:         cobra.ArbitraryArgs,
 	SilenceUsage: true,
 	Version:      version.Version,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := applyFlagEnvs(cmd); err != nil {
+			return err
+		}
+		setupLogger()
+		if configDir != "" {
+			abs, err := filepath.Abs(configDir)
+			if err != nil {
+				return err
+			}
+			configDir = abs
+		}
+		// Search DIR for .env files and run the command in DIR, like git -C
+		if chdir != "" {
+			if err := os.Chdir(chdir); err != nil {
+				return fmt.Errorf("failed to change directory: %w", err)
+			}
+		}
+		// Use profile of .envdo.yml unless a profile is given
+		if f := cmd.Flags().Lookup("profile"); f != nil && !f.Changed {
+			c, err := loadConfig()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+			profile = c.Profile
+		}
+		return nil
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// Load environment variables
-		envs, err := env.LoadEnvFiles(profile)
+		var (
+			envs  map[string]string
+			order []string
+			err   error
+		)
+		if files := explicitEnvFiles(); len(files) > 0 {
+			if profile != "" {
+				return errors.New("--env-file and --profile cannot be used together")
+			}
+			if watch || explain || dryRun {
+				return errors.New("--watch, --explain and --dry-run cannot be used with --env-file except -")
+			}
+			envs, order, err = loadEnvFileArgs(files)
+		} else {
+			if len(args) > 0 {
+				if err := checkAllowedCommand(profile, args); err != nil {
+					return err
+				}
+			}
+			envs, order, err = loadEnvs(profile)
+		}
 		if err != nil {
-			return fmt.Errorf("failed to load environment variables: %w", err)
+			return err
+		}
+		envs, order, err = transformKeys(envs, order)
+		if err != nil {
+			return err
+		}
+		if explain || dryRun {
+			if err := writeExplain(os.Stderr, profile, envs, order); err != nil {
+				return err
+			}
+			if dryRun {
+				return nil
+			}
+		}
+		if validate {
+			if err := validateValues(os.Stderr, envs); err != nil {
+				return err
+			}
+		}
+		if err := recordAuditLog(profile, args, envs); err != nil {
+			return err
 		}
 
-		// If no arguments, print the loaded environment variables
-		if len(args) == 0 {
-			for key, value := range envs {
-				fmt.Printf("export %s=%s\n", key, value)
+		if exportFile != "" || githubEnv {
+			if len(args) > 0 {
+				return errors.New("a command cannot be given with --export-file or --github-env")
 			}
-			return nil
+			return writeExportFile(envs, order)
 		}
 
-		// Prepare environment for command execution
-		cmdEnvs := os.Environ()
-		for key, value := range envs {
-			cmdEnvs = append(cmdEnvs, fmt.Sprintf("%s=%s", key, value))
+		// If no arguments, print the loaded environment variables
+		if len(args) == 0 {
+			s, err := loadSchema()
+			if err != nil {
+				return fmt.Errorf("failed to load schema: %w", err)
+			}
+			m, err := newMasker()
+			if err != nil {
+				return err
+			}
+			if m != nil {
+				envs = m.Mask(envs)
+			}
+			if omitted := output.OmittedKeys(format, envs, s); len(omitted) > 0 {
+				other := output.FormatK8sSecret
+				if format == output.FormatK8sSecret {
+					other = output.FormatK8sConfigMap
+				}
+				logger.Warn(fmt.Sprintf("keys not written in the %s format: %s (write them with --format %s)", format, strings.Join(omitted, ", "), other))
+			}
+			return output.Write(os.Stdout, format, envs, output.Options{
+				Name:   name,
+				Schema: s,
+				Shell:  shell,
+				Order:  order,
+			})
 		}
 
-		// Execute the command
-		command := args[0]
-		c := exec.Command(command, args[1:]...)
-		c.Stdin = os.Stdin
-		c.Stdout = os.Stdout
-		c.Stderr = os.Stderr
-		c.Env = cmdEnvs
-		cmd.SilenceErrors = true
-		if err := c.Run(); err != nil {
-			var exitError *exec.ExitError
-			if errors.As(err, &exitError) {
-				os.Exit(exitError.ExitCode())
+		if watch {
+			if timeout > 0 || retries > 0 || redact {
+				return errors.New("--timeout, --retries and --redact cannot be used with --watch")
 			}
-			return err
+			e, err := newEnv()
+			if err != nil {
+				return err
+			}
+			return runWatch(cmd, args, envs, order, e.Files(profile), func() (map[string]string, []string, error) {
+				envs, order, err := loadEnvs(profile)
+				if err != nil {
+					return nil, nil, err
+				}
+				return transformKeys(envs, order)
+			})
 		}
-		return nil
+		return runCommand(cmd, args, envs, order)
 	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
+	registerCompletions(rootCmd)
 	if err := rootCmd.Execute(); err != nil {
+		var exitErr *exitCodeError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.code)
+		}
 		os.Exit(1)
 	}
 }
 
+// exitCodeError is returned by commands to make envdo exit with the code, without printing an error.
+type exitCodeError struct {
+	code int
+}
+
+func (e *exitCodeError) Error() string {
+	return fmt.Sprintf("exit status %d", e.code)
+}
+
+// exitWithCode returns the error making envdo exit with code, silencing the error of cmd.
+func exitWithCode(cmd *cobra.Command, code int) error {
+	cmd.SilenceErrors = true
+	return &exitCodeError{code: code}
+}
+
 func init() {
 	rootCmd.Flags().StringVarP(&profile, "profile", "p", "", "profile name")
+	addRunFlags(rootCmd)
+	rootCmd.Flags().StringVar(&format, "format", output.FormatExport, "output format when no command is given ("+strings.Join(output.Formats, ", ")+")")
+	rootCmd.Flags().StringArrayVar(&envFiles, "env-file", nil, "env file to load instead of the .env files of the profile, the URL of a remote env file (https://, s3:// or gs://) or - to read stdin at the highest priority (can be repeated, later files override earlier ones)")
+	rootCmd.Flags().StringVar(&shell, "shell", output.DefaultShell, "shell of the export format ("+strings.Join(output.Shells, ", ")+")")
+	rootCmd.Flags().StringArrayVar(&envURLs, "env-url", nil, "load the remote env file at the URL (https://, s3:// or gs://) with lower priority than .env files, pinned by #sha256=<hex> if given (can be repeated; $"+env.URLTokenEnv+" is sent as a bearer token to the hosts in $"+env.URLTokenHostsEnv+")")
+	rootCmd.Flags().StringArrayVar(&fromK8s, "from-k8s", nil, "load the data of the Kubernetes Secret or ConfigMap (secret/NAME or configmap/NAME) with kubectl with lower priority than .env files (can be repeated)")
+	rootCmd.Flags().StringVar(&k8sNamespace, "k8s-namespace", "", "namespace of --from-k8s (default the current namespace of the kubeconfig)")
+	rootCmd.Flags().StringVar(&exportFile, "export-file", "", "append the variables in KEY=value form to the file instead of executing a command")
+	rootCmd.Flags().BoolVar(&githubEnv, "github-env", false, "append the variables to $GITHUB_ENV in GitHub Actions, masking secret values in logs")
+	rootCmd.Flags().StringVar(&name, "name", "", "resource name for manifest formats (service name for the compose format)")
+	rootCmd.Flags().StringVar(&schemaPath, "schema", "", "schema file path (default "+schema.Filename+" in the current directory)")
+	rootCmd.Flags().StringVar(&keyCase, "key-case", "", "transform keys to the case ("+strings.Join(env.KeyCases, ", ")+")")
+	rootCmd.Flags().StringToStringVar(&rename, "rename", nil, "rename keys (e.g. TOKEN=GITHUB_TOKEN), in addition to the rename mapping of "+config.Filename)
+	rootCmd.Flags().BoolVar(&mask, "mask", false, "mask values of secret keys (TOKEN, SECRET, PASSWORD, KEY, ...) with *** when printing")
+	rootCmd.Flags().StringArrayVar(&maskPatterns, "mask-pattern", nil, "mask values of keys matching the regular expression when printing (implies --mask)")
+	rootCmd.Flags().StringVar(&diagnosticsFormat, "diagnostics", "", "write all problems of the .env files to stderr in the format (json)")
+	rootCmd.Flags().BoolVar(&explain, "explain", false, "write each variable with the file and line it came from and the definitions it overrides to stderr before executing")
+	rootCmd.Flags().BoolVar(&dryRun, "dry-run", false, "write the variables like --explain and exit without executing the command")
+	rootCmd.Flags().BoolVar(&explainShowValues, "show-values", false, "show the values of secret keys written by --explain and --dry-run instead of ***")
+	rootCmd.Flags().BoolVar(&watch, "watch", false, "restart the command when the loaded .env files change")
+	rootCmd.Flags().BoolVar(&validate, "validate", false, "validate the variables against the schema file before executing the command")
+	rootCmd.PersistentFlags().BoolVar(&auditLog, "audit-log", false, "record the invocation to the audit log (also enabled by ENVDO_AUDIT_LOG=1)")
+	rootCmd.PersistentFlags().StringVarP(&chdir, "chdir", "C", "", "run as if envdo was started in the directory (searching it for .env files and running the command in it)")
+	rootCmd.PersistentFlags().StringVar(&configDir, "config-dir", "", "configuration directory of envdo with the profile files, config.yml and age identities (default $XDG_CONFIG_HOME/envdo)")
+	rootCmd.PersistentFlags().BoolVar(&walkUp, "up", false, "search parent directories up to the git repository root for .env files too (also enabled by walk_up of "+config.Filename+")")
+	rootCmd.PersistentFlags().StringArrayVar(&envDirs, "env-dir", nil, "additional directory of .env files searched after the current directory and before $XDG_CONFIG_HOME/envdo (repeatable)")
+	rootCmd.PersistentFlags().BoolVar(&verbose, "verbose", false, "write debug messages such as the .env files found, skipped and merged to stderr")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "write no messages of envdo except errors, so only the output of the command appears")
+	rootCmd.MarkFlagsMutuallyExclusive("verbose", "quiet")
+	rootCmd.PersistentFlags().BoolVar(&killChildren, "kill-children", true, "forward signals terminating envdo to the whole process group (process tree on Windows) of the command, so that no grandchildren are left behind")
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "resolve secret references without the cache of resolved values")
+	rootCmd.PersistentFlags().BoolVar(&noExpand, "no-expand", false, "keep $VAR and ${VAR} in the values of the .env files as they are instead of expanding them")
+	rootCmd.PersistentFlags().BoolVar(&allowExec, "allow-exec", false, "run the command substitutions ($(command) values) of the .env files even if they are not allowed with envdo allow")
+	rootCmd.PersistentFlags().BoolVar(&forbidPwdEnv, "forbid-pwd-env", false, "fail if a .env file exists in the working directory (also enabled by ENVDO_CI=1)")
 }
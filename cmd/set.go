@@ -0,0 +1,110 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/k1LoW/envdo/env"
+	"github.com/spf13/cobra"
+)
+
+// editFile is the .env file edited by set, get, rename and unset, given by --file.
+var editFile string
+
+// setCmd represents the set command.
+var setCmd = &cobra.Command{
+	Use:   "set KEY=VALUE [KEY=VALUE...]",
+	Short: "Set keys in a .env file of a profile",
+	Long: `Set keys in the .env file of a profile, keeping comments and the order of the other lines.
+Existing definitions are replaced in place and new keys are appended.
+
+The file is the one given by --file, or the dotenv file of the profile with the highest priority
+(e.g. .env.<profile> in the current directory), falling back to the profile file in $XDG_CONFIG_HOME/envdo.
+
+Examples:
+  envdo set PORT=8080
+  envdo set API_URL=https://staging.example.com -p staging
+  envdo set DEBUG=true --file .env.local`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		envs := make(map[string]string)
+		keys := make([]string, 0, len(args))
+		for _, a := range args {
+			k, v, ok := strings.Cut(a, "=")
+			if !ok || k == "" {
+				return fmt.Errorf("invalid argument %q: must be KEY=VALUE", a)
+			}
+			if _, ok := envs[k]; !ok {
+				keys = append(keys, k)
+			}
+			envs[k] = v
+		}
+		path, err := editTarget()
+		if err != nil {
+			return err
+		}
+		if err := env.UpdateFile(path, envs); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		logger.Info(fmt.Sprintf("Set %s in %s", strings.Join(keys, ", "), path))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(setCmd)
+	addEditFlags(setCmd)
+}
+
+// addEditFlags adds the flags choosing the .env file edited by cmd.
+func addEditFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&profile, "profile", "p", "", "profile name")
+	cmd.Flags().StringVar(&editFile, "file", "", "path of the .env file to edit (default the dotenv file of the profile)")
+}
+
+// editTarget returns the .env file edited by set, get, rename and unset: the file given by --file,
+// or the dotenv file of the profile with the highest priority, falling back to the profile file in $XDG_CONFIG_HOME/envdo.
+// Structured and encrypted files cannot be edited.
+func editTarget() (string, error) {
+	path := editFile
+	if path == "" {
+		e, err := newEnv()
+		if err != nil {
+			return "", err
+		}
+		path = e.ProfilePath(profile)
+		for _, f := range e.Files(profile) {
+			if filepath.Base(f) == env.Filename(profile) {
+				path = f
+				break
+			}
+		}
+	}
+	if env.IsStructured(path) || env.IsEncrypted(path) || env.IsRemote(path) {
+		return "", errors.New("only dotenv files can be edited: " + path)
+	}
+	return path, nil
+}
@@ -0,0 +1,84 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/k1LoW/envdo/crypt"
+	"github.com/k1LoW/envdo/env"
+	"github.com/spf13/cobra"
+)
+
+var shareRecipients []string
+
+// shareCmd represents the share command.
+var shareCmd = &cobra.Command{
+	Use:   "share [KEY...]",
+	Short: "Encrypt variables for sharing with another user",
+	Long: `Encrypt variables of a profile to age public keys and print them as ASCII-armored text.
+
+Only the recipients can decrypt it with 'envdo receive'.
+If no keys are given, the whole profile is shared.
+
+Examples:
+  envdo share API_KEY --to age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p
+  envdo share -p staging --to age1... > staging.age.txt`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		recipients, err := crypt.ParseRecipients(shareRecipients)
+		if err != nil {
+			return err
+		}
+		envs, _, err := loadEnvs(profile)
+		if err != nil {
+			return err
+		}
+		if len(args) > 0 {
+			selected := make(map[string]string, len(args))
+			for _, key := range args {
+				v, ok := envs[key]
+				if !ok {
+					return fmt.Errorf("key %s is not defined", key)
+				}
+				selected[key] = v
+			}
+			envs = selected
+		}
+		plaintext, err := env.Marshal(envs)
+		if err != nil {
+			return err
+		}
+		ciphertext, err := crypt.Encrypt(plaintext, recipients)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt: %w", err)
+		}
+		_, err = os.Stdout.Write(ciphertext)
+		return err
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(shareCmd)
+	shareCmd.Flags().StringVarP(&profile, "profile", "p", "", "profile name")
+	shareCmd.Flags().StringSliceVar(&shareRecipients, "to", nil, "age public key of the recipient (repeatable)")
+}
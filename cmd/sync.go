@@ -0,0 +1,67 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"errors"
+	"os"
+
+	"github.com/k1LoW/envdo/gitsync"
+	"github.com/spf13/cobra"
+)
+
+var syncRepoURL string
+
+// syncCmd represents the sync command.
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Share encrypted profiles with a team through a git repository",
+	Long: `Share profiles with a team through a git repository.
+
+Profiles are encrypted with age to the public keys in recipients.txt of the repository
+(or profiles/<name>.recipients for a profile) and stored as profiles/<name>.env.age,
+so the history of the shared environments is versioned and auditable with git.
+
+The repository is given by --repo or $ENVDO_SYNC_REPO and cloned into the user cache directory.`,
+}
+
+func init() {
+	rootCmd.AddCommand(syncCmd)
+	syncCmd.PersistentFlags().StringVarP(&profile, "profile", "p", "", "profile name")
+	syncCmd.PersistentFlags().StringVar(&syncRepoURL, "repo", "", "git repository URL to sync with")
+}
+
+// openSyncRepo clones or updates the sync repository.
+func openSyncRepo(cmd *cobra.Command) (*gitsync.Repo, error) {
+	if syncRepoURL == "" {
+		return nil, errors.New("--repo or $ENVDO_SYNC_REPO is required")
+	}
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	r := gitsync.New(syncRepoURL, cacheDir)
+	if err := r.Pull(cmd.Context()); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
@@ -0,0 +1,68 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/k1LoW/envdo/crypt"
+	"github.com/k1LoW/envdo/env"
+	"github.com/spf13/cobra"
+)
+
+// syncPullCmd represents the sync pull command.
+var syncPullCmd = &cobra.Command{
+	Use:   "pull",
+	Short: "Pull a profile from the sync repository and decrypt it",
+	Long: `Pull a profile from the sync repository and decrypt it into the profile file
+in $XDG_CONFIG_HOME/envdo. The profile file is replaced with the content of the repository.
+
+The profile is decrypted with the age identity in $AGE_KEY_FILE or $XDG_CONFIG_HOME/envdo/age/keys.txt.
+
+Examples:
+  envdo sync pull -p staging --repo git@github.com:example/envs.git`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ids, err := crypt.LoadIdentities(envdoConfigDir())
+		if err != nil {
+			return err
+		}
+		r, err := openSyncRepo(cmd)
+		if err != nil {
+			return err
+		}
+		content, err := r.ReadProfile(profile, ids)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt profile: %w", err)
+		}
+		path := defaultEnv().ProfilePath(profile)
+		if err := env.WriteFile(path, content); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		logger.Info("Pulled " + path)
+		return nil
+	},
+}
+
+func init() {
+	syncCmd.AddCommand(syncPullCmd)
+}
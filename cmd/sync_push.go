@@ -0,0 +1,60 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/k1LoW/envdo/env"
+	"github.com/spf13/cobra"
+)
+
+// syncPushCmd represents the sync push command.
+var syncPushCmd = &cobra.Command{
+	Use:   "push",
+	Short: "Encrypt a profile and push it to the sync repository",
+	Long: `Encrypt the profile file in $XDG_CONFIG_HOME/envdo and push it to the sync repository.
+
+Examples:
+  envdo sync push -p staging --repo git@github.com:example/envs.git`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := defaultEnv().ProfilePath(profile)
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		r, err := openSyncRepo(cmd)
+		if err != nil {
+			return err
+		}
+		if err := r.WriteProfile(profile, content); err != nil {
+			return err
+		}
+		return r.Push(cmd.Context(), fmt.Sprintf("Update %s", env.Filename(profile)))
+	},
+}
+
+func init() {
+	syncCmd.AddCommand(syncPushCmd)
+}
@@ -0,0 +1,92 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/k1LoW/envdo/crypt"
+	"github.com/spf13/cobra"
+)
+
+var (
+	syncRecipientsAdd    []string
+	syncRecipientsRemove []string
+)
+
+// syncRecipientsCmd represents the sync recipients command.
+var syncRecipientsCmd = &cobra.Command{
+	Use:   "recipients",
+	Short: "List or change the recipients of the sync repository",
+	Long: `List the age public keys of the team, or add and remove them.
+
+When recipients are changed, all profiles in the repository are re-encrypted
+with your identity and pushed, so the removed recipients cannot decrypt future changes.
+
+Examples:
+  envdo sync recipients
+  envdo sync recipients --add age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p
+  envdo sync recipients --remove age1...`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		r, err := openSyncRepo(cmd)
+		if err != nil {
+			return err
+		}
+		keys, err := r.Recipients("")
+		if err != nil {
+			return err
+		}
+		if len(syncRecipientsAdd) == 0 && len(syncRecipientsRemove) == 0 {
+			for _, k := range keys {
+				fmt.Println(k)
+			}
+			return nil
+		}
+		for _, k := range syncRecipientsAdd {
+			if !slices.Contains(keys, k) {
+				keys = append(keys, k)
+			}
+		}
+		keys = slices.DeleteFunc(keys, func(k string) bool {
+			return slices.Contains(syncRecipientsRemove, k)
+		})
+		ids, err := crypt.LoadIdentities(envdoConfigDir())
+		if err != nil {
+			return err
+		}
+		if err := r.SetRecipients(keys); err != nil {
+			return err
+		}
+		if err := r.Rekey(ids); err != nil {
+			return err
+		}
+		return r.Push(cmd.Context(), "Update recipients")
+	},
+}
+
+func init() {
+	syncCmd.AddCommand(syncRecipientsCmd)
+	syncRecipientsCmd.Flags().StringSliceVar(&syncRecipientsAdd, "add", nil, "age public key to add")
+	syncRecipientsCmd.Flags().StringSliceVar(&syncRecipientsRemove, "remove", nil, "age public key to remove")
+}
@@ -0,0 +1,81 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/k1LoW/envdo/output"
+	"github.com/spf13/cobra"
+)
+
+var templateEnvsubst bool
+
+// templateCmd represents the template command.
+var templateCmd = &cobra.Command{
+	Use:   "template FILE",
+	Short: "Render a template with environment variables",
+	Long: `Render a Go text/template file with the resolved environment variables of a profile
+and write it to stdout or the file specified by --output.
+
+Values are referred by {{ .KEY }}, which fails if the key is not defined,
+or by {{ env "KEY" }}, which is empty if the key is not defined.
+{{ env "KEY" | default "value" }} falls back to the value.
+{{ range keys }} iterates the keys in the order of their definitions.
+
+With --envsubst, $VAR and ${VAR} in the file are replaced like envsubst instead.
+
+Examples:
+  envdo template nginx.conf.tmpl -p production -o nginx.conf
+  envdo template config.yml.in --envsubst`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		b, err := os.ReadFile(args[0])
+		if err != nil {
+			return err
+		}
+		envs, order, err := loadEnvs(profile)
+		if err != nil {
+			return err
+		}
+		envs, order, err = transformKeys(envs, order)
+		if err != nil {
+			return err
+		}
+		return writeGenOutput(func(w io.Writer) error {
+			if templateEnvsubst {
+				return output.Envsubst(w, string(b), envs)
+			}
+			return output.Template(w, filepath.Base(args[0]), string(b), envs, order)
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(templateCmd)
+	templateCmd.Flags().StringVarP(&profile, "profile", "p", "", "profile name")
+	templateCmd.Flags().StringVar(&baseProfile, "base", "", "base profile to layer the profile on (.env for the default .env file)")
+	templateCmd.Flags().StringVarP(&genOutput, "output", "o", "", "output file path (default stdout)")
+	templateCmd.Flags().BoolVar(&templateEnvsubst, "envsubst", false, "replace $VAR and ${VAR} like envsubst instead of rendering a Go template")
+}
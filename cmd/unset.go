@@ -0,0 +1,66 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/k1LoW/envdo/env"
+	"github.com/spf13/cobra"
+)
+
+// unsetCmd represents the unset command.
+var unsetCmd = &cobra.Command{
+	Use:   "unset KEY [KEY...]",
+	Short: "Delete keys from a .env file of a profile",
+	Long: `Delete the definitions of keys from the .env file of a profile (chosen like envdo set),
+together with their directive comments such as # envdo:expires. Other lines are kept.
+
+To remove a variable inherited from lower-priority files or the parent shell instead,
+write a !KEY line or give --unset KEY.
+
+Examples:
+  envdo unset OLD_TOKEN
+  envdo unset OLD_TOKEN LEGACY_URL -p staging`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := editTarget()
+		if err != nil {
+			return err
+		}
+		deleted, err := env.DeleteKeys(path, args)
+		if err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		if len(deleted) == 0 {
+			return fmt.Errorf("no definitions of %s in %s", strings.Join(args, ", "), path)
+		}
+		logger.Info(fmt.Sprintf("Deleted %s from %s", strings.Join(deleted, ", "), path))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(unsetCmd)
+	addEditFlags(unsetCmd)
+}
@@ -0,0 +1,84 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/k1LoW/envdo/check"
+	"github.com/k1LoW/envdo/schema"
+	"github.com/spf13/cobra"
+)
+
+var validate bool
+
+// validateCmd represents the validate command.
+var validateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Validate environment variables against the schema file",
+	Long: `Validate the resolved environment variables of a profile against the types,
+enums and patterns defined in the schema file (.envdo.schema.yml).
+
+It exits with 1 if any value is invalid.
+
+Examples:
+  envdo validate -p production
+  envdo validate --schema config/env.schema.yml`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		envs, order, err := loadEnvs(profile)
+		if err != nil {
+			return err
+		}
+		envs, _, err = transformKeys(envs, order)
+		if err != nil {
+			return err
+		}
+		return validateValues(os.Stdout, envs)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+	validateCmd.Flags().StringVarP(&profile, "profile", "p", "", "profile name")
+	validateCmd.Flags().StringVar(&baseProfile, "base", "", "base profile to layer the profile on (.env for the default .env file)")
+	validateCmd.Flags().StringVar(&schemaPath, "schema", "", "schema file path (default "+schema.Filename+" in the current directory)")
+}
+
+// validateValues validates envs against the schema file and writes invalid values to w.
+// It returns an error if any value is invalid.
+func validateValues(w io.Writer, envs map[string]string) error {
+	s, err := loadSchema()
+	if err != nil {
+		return fmt.Errorf("failed to load schema: %w", err)
+	}
+	ds := check.ValidateValues(envs, s)
+	if len(ds) == 0 {
+		return nil
+	}
+	if err := check.WriteText(w, ds); err != nil {
+		return err
+	}
+	return fmt.Errorf("found %d invalid values", len(ds))
+}
@@ -0,0 +1,175 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	osexec "os/exec"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/k1LoW/exec"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// watchDebounce is the delay to wait for successive changes of .env files before restarting.
+	watchDebounce = 200 * time.Millisecond
+	// stopTimeout is the time to wait for the command to exit after SIGTERM before killing it.
+	stopTimeout = 5 * time.Second
+)
+
+var watch bool
+
+// child is a running command.
+type child struct {
+	cmd  *osexec.Cmd
+	done chan error
+}
+
+// runWatch executes args with envs added in order and restarts it with envs reloaded by reload
+// whenever one of files changes. It returns when envdo receives SIGINT or SIGTERM.
+func runWatch(cmd *cobra.Command, args []string, envs map[string]string, order []string, files []string, reload func() (map[string]string, []string, error)) error {
+	if len(files) == 0 {
+		return errors.New("no .env files to watch")
+	}
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+	// Watch the directories instead of the files to follow editors replacing files on save
+	watched := make(map[string]bool)
+	for _, f := range files {
+		abs, err := filepath.Abs(f)
+		if err != nil {
+			return err
+		}
+		watched[abs] = true
+	}
+	dirs := make(map[string]bool)
+	for f := range watched {
+		dir := filepath.Dir(f)
+		if dirs[dir] {
+			continue
+		}
+		if err := w.Add(dir); err != nil {
+			return fmt.Errorf("failed to watch %s: %w", dir, err)
+		}
+		dirs[dir] = true
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+
+	c, err := startChild(args, envs, order)
+	if err != nil {
+		if exitErr := startError(cmd, args[0], err); exitErr != nil {
+			return exitErr
+		}
+		return err
+	}
+	var debounce <-chan time.Time
+	for {
+		select {
+		case ev, ok := <-w.Events:
+			if !ok {
+				return nil
+			}
+			if ev.Has(fsnotify.Chmod) || !watched[filepath.Clean(ev.Name)] {
+				continue
+			}
+			debounce = time.After(watchDebounce)
+		case err, ok := <-w.Errors:
+			if !ok {
+				return nil
+			}
+			logger.Warn(fmt.Sprintf("failed to watch .env files: %v", err))
+		case <-debounce:
+			debounce = nil
+			reloaded, order, err := reload()
+			if err != nil {
+				logger.Info(fmt.Sprintf("Failed to reload environment variables, keeping the running command: %v", err))
+				continue
+			}
+			logger.Info("The .env files changed, restarting the command")
+			c.stop()
+			if c, err = startChild(args, reloaded, order); err != nil {
+				if exitErr := startError(cmd, args[0], err); exitErr != nil {
+					return exitErr
+				}
+				return err
+			}
+		case err := <-c.done:
+			c.done = nil
+			var exitError *exec.ExitError
+			switch {
+			case errors.As(err, &exitError):
+				logger.Info(fmt.Sprintf("The command exited with code %d, waiting for changes of the .env files", exitCode(args[0], exitError)))
+			case err != nil:
+				logger.Info(fmt.Sprintf("The command failed: %v, waiting for changes of the .env files", err))
+			default:
+				logger.Info("The command exited, waiting for changes of the .env files")
+			}
+		case <-sigCh:
+			c.stop()
+			return nil
+		}
+	}
+}
+
+// startChild starts args with envs added in order.
+func startChild(args []string, envs map[string]string, order []string) (*child, error) {
+	c := commandWithEnvs(args, envs, order)
+	if err := checkEnviron(c); err != nil {
+		return nil, err
+	}
+	if err := c.Start(); err != nil {
+		return nil, err
+	}
+	ch := &child{cmd: c, done: make(chan error, 1)}
+	go func() {
+		ch.done <- c.Wait()
+	}()
+	return ch, nil
+}
+
+// stop terminates the command if it is running, and kills it if it does not exit within stopTimeout.
+func (c *child) stop() {
+	if c.done == nil {
+		return
+	}
+	_ = exec.TerminateCommand(c.cmd, syscall.SIGTERM)
+	select {
+	case <-c.done:
+	case <-time.After(stopTimeout):
+		_ = exec.KillCommand(c.cmd)
+		<-c.done
+	}
+	c.done = nil
+}
@@ -0,0 +1,97 @@
+/*
+Copyright © 2025 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/k1LoW/envdo/env"
+	"github.com/k1LoW/envdo/output"
+	"github.com/spf13/cobra"
+)
+
+var whichShow bool
+
+// whichCmd represents the which command.
+var whichCmd = &cobra.Command{
+	Use:   "which KEY",
+	Short: "Show the effective value and origin of a key",
+	Long: `Show the effective value of a key in a profile, the file and line that defined it,
+and the lower-priority definitions it shadows.
+
+The value is resolved like when running a command (generated values, command substitutions
+and secret references), and masked unless --show is given.
+
+Examples:
+  envdo which DATABASE_URL
+  envdo which DATABASE_URL -p staging --show`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key := args[0]
+		e, err := newEnv()
+		if err != nil {
+			return err
+		}
+		entries, err := e.Entries(profile)
+		if err != nil {
+			return err
+		}
+		envs, _, err := loadEnvs(profile)
+		if err != nil {
+			return err
+		}
+		if i := slices.IndexFunc(entries, func(en env.Entry) bool { return en.Key == key }); i >= 0 && entries[i].Unset {
+			fmt.Printf("unset %s (%s:%d)\n", key, entries[i].File, entries[i].Line)
+			return nil
+		}
+		value, ok := envs[key]
+		if !ok {
+			return fmt.Errorf("%s is not defined in the profile", key)
+		}
+		if !whichShow {
+			value = output.MaskedValue
+		}
+		fmt.Printf("%s=%s\n", key, strings.ReplaceAll(value, "\n", `\n`))
+		vars := env.ResolveVars(entries)
+		i := slices.IndexFunc(vars, func(v env.Var) bool { return v.Key == key })
+		if i < 0 {
+			// Added by --env-url or --from-k8s
+			fmt.Println("  defined outside the .env files")
+			return nil
+		}
+		v := vars[i]
+		fmt.Printf("  defined at %s:%d\n", v.File, v.Line)
+		for _, o := range v.Overrides {
+			fmt.Printf("  shadows %s:%d\n", o.File, o.Line)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(whichCmd)
+	whichCmd.Flags().StringVarP(&profile, "profile", "p", "", "profile name")
+	whichCmd.Flags().StringVar(&baseProfile, "base", "", "base profile to layer the profile on (.env for the default .env file)")
+	whichCmd.Flags().BoolVar(&whichShow, "show", false, "show the value instead of ***")
+}
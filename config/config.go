@@ -0,0 +1,272 @@
+// Package config provides the project configuration of envdo defined in .envdo.yml.
+package config
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-yaml"
+	"github.com/k1LoW/envdo/env"
+	"github.com/k1LoW/envdo/env/provider"
+)
+
+// Filename is the project configuration filename.
+const Filename = ".envdo.yml"
+
+// UserFilename is the user configuration filename in $XDG_CONFIG_HOME/envdo.
+const UserFilename = "config.yml"
+
+// Config represents the project configuration.
+type Config struct {
+	// KeyCase transforms keys in .env files to the case (upper, lower or constant).
+	KeyCase string `yaml:"key_case,omitempty"`
+	// Rename maps keys in .env files to keys at runtime.
+	Rename map[string]string `yaml:"rename,omitempty"`
+	// MaskPatterns are regular expressions of keys whose values are masked by --mask,
+	// in addition to keys commonly holding secrets.
+	MaskPatterns []string `yaml:"mask_patterns,omitempty"`
+	// WalkUp makes the parent directories up to the git repository root searched for .env files.
+	WalkUp bool `yaml:"walk_up,omitempty"`
+	// Commands are named command presets run by envdo run.
+	Commands map[string][]string `yaml:"commands,omitempty"`
+	// Profile is the profile used when no profile is given.
+	Profile string `yaml:"profile,omitempty"`
+	// EnvFiles are paths of additional .env files, relative to the directory of the configuration file.
+	// They are loaded with the lowest priority.
+	EnvFiles []string `yaml:"env_files,omitempty"`
+	// Providers are the URI schemes of secret providers enabled to resolve references (e.g. op, vault),
+	// including provider plugins (envdo-provider-<scheme> executables in $PATH).
+	// All built-in providers and plugins are enabled if empty.
+	Providers []string `yaml:"providers,omitempty"`
+	// Required are keys that must be set to non-empty values.
+	Required []string `yaml:"required,omitempty"`
+	// Unset are keys removed from the loaded environment variables and the environment of commands.
+	Unset []string `yaml:"unset,omitempty"`
+	// AllowedCommands maps profiles ("default" for the default .env files) to the commands they may be used with.
+	// Commands are names of executables or glob patterns of them. Profiles not listed may be used with any command.
+	AllowedCommands map[string][]string `yaml:"allowed_commands,omitempty"`
+	// CacheTTL is the time to live of cached values of secret references (e.g. 30m). "0" disables the cache.
+	CacheTTL string `yaml:"cache_ttl,omitempty"`
+	// MaxValueSize is the size of values in bytes over which warnings are reported (32768 by default).
+	// A negative value disables the check.
+	MaxValueSize int `yaml:"max_value_size,omitempty"`
+	// Concurrency is the maximum number of secret references resolved concurrently (8 by default).
+	Concurrency int `yaml:"concurrency,omitempty"`
+
+	// allowedCommandsFiles maps the profiles of AllowedCommands to the configuration files defining them.
+	allowedCommandsFiles map[string]string
+}
+
+// Load loads a configuration file.
+func Load(path string) (*Config, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	c := &Config{}
+	if err := yaml.Unmarshal(b, c); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if c.KeyCase != "" && !slices.Contains(env.KeyCases, c.KeyCase) {
+		return nil, fmt.Errorf("invalid key_case in %s: %s", path, c.KeyCase)
+	}
+	for from, to := range c.Rename {
+		if from == "" || to == "" {
+			return nil, fmt.Errorf("invalid rename in %s: %q to %q", path, from, to)
+		}
+	}
+	for _, p := range c.MaskPatterns {
+		if _, err := regexp.Compile(p); err != nil {
+			return nil, fmt.Errorf("invalid mask_patterns in %s: %w", path, err)
+		}
+	}
+	for name, args := range c.Commands {
+		if name == "" || len(args) == 0 || args[0] == "" {
+			return nil, fmt.Errorf("invalid command %q in %s", name, path)
+		}
+	}
+	for _, f := range c.EnvFiles {
+		if f == "" {
+			return nil, fmt.Errorf("invalid env_files in %s: empty path", path)
+		}
+	}
+	if len(c.Providers) > 0 {
+		available := slices.Clone(provider.Schemes)
+		for _, p := range provider.FindPlugins(os.Getenv("PATH")) {
+			available = append(available, p.Scheme())
+		}
+		for _, p := range c.Providers {
+			if !slices.Contains(available, p) {
+				return nil, fmt.Errorf("invalid providers in %s: %s (available: %s)", path, p, strings.Join(available, ", "))
+			}
+		}
+	}
+	for p, commands := range c.AllowedCommands {
+		for _, command := range commands {
+			if _, err := filepath.Match(command, ""); err != nil || command == "" {
+				return nil, fmt.Errorf("invalid allowed_commands of %s in %s: %q", p, path, command)
+			}
+		}
+		if c.allowedCommandsFiles == nil {
+			c.allowedCommandsFiles = make(map[string]string)
+		}
+		c.allowedCommandsFiles[p] = path
+	}
+	if c.CacheTTL != "" {
+		if d, err := time.ParseDuration(c.CacheTTL); err != nil || d < 0 {
+			return nil, fmt.Errorf("invalid cache_ttl in %s: %q", path, c.CacheTTL)
+		}
+	}
+	if c.Concurrency < 0 {
+		return nil, fmt.Errorf("invalid concurrency in %s: %d", path, c.Concurrency)
+	}
+	for _, k := range slices.Concat(c.Required, c.Unset) {
+		if k == "" {
+			return nil, fmt.Errorf("invalid key in %s: empty key", path)
+		}
+	}
+	return c, nil
+}
+
+// Find loads the configuration file in dir.
+// If the configuration file does not exist, it returns an empty configuration.
+func Find(dir string) (*Config, error) {
+	return loadIfExists(filepath.Join(dir, Filename))
+}
+
+// Discover returns the configuration found like .env files: the configuration file in dir or, if walkUp
+// (or walk_up of the user configuration) is true, in the nearest parent directory up to the git repository
+// root, layered on the user configuration file in userDir. The project configuration takes priority over
+// the user configuration, and env_files of both are resolved to absolute paths.
+func Discover(dir string, walkUp bool, userDir string) (*Config, error) {
+	user, err := FindUser(userDir)
+	if err != nil {
+		return nil, err
+	}
+	user.EnvFiles = user.EnvFilePaths(userDir)
+	dirs := []string{dir}
+	if walkUp || user.WalkUp {
+		dirs = append(dirs, env.ParentDirs(dir)...)
+	}
+	for _, d := range dirs {
+		path := filepath.Join(d, Filename)
+		if _, err := os.Stat(path); err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		project, err := Load(path)
+		if err != nil {
+			return nil, err
+		}
+		project.EnvFiles = project.EnvFilePaths(d)
+		return merge(user, project), nil
+	}
+	return user, nil
+}
+
+// UserPath returns the path of the user configuration file in dir, the configuration directory of envdo.
+func UserPath(dir string) string {
+	return filepath.Join(dir, UserFilename)
+}
+
+// FindUser loads the user configuration file in dir, the configuration directory of envdo.
+// If the configuration file does not exist, it returns an empty configuration.
+func FindUser(dir string) (*Config, error) {
+	return loadIfExists(UserPath(dir))
+}
+
+// EnvFilePaths returns the paths of EnvFiles resolved against dir, the directory of the configuration file.
+func (c *Config) EnvFilePaths(dir string) []string {
+	paths := make([]string, 0, len(c.EnvFiles))
+	for _, f := range c.EnvFiles {
+		if !filepath.IsAbs(f) {
+			f = filepath.Join(dir, f)
+		}
+		paths = append(paths, f)
+	}
+	return paths
+}
+
+// AllowedCommandsFile returns the path of the configuration file defining the allowed commands of the profile.
+func (c *Config) AllowedCommandsFile(profile string) string {
+	if profile == "" {
+		profile = env.DefaultProfileName
+	}
+	return c.allowedCommandsFiles[profile]
+}
+
+// CommandAllowed reports whether the profile may be used with command, the name or path of the executable.
+func (c *Config) CommandAllowed(profile, command string) bool {
+	if profile == "" {
+		profile = env.DefaultProfileName
+	}
+	allowed, ok := c.AllowedCommands[profile]
+	if !ok {
+		return true
+	}
+	name := filepath.Base(command)
+	return slices.ContainsFunc(allowed, func(pattern string) bool {
+		for _, n := range []string{name, strings.TrimSuffix(strings.ToLower(name), ".exe")} {
+			if ok, _ := filepath.Match(pattern, n); ok {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// merge returns the configuration of project layered on user. The settings of project take priority,
+// and the lists of both are combined, except commands and allowed_commands: the presets and restrictions
+// of user take priority, so a cloned repository cannot replace or loosen them.
+func merge(user, project *Config) *Config {
+	c := *project
+	c.KeyCase = cmp.Or(project.KeyCase, user.KeyCase)
+	c.Profile = cmp.Or(project.Profile, user.Profile)
+	c.CacheTTL = cmp.Or(project.CacheTTL, user.CacheTTL)
+	c.Concurrency = cmp.Or(project.Concurrency, user.Concurrency)
+	c.MaxValueSize = cmp.Or(project.MaxValueSize, user.MaxValueSize)
+	c.WalkUp = project.WalkUp || user.WalkUp
+	c.Rename = mergeMaps(user.Rename, project.Rename)
+	c.Commands = mergeMaps(project.Commands, user.Commands)
+	c.AllowedCommands = mergeMaps(project.AllowedCommands, user.AllowedCommands)
+	c.allowedCommandsFiles = mergeMaps(project.allowedCommandsFiles, user.allowedCommandsFiles)
+	if len(project.Providers) == 0 {
+		c.Providers = user.Providers
+	}
+	c.MaskPatterns = slices.Concat(project.MaskPatterns, user.MaskPatterns)
+	c.EnvFiles = slices.Concat(project.EnvFiles, user.EnvFiles)
+	c.Required = slices.Concat(project.Required, user.Required)
+	c.Unset = slices.Concat(project.Unset, user.Unset)
+	return &c
+}
+
+// mergeMaps returns the entries of base and over, those of over taking priority.
+func mergeMaps[V any](base, over map[string]V) map[string]V {
+	if len(base) == 0 && len(over) == 0 {
+		return nil
+	}
+	m := make(map[string]V, len(base)+len(over))
+	maps.Copy(m, base)
+	maps.Copy(m, over)
+	return m
+}
+
+// loadIfExists loads the configuration file at path, or returns an empty configuration if it does not exist.
+func loadIfExists(path string) (*Config, error) {
+	if _, err := os.Stat(path); err != nil {
+		if os.IsNotExist(err) {
+			return &Config{}, nil
+		}
+		return nil, err
+	}
+	return Load(path)
+}
@@ -0,0 +1,200 @@
+package config
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestFind(t *testing.T) {
+	dir := t.TempDir()
+	c, err := Find(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Rename) != 0 {
+		t.Errorf("want empty config, got %+v", c)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, Filename), []byte(`key_case: upper
+rename:
+  PG_PASSWORD: PGPASSWORD
+  TOKEN: GITHUB_TOKEN
+`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	c, err = Find(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.KeyCase != "upper" {
+		t.Errorf("got %s", c.KeyCase)
+	}
+	want := map[string]string{"PG_PASSWORD": "PGPASSWORD", "TOKEN": "GITHUB_TOKEN"}
+	if !maps.Equal(c.Rename, want) {
+		t.Errorf("got %v, want %v", c.Rename, want)
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), Filename)
+	for _, content := range []string{
+		"rename:\n  TOKEN: \"\"\n",
+		"key_case: camel\n",
+		"mask_patterns:\n  - \"(\"\n",
+		"commands:\n  deploy: []\n",
+		"providers:\n  - aws\n",
+		"unset:\n  - \"\"\n",
+		"allowed_commands:\n  production: [\"[\"]\n",
+		"cache_ttl: 10\n",
+		"concurrency: -1\n",
+	} {
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(path); err == nil {
+			t.Errorf("%q: want error", content)
+		}
+	}
+}
+
+func TestLoadProject(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, Filename), []byte(`profile: dev
+env_files:
+  - config/shared.env
+providers: [op, keychain]
+required: [DATABASE_URL]
+unset: [AWS_PROFILE]
+`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	c, err := Find(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Profile != "dev" {
+		t.Errorf("profile = %s", c.Profile)
+	}
+	if got, want := c.EnvFilePaths(dir), []string{filepath.Join(dir, "config", "shared.env")}; !slices.Equal(got, want) {
+		t.Errorf("env files = %v, want %v", got, want)
+	}
+	if !slices.Equal(c.Providers, []string{"op", "keychain"}) || !slices.Equal(c.Required, []string{"DATABASE_URL"}) || !slices.Equal(c.Unset, []string{"AWS_PROFILE"}) {
+		t.Errorf("got %+v", c)
+	}
+}
+
+func TestCommandAllowed(t *testing.T) {
+	c := &Config{AllowedCommands: map[string][]string{
+		"production": {"kubectl", "terraform*"},
+		"default":    {"npm"},
+	}}
+	tests := []struct {
+		profile string
+		command string
+		want    bool
+	}{
+		{"production", "kubectl", true},
+		{"production", "/usr/local/bin/kubectl", true},
+		{"production", "kubectl.exe", true},
+		{"production", "terraform1.5", true},
+		{"production", "rm", false},
+		{"", "npm", true},
+		{"", "node", false},
+		{"dev", "rm", true},
+	}
+	for _, tt := range tests {
+		if got := c.CommandAllowed(tt.profile, tt.command); got != tt.want {
+			t.Errorf("CommandAllowed(%q, %q) = %v, want %v", tt.profile, tt.command, got, tt.want)
+		}
+	}
+}
+
+func TestFindUser(t *testing.T) {
+	configDir := t.TempDir()
+	c, err := FindUser(configDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Commands) != 0 {
+		t.Errorf("want empty config, got %+v", c)
+	}
+	if err := os.WriteFile(UserPath(configDir), []byte(`commands:
+  deploy: ["terraform", "apply"]
+`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	c, err = FindUser(configDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Commands["deploy"]; len(got) != 2 || got[0] != "terraform" || got[1] != "apply" {
+		t.Errorf("got %v", got)
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	userDir := t.TempDir()
+	if err := os.WriteFile(UserPath(userDir), []byte(`profile: user
+key_case: upper
+env_files: [user.env]
+unset: [USER_KEY]
+rename:
+  A: USER_A
+  B: USER_B
+`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	root := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, ".git"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	dir := filepath.Join(root, "sub")
+	if err := os.Mkdir(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	// Without a project configuration, the user configuration is used
+	c, err := Discover(dir, true, userDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Profile != "user" || !slices.Equal(c.EnvFiles, []string{filepath.Join(userDir, "user.env")}) {
+		t.Errorf("got %+v", c)
+	}
+
+	if err := os.WriteFile(filepath.Join(root, Filename), []byte(`profile: project
+env_files: [project.env]
+unset: [PROJECT_KEY]
+rename:
+  A: PROJECT_A
+`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	// The project configuration in the parent directory is found only when walking up
+	c, err = Discover(dir, false, userDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Profile != "user" {
+		t.Errorf("got profile %s, want user", c.Profile)
+	}
+	c, err = Discover(dir, true, userDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Profile != "project" || c.KeyCase != "upper" {
+		t.Errorf("got profile %s and key_case %s, want project and upper", c.Profile, c.KeyCase)
+	}
+	if want := []string{filepath.Join(root, "project.env"), filepath.Join(userDir, "user.env")}; !slices.Equal(c.EnvFiles, want) {
+		t.Errorf("got env_files %v, want %v", c.EnvFiles, want)
+	}
+	if want := []string{"PROJECT_KEY", "USER_KEY"}; !slices.Equal(c.Unset, want) {
+		t.Errorf("got unset %v, want %v", c.Unset, want)
+	}
+	if want := map[string]string{"A": "PROJECT_A", "B": "USER_B"}; !maps.Equal(c.Rename, want) {
+		t.Errorf("got rename %v, want %v", c.Rename, want)
+	}
+}
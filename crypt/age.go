@@ -0,0 +1,95 @@
+// Package crypt encrypts and decrypts environment variables with age.
+package crypt
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+)
+
+// KeyFileEnv is the environment variable name of the age identity file path.
+const KeyFileEnv = "AGE_KEY_FILE"
+
+// DefaultKeyFile returns the default age identity file path in dir, the configuration directory of envdo.
+func DefaultKeyFile(dir string) string {
+	return filepath.Join(dir, "age", "keys.txt")
+}
+
+// LoadIdentities loads age identities from $AGE_KEY_FILE or the default identity file in dir,
+// the configuration directory of envdo.
+func LoadIdentities(dir string) ([]age.Identity, error) {
+	path := os.Getenv(KeyFileEnv)
+	if path == "" {
+		path = DefaultKeyFile(dir)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open age identity file (set %s or create %s): %w", KeyFileEnv, DefaultKeyFile(dir), err)
+	}
+	defer f.Close()
+	ids, err := age.ParseIdentities(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse age identity file %s: %w", path, err)
+	}
+	return ids, nil
+}
+
+// ParseRecipients parses age public keys (age1...).
+func ParseRecipients(keys []string) ([]age.Recipient, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("no age recipients")
+	}
+	recipients := make([]age.Recipient, 0, len(keys))
+	for _, k := range keys {
+		r, err := age.ParseX25519Recipient(strings.TrimSpace(k))
+		if err != nil {
+			return nil, fmt.Errorf("invalid age recipient %q: %w", k, err)
+		}
+		recipients = append(recipients, r)
+	}
+	return recipients, nil
+}
+
+// Encrypt encrypts plaintext to recipients as ASCII-armored age ciphertext.
+func Encrypt(plaintext []byte, recipients []age.Recipient) ([]byte, error) {
+	var buf bytes.Buffer
+	aw := armor.NewWriter(&buf)
+	w, err := age.Encrypt(aw, recipients...)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(plaintext); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	if err := aw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decrypt decrypts age ciphertext, either ASCII-armored or binary, with identities.
+func Decrypt(ciphertext []byte, identities []age.Identity) ([]byte, error) {
+	var src io.Reader = bytes.NewReader(ciphertext)
+	br := bufio.NewReader(src)
+	if start, _ := br.Peek(len(armor.Header)); string(start) == armor.Header {
+		src = armor.NewReader(br)
+	} else {
+		src = br
+	}
+	r, err := age.Decrypt(src, identities...)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
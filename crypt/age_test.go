@@ -0,0 +1,67 @@
+package crypt
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"filippo.io/age"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	id, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	recipients, err := ParseRecipients([]string{id.Recipient().String()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	plaintext := []byte("API_KEY=secret\n")
+	ciphertext, err := Encrypt(plaintext, recipients)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(ciphertext, []byte("-----BEGIN AGE ENCRYPTED FILE-----")) {
+		t.Errorf("want armored ciphertext, got %q", ciphertext)
+	}
+
+	configDir := t.TempDir()
+	keyFile := DefaultKeyFile(configDir)
+	if err := os.MkdirAll(filepath.Dir(keyFile), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, []byte(id.String()+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(KeyFileEnv, "")
+	ids, err := LoadIdentities(configDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := Decrypt(ciphertext, ids)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, plaintext) {
+		t.Errorf("want %q, got %q", plaintext, got)
+	}
+
+	other, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Decrypt(ciphertext, []age.Identity{other}); err == nil {
+		t.Error("want error with another identity but got none")
+	}
+}
+
+func TestParseRecipientsInvalid(t *testing.T) {
+	if _, err := ParseRecipients([]string{"not-a-key"}); err == nil {
+		t.Error("want error but got none")
+	}
+	if _, err := ParseRecipients(nil); err == nil {
+		t.Error("want error but got none")
+	}
+}
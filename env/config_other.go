@@ -0,0 +1,17 @@
+//go:build !windows
+
+package env
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// userConfigDir returns ~/.config, or an empty string if the home directory is unknown.
+func userConfigDir() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(homeDir, ".config")
+}
@@ -0,0 +1,14 @@
+//go:build windows
+
+package env
+
+import "os"
+
+// userConfigDir returns %APPDATA%, or an empty string if it is not set.
+func userConfigDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return dir
+}
@@ -0,0 +1,20 @@
+//go:build windows
+
+package env
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestDefaultConfigDirWindows(t *testing.T) {
+	appData := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("APPDATA", appData)
+	if got := DefaultConfigDir(); got != appData {
+		t.Errorf("got %s, want %s", got, appData)
+	}
+	if got, want := New("", DefaultConfigDir()).ProfilePath("dev"), filepath.Join(appData, "envdo", ".env.dev"); got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
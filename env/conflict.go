@@ -0,0 +1,31 @@
+package env
+
+import (
+	"slices"
+	"strings"
+)
+
+// Conflict is a key defined more than once.
+type Conflict struct {
+	Key string `json:"key"`
+	// Entries are the definitions of the key in priority order. The first one wins.
+	Entries []Entry `json:"entries"`
+}
+
+// Conflicts returns keys defined more than once in entries in priority order, sorted by key.
+func Conflicts(entries []Entry) []Conflict {
+	defs := make(map[string][]Entry)
+	for _, e := range entries {
+		defs[e.Key] = append(defs[e.Key], e)
+	}
+	var conflicts []Conflict
+	for k, es := range defs {
+		if len(es) > 1 {
+			conflicts = append(conflicts, Conflict{Key: k, Entries: es})
+		}
+	}
+	slices.SortFunc(conflicts, func(a, b Conflict) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+	return conflicts
+}
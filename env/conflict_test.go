@@ -0,0 +1,48 @@
+package env
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestConflicts(t *testing.T) {
+	pwd := t.TempDir()
+	configDir := t.TempDir()
+	createTestFile(t, pwd, ".env", "API_KEY=pwd\nPORT=3000\nPORT=4000\n")
+	if err := os.MkdirAll(filepath.Join(configDir, "envdo"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	createTestFile(t, filepath.Join(configDir, "envdo"), ".env", "API_KEY=config\nDEBUG=true\n")
+
+	entries, err := New(pwd, configDir).Entries("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := Conflicts(entries)
+	pwdFile := filepath.Join(pwd, ".env")
+	configFile := filepath.Join(configDir, "envdo", ".env")
+	want := []Conflict{
+		{Key: "API_KEY", Entries: []Entry{
+			{Key: "API_KEY", Value: "pwd", File: pwdFile, Line: 1},
+			{Key: "API_KEY", Value: "config", File: configFile, Line: 1},
+		}},
+		{Key: "PORT", Entries: []Entry{
+			{Key: "PORT", Value: "4000", File: pwdFile, Line: 3},
+			{Key: "PORT", Value: "3000", File: pwdFile, Line: 2},
+		}},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %+v", got)
+	}
+	for i := range want {
+		if got[i].Key != want[i].Key || len(got[i].Entries) != len(want[i].Entries) {
+			t.Fatalf("got %+v, want %+v", got[i], want[i])
+		}
+		for j := range want[i].Entries {
+			if got[i].Entries[j] != want[i].Entries[j] {
+				t.Errorf("got %+v, want %+v", got[i].Entries[j], want[i].Entries[j])
+			}
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package env
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"filippo.io/age"
+	"github.com/k1LoW/envdo/crypt"
+)
+
+// EncryptedSuffix is the suffix of encrypted .env files.
+const EncryptedSuffix = ".age"
+
+// Decryptor decrypts encrypted .env files.
+type Decryptor interface {
+	Decrypt(ciphertext []byte) ([]byte, error)
+}
+
+// AgeDecryptor decrypts .env files encrypted with age using the identities
+// in $AGE_KEY_FILE or age/keys.txt in the configuration directory of envdo.
+// The identities are loaded on first use.
+type AgeDecryptor struct {
+	dir        string
+	once       sync.Once
+	identities []age.Identity
+	err        error
+}
+
+// NewAgeDecryptor creates a new AgeDecryptor with dir, the configuration directory of envdo ($XDG_CONFIG_HOME/envdo).
+func NewAgeDecryptor(dir string) *AgeDecryptor {
+	return &AgeDecryptor{dir: dir}
+}
+
+// Decrypt decrypts age ciphertext.
+func (d *AgeDecryptor) Decrypt(ciphertext []byte) ([]byte, error) {
+	d.once.Do(func() {
+		d.identities, d.err = crypt.LoadIdentities(d.dir)
+	})
+	if d.err != nil {
+		return nil, d.err
+	}
+	return crypt.Decrypt(bytes.TrimSpace(ciphertext), d.identities)
+}
+
+// IsEncrypted reports whether the .env file at path is encrypted with age, or a .env.vault file.
+func IsEncrypted(path string) bool {
+	return strings.HasSuffix(path, EncryptedSuffix) || IsVault(path)
+}
+
+// openFile opens the .env file at path, decrypting it if encrypted.
+func openFile(path string, d Decryptor) (io.ReadCloser, error) {
+	if !IsEncrypted(path) {
+		return os.Open(path)
+	}
+	if d == nil {
+		if IsVault(path) {
+			return nil, fmt.Errorf("%s is not set", DotenvKeyEnv)
+		}
+		return nil, errors.New("no decryptor for encrypted files")
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := d.Decrypt(b)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt: %w", err)
+	}
+	return io.NopCloser(bytes.NewReader(plaintext)), nil
+}
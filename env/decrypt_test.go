@@ -0,0 +1,62 @@
+package env
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/k1LoW/envdo/crypt"
+)
+
+func TestLoadEncryptedEnvFiles(t *testing.T) {
+	id, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	configDir := t.TempDir()
+	keyFile := crypt.DefaultKeyFile(configDir)
+	if err := os.MkdirAll(filepath.Dir(keyFile), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, []byte(id.String()+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(crypt.KeyFileEnv, "")
+
+	recipients, err := crypt.ParseRecipients([]string{id.Recipient().String()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ciphertext, err := crypt.Encrypt([]byte("API_KEY=secret\nPORT=3000\n"), recipients)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pwd := t.TempDir()
+	if err := os.WriteFile(filepath.Join(pwd, ".env.production.age"), ciphertext, 0600); err != nil {
+		t.Fatal(err)
+	}
+	createTestFile(t, pwd, ".env.production", "PORT=8080\n")
+
+	e := New(pwd, configDir)
+	if _, err := e.LoadEnvFiles("production"); err == nil {
+		t.Error("want error without decryptor")
+	}
+	e.SetDecryptor(NewAgeDecryptor(configDir))
+	envs, err := e.LoadEnvFiles("production")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The plain file takes priority over the encrypted file
+	if envs["API_KEY"] != "secret" || envs["PORT"] != "8080" {
+		t.Errorf("got %v", envs)
+	}
+
+	issues, err := Lint(filepath.Join(pwd, ".env.production.age"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 0 {
+		t.Errorf("encrypted files should not be linted: %+v", issues)
+	}
+}
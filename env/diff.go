@@ -0,0 +1,41 @@
+package env
+
+import "maps"
+
+// Types of changes between environments.
+const (
+	ChangeAdded   = "added"
+	ChangeRemoved = "removed"
+	ChangeChanged = "changed"
+)
+
+// Change is a difference of a key between two environments.
+type Change struct {
+	Key  string `json:"key"`
+	Type string `json:"type"`
+	// From is the value in the environment compared from. It is empty if the key is added.
+	From string `json:"from,omitempty"`
+	// To is the value in the environment compared to. It is empty if the key is removed.
+	To string `json:"to,omitempty"`
+}
+
+// Diff returns the changes of keys from the environment from to the environment to, sorted by key.
+func Diff(from, to map[string]string) []Change {
+	all := make(map[string]string, len(from)+len(to))
+	maps.Copy(all, from)
+	maps.Copy(all, to)
+	var changes []Change
+	for _, k := range sortedKeys(all) {
+		f, inFrom := from[k]
+		t, inTo := to[k]
+		switch {
+		case !inFrom:
+			changes = append(changes, Change{Key: k, Type: ChangeAdded, To: t})
+		case !inTo:
+			changes = append(changes, Change{Key: k, Type: ChangeRemoved, From: f})
+		case f != t:
+			changes = append(changes, Change{Key: k, Type: ChangeChanged, From: f, To: t})
+		}
+	}
+	return changes
+}
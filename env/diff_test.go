@@ -0,0 +1,23 @@
+package env
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	from := map[string]string{"A": "1", "B": "2", "C": "3"}
+	to := map[string]string{"A": "1", "B": "20", "D": "4"}
+	got := Diff(from, to)
+	want := []Change{
+		{Key: "B", Type: ChangeChanged, From: "2", To: "20"},
+		{Key: "C", Type: ChangeRemoved, From: "3"},
+		{Key: "D", Type: ChangeAdded, To: "4"},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got := Diff(from, from); len(got) != 0 {
+		t.Errorf("got %v, want no changes", got)
+	}
+}
@@ -2,110 +2,439 @@ package env
 
 import (
 	"bufio"
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"slices"
 	"strings"
+
+	"github.com/k1LoW/envdo/internal/api"
+)
+
+const (
+	// maxLineSize is the maximum size of a line in .env files.
+	maxLineSize = 1024 * 1024
+	// avgLineSize is the estimated average size of a line in .env files.
+	avgLineSize = 32
 )
 
 // Env represents an environment loader with configurable directories.
 type Env struct {
-	pwd       string
-	configDir string
+	pwd string
+	// profileDir is the directory of the profile files, searched last (configDir/envdo).
+	profileDir string
+	decryptor  Decryptor
+	base       *string
+	// dirs overrides the search directories if set.
+	dirs []string
+	// walkUp makes the parent directories of pwd searched.
+	walkUp bool
+	// envDirs are searched after pwd and before configDir/envdo.
+	envDirs []string
+	// extraFiles are loaded with the lowest priority for every profile.
+	extraFiles []string
+	// unset are keys removed from the loaded environment variables.
+	unset []string
+	// remote fetches remote env files given to LoadFiles.
+	remote *Remote
+	// vault decrypts .env.vault files.
+	vault  *Vault
+	logger *slog.Logger
+}
+
+// Entry is a definition of an environment variable in a .env file.
+type Entry struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	File  string `json:"file"`
+	Line  int    `json:"line"`
+	// Unset is true if the line removes the variable (!KEY or unset KEY) instead of defining it.
+	Unset bool `json:"unset,omitempty"`
+	// Append is true if the line appends the value to the list value defined before (KEY+=value).
+	Append bool `json:"append,omitempty"`
+	// Prepend is true if the line prepends the value to the list value defined before (KEY^=value).
+	Prepend bool `json:"prepend,omitempty"`
+	// Literal is true if the value is single-quoted, which is not expanded.
+	Literal bool `json:"literal,omitempty"`
 }
 
 // New creates a new Env instance with specified directories.
+// The profile files are searched in configDir/envdo after pwd.
 func New(pwd, configDir string) *Env {
-	return &Env{
-		pwd:       pwd,
-		configDir: configDir,
+	e := &Env{
+		pwd:    pwd,
+		logger: slog.New(slog.DiscardHandler),
+	}
+	if configDir != "" {
+		e.profileDir = filepath.Join(configDir, "envdo")
 	}
+	return e
 }
 
 // LoadEnvFiles loads .env files from multiple directories with priority.
 // Priority: pwd > configDir/envdo.
 func (e *Env) LoadEnvFiles(profile string) (map[string]string, error) {
-	envs := make(map[string]string)
+	files, err := e.loadOrder(profile)
+	if err != nil {
+		return nil, err
+	}
+	return e.LoadFiles(files)
+}
 
-	// Determine .env filename
-	filename := ".env"
-	if profile != "" {
-		filename = fmt.Sprintf(".env.%s", profile)
+// LoadEnvEntries loads .env files like LoadEnvFiles, but returns the definitions of the loaded variables,
+// so the file and line each value comes from can be shown.
+func (e *Env) LoadEnvEntries(profile string) (map[string]Entry, error) {
+	files, err := e.loadOrder(profile)
+	if err != nil {
+		return nil, err
 	}
+	return e.LoadFileEntries(files)
+}
 
-	// Get directories to search
-	dirs := e.getSearchDirectories()
+// LoadFiles loads the .env files in order. Later files override earlier ones.
+// Files may be URLs of remote env files (https://, s3:// and gs://).
+// Unlike LoadEnvFiles, it returns an error if a file does not exist.
+func (e *Env) LoadFiles(files []string) (map[string]string, error) {
+	envs := make(map[string]string, sizeHint(files))
+	if err := mergeFiles(e, files, envs, func(en Entry) string { return en.Value }); err != nil {
+		return nil, err
+	}
+	return envs, nil
+}
 
-	// Check if any file exists when profile is specified
-	if profile != "" {
-		fileFound := false
-		for _, dir := range dirs {
-			envPath := filepath.Join(dir, filename)
-			if _, err := os.Stat(envPath); err == nil {
-				fileFound = true
-				break
-			}
+// LoadFileEntries loads the files like LoadFiles, but returns the definitions of the loaded variables.
+func (e *Env) LoadFileEntries(files []string) (map[string]Entry, error) {
+	entries := make(map[string]Entry, sizeHint(files))
+	if err := mergeFiles(e, files, entries, func(en Entry) Entry { return en }); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// Entries returns the definitions of environment variables in the .env files for the profile
+// in priority order, so the first definition of each key is the one loaded.
+func (e *Env) Entries(profile string) ([]Entry, error) {
+	return e.ReadEntries(e.Files(profile))
+}
+
+// ReadEntries returns the definitions of environment variables in files given in priority order.
+// The returned definitions are also in priority order.
+func (e *Env) ReadEntries(files []string) ([]Entry, error) {
+	var entries []Entry
+	for _, f := range files {
+		file, err := openFile(f, e.decryptorOf(f))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s: %w", f, err)
 		}
-		if !fileFound {
-			return nil, fmt.Errorf("environment file %s not found in any search directory", filename)
+		es, err := parseEntries(file, f)
+		_ = file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s: %w", f, err)
 		}
+		slices.Reverse(es)
+		entries = append(entries, es...)
 	}
+	return entries, nil
+}
 
-	// Load from directories in reverse order (lower priority first)
-	slices.Reverse(dirs)
-	for _, dir := range dirs {
-		envPath := filepath.Join(dir, filename)
-		if _, err := os.Stat(envPath); err == nil {
-			if err := loadEnvFile(envPath, envs); err != nil {
-				return nil, fmt.Errorf("failed to load %s: %w", envPath, err)
-			}
+// Files returns the paths of existing .env files for the profile in priority order,
+// followed by the files of the base profiles it inherits and the extra files set by SetExtraFiles.
+// In each directory, the dotenv file takes priority over the structured files
+// (.env.<profile>.json, .env.<profile>.yaml, .env.<profile>.yml, .env.<profile>.toml and .env.<profile>.ini),
+// and plain files take priority over the encrypted files (.env.<profile>.age).
+func (e *Env) Files(profile string) []string {
+	var bases []string
+	if e.base != nil {
+		bases = []string{*e.base}
+	}
+	files := e.layeredFiles(profile, bases, make(map[string]bool))
+	for _, f := range e.extraFiles {
+		if !slices.Contains(files, f) {
+			files = append(files, f)
 		}
 	}
+	return files
+}
 
-	return envs, nil
+// SetBase sets the base profile that the profile given to Files and LoadEnvFiles inherits,
+// in addition to the #!inherit directives. It has the lowest priority.
+// ".env" means the default profile.
+func (e *Env) SetBase(base string) {
+	b := BaseProfile(base)
+	e.base = &b
+}
+
+// SetWalkUp sets whether the parent directories of pwd up to the git repository root
+// (or the filesystem root outside git repositories) are searched too.
+// Nearer directories take priority.
+func (e *Env) SetWalkUp(walkUp bool) {
+	e.walkUp = walkUp
+}
+
+// SetEnvDirs sets additional directories of .env files (e.g. a shared repository of profiles)
+// searched after pwd (and its parents) and before configDir/envdo, in priority order.
+func (e *Env) SetEnvDirs(dirs []string) {
+	e.envDirs = dirs
+}
+
+// SetExtraFiles sets the .env files loaded for every profile with the lowest priority
+// (e.g. env_files of .envdo.yml). Unlike the files found in the search directories, they must exist.
+func (e *Env) SetExtraFiles(files []string) {
+	e.extraFiles = files
+}
+
+// SetUnset sets the keys removed from the loaded environment variables
+// (e.g. unset of .envdo.yml and --unset), in addition to the !KEY and unset KEY lines of .env files.
+func (e *Env) SetUnset(keys []string) {
+	e.unset = keys
+}
+
+// Unset returns the keys set by SetUnset.
+func (e *Env) Unset() []string {
+	return e.unset
+}
+
+// SetRemote sets the fetcher of remote env files given to LoadFiles.
+func (e *Env) SetRemote(r *Remote) {
+	e.remote = r
+}
+
+// SetLogger sets the logger of debug messages such as the .env files found, skipped and merged.
+func (e *Env) SetLogger(l *slog.Logger) {
+	e.logger = l
+}
+
+// SetDecryptor sets the decryptor of encrypted .env files.
+func (e *Env) SetDecryptor(d Decryptor) {
+	e.decryptor = d
+}
+
+// SetVault sets the decryptor of .env.vault files. With a vault, the .env.vault file
+// in each search directory is loaded for the profile of the environment of its keys
+// with the lowest priority in the directory.
+func (e *Env) SetVault(v *Vault) {
+	e.vault = v
+}
+
+// SetProfileDir sets the directory of the profile files searched last, instead of configDir/envdo.
+func (e *Env) SetProfileDir(dir string) {
+	e.profileDir = dir
+}
+
+// ProfilePath returns the path of the profile file in configDir/envdo.
+func (e *Env) ProfilePath(profile string) string {
+	return filepath.Join(e.profileDir, Filename(profile))
 }
 
 // getSearchDirectories returns directories to search for .env files.
-// Returns in priority order: [pwd, configDir/envdo].
+// Returns in priority order: [pwd, (parents of pwd,) env dirs, the profile directory].
 func (e *Env) getSearchDirectories() []string {
+	if e.dirs != nil {
+		return e.dirs
+	}
 	dirs := []string{}
 
 	// Current directory (highest priority)
 	if e.pwd != "" {
 		dirs = append(dirs, e.pwd)
+		if e.walkUp {
+			dirs = append(dirs, ParentDirs(e.pwd)...)
+		}
 	}
 
+	// Additional directories set by SetEnvDirs
+	dirs = append(dirs, e.envDirs...)
+
 	// Config directory/envdo
-	if e.configDir != "" {
-		envdoConfigDir := filepath.Join(e.configDir, "envdo")
-		dirs = append(dirs, envdoConfigDir)
+	if e.profileDir != "" {
+		dirs = append(dirs, e.profileDir)
 	}
 
 	return dirs
 }
 
+// profileFiles returns the paths of existing .env files of the profile itself in priority order.
+func (e *Env) profileFiles(profile string) []string {
+	var files []string
+	for _, dir := range e.getSearchDirectories() {
+		envPath := filepath.Join(dir, Filename(profile))
+		candidates := []string{envPath}
+		for _, ext := range StructuredExtensions {
+			candidates = append(candidates, envPath+ext)
+		}
+		for _, c := range candidates {
+			if _, err := os.Stat(c); err == nil {
+				files = append(files, c)
+			}
+		}
+		for _, c := range candidates {
+			if _, err := os.Stat(c + EncryptedSuffix); err == nil {
+				files = append(files, c+EncryptedSuffix)
+			}
+		}
+		if e.vault != nil && e.vault.Has(profile) {
+			vaultPath := filepath.Join(dir, VaultFilename)
+			if _, err := os.Stat(vaultPath); err == nil {
+				files = append(files, vaultPath)
+			}
+		}
+	}
+	return files
+}
+
+// layeredFiles returns the files of the profile and its bases in priority order.
+// Bases listed later take priority over earlier ones: extraBases, the bases of the #!inherit directives
+// and the parents of a hierarchical profile. Profiles already visited are skipped.
+func (e *Env) layeredFiles(profile string, extraBases []string, visited map[string]bool) []string {
+	if visited[profile] {
+		return nil
+	}
+	visited[profile] = true
+	files := e.profileFiles(profile)
+	parents := parentProfiles(profile)
+	if len(files) > 0 && len(parents) > 0 {
+		e.logger.Debug("layered a hierarchical profile on its parents", "profile", profile, "parents", parents)
+	}
+	bases := slices.Concat(extraBases, inheritedProfiles(files), parents)
+	slices.Reverse(bases)
+	for _, b := range bases {
+		files = append(files, e.layeredFiles(b, nil, visited)...)
+	}
+	return files
+}
+
+// decryptorOf returns the decryptor of the encrypted .env file at path, or nil if not available.
+func (e *Env) decryptorOf(path string) Decryptor {
+	if !IsVault(path) {
+		return e.decryptor
+	}
+	if e.vault == nil {
+		return nil
+	}
+	return e.vault
+}
+
+// loadOrder returns the existing .env files for the profile in load order (lower priority first).
+// It returns an error if no file of the profile itself exists.
+func (e *Env) loadOrder(profile string) ([]string, error) {
+	// Get existing files in priority order
+	filename := Filename(profile)
+	files := e.Files(profile)
+
+	// Check if any file exists when profile is specified
+	if profile != "" && len(e.profileFiles(profile)) == 0 {
+		return nil, fmt.Errorf("environment file %s not found in any search directory", filename)
+	}
+	for _, dir := range e.getSearchDirectories() {
+		if !slices.ContainsFunc(files, func(f string) bool { return filepath.Dir(f) == dir }) {
+			e.logger.Debug("skipped a directory without .env files", "dir", dir, "file", filename)
+		}
+	}
+	for _, f := range files {
+		e.logger.Debug("found a .env file", "file", f)
+	}
+
+	// Load from files in reverse order (lower priority first)
+	slices.Reverse(files)
+	return files, nil
+}
+
+// scanEnvFile calls fn for each definition in the .env file, decrypting it if encrypted.
+func (e *Env) scanEnvFile(filename string, fn func(Entry)) error {
+	r, err := openFile(filename, e.decryptorOf(filename))
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	return scanFile(r, filename, fn)
+}
+
+// mergeFiles merges the definitions in files into envs in order, storing the values given by value.
+// Variables unset by !KEY or unset KEY lines and the keys set by SetUnset are removed from envs,
+// and list values of KEY+=value and KEY^=value lines are extended.
+func mergeFiles[V any](e *Env, files []string, envs map[string]V, value func(Entry) V) error {
+	m := newListMerger()
+	merge := func(en Entry) {
+		en.Value = m.merge(en)
+		if en.Unset {
+			delete(envs, en.Key)
+			return
+		}
+		envs[en.Key] = value(en)
+	}
+	for _, envPath := range files {
+		if IsRemote(envPath) {
+			if e.remote == nil {
+				return fmt.Errorf("remote env files are not enabled: %s", envPath)
+			}
+			if err := e.remote.scan(context.Background(), envPath, merge); err != nil {
+				return err
+			}
+			e.logger.Debug("merged a remote env file", "url", envPath, "keys", len(envs))
+			continue
+		}
+		if err := e.scanEnvFile(envPath, merge); err != nil {
+			if os.IsNotExist(err) {
+				return err
+			}
+			return fmt.Errorf("failed to load %s: %w", envPath, err)
+		}
+		e.logger.Debug("merged a .env file", "file", envPath, "keys", len(envs))
+	}
+	for _, k := range e.unset {
+		if _, ok := envs[k]; ok {
+			e.logger.Debug("unset a key", "key", k)
+		}
+		delete(envs, k)
+	}
+	return nil
+}
+
 // LoadEnvFiles loads .env files from multiple directories with priority.
 // Priority: current directory > XDG_CONFIG_HOME/envdo.
 // This function maintains backward compatibility by using default directories.
 func LoadEnvFiles(profile string) (map[string]string, error) {
+	return Default().LoadEnvFiles(profile)
+}
+
+// Default creates a new Env instance with the current directory and the default config directory.
+// Encrypted .env files are decrypted with age identities, .env.vault files with $DOTENV_KEY if set,
+// and $ENVDO_URL_TOKEN is sent to the remote env URLs of the hosts in $ENVDO_URL_TOKEN_HOSTS.
+func Default() *Env {
 	// Get current working directory
 	pwd, err := os.Getwd()
 	if err != nil {
 		pwd = ""
 	}
+	configDir := DefaultConfigDir()
+	e := New(pwd, configDir)
+	e.SetDecryptor(NewAgeDecryptor(filepath.Join(configDir, "envdo")))
+	e.SetRemote(NewRemote(api.NewClient(), os.Getenv(URLTokenEnv), urlTokenHosts()))
+	if v, err := NewVault(os.Getenv(DotenvKeyEnv)); err == nil {
+		e.SetVault(v)
+	}
+	return e
+}
 
-	// Get config directory
-	configDir := os.Getenv("XDG_CONFIG_HOME")
-	if configDir == "" {
-		if homeDir, err := os.UserHomeDir(); err == nil {
-			configDir = filepath.Join(homeDir, ".config")
-		}
+// DefaultConfigDir returns $XDG_CONFIG_HOME, falling back to ~/.config (%APPDATA% on Windows).
+// The files of envdo are in the envdo directory under it.
+func DefaultConfigDir() string {
+	if configDir := os.Getenv("XDG_CONFIG_HOME"); configDir != "" {
+		return configDir
 	}
+	return userConfigDir()
+}
 
-	// Create Env instance with default directories
-	env := New(pwd, configDir)
-	return env.LoadEnvFiles(profile)
+// Filename returns the .env filename for the profile.
+func Filename(profile string) string {
+	if profile == "" {
+		return ".env"
+	}
+	return fmt.Sprintf(".env.%s", profile)
 }
 
 // loadEnvFile loads environment variables from a .env file.
@@ -120,34 +449,276 @@ func loadEnvFile(filename string, envs map[string]string) error {
 	}
 	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
+	return parseFile(file, filename, envs)
+}
+
+// Parse parses environment variables in .env format from r.
+func Parse(r io.Reader) (map[string]string, error) {
+	envs := make(map[string]string)
+	if err := parse(r, envs); err != nil {
+		return nil, err
+	}
+	return envs, nil
+}
+
+// ParseEntries parses definitions of environment variables in .env format from r, named name in the entries,
+// in priority order like ReadEntries.
+func ParseEntries(r io.Reader, name string) ([]Entry, error) {
+	var entries []Entry
+	if err := scanEntries(r, name, func(e Entry) {
+		entries = append(entries, e)
+	}); err != nil {
+		return nil, err
+	}
+	slices.Reverse(entries)
+	return entries, nil
+}
+
+// parse parses environment variables in .env format from r into envs.
+func parse(r io.Reader, envs map[string]string) error {
+	return parseFile(r, "", envs)
+}
+
+// parseFile parses environment variables from r in the format of file into envs.
+// Variables unset by !KEY or unset KEY lines are removed from envs.
+func parseFile(r io.Reader, file string, envs map[string]string) error {
+	return scanFile(r, file, func(e Entry) {
+		if e.Unset {
+			delete(envs, e.Key)
+			return
+		}
+		envs[e.Key] = e.Value
+	})
+}
+
+// parseEntries parses definitions of environment variables from r in the format of file.
+func parseEntries(r io.Reader, file string) ([]Entry, error) {
+	var entries []Entry
+	err := scanFile(r, file, func(e Entry) {
+		entries = append(entries, e)
+	})
+	return entries, err
+}
+
+// scanEntries parses definitions of environment variables in .env format from r
+// and calls fn for each definition in order, without buffering the whole input.
+func scanEntries(r io.Reader, file string, fn func(Entry)) error {
+	err := scanLogicalLines(r, func(n int, line string) error {
+		line = strings.TrimSpace(line)
 
 		// Skip empty lines and comments
-		if line == "" || strings.HasPrefix(line, "#") {
-			continue
+		if line == "" || line[0] == '#' {
+			return nil
+		}
+
+		// Parse !KEY and unset KEY [KEY...]
+		if keys, ok := parseUnset(line); ok {
+			for _, k := range keys {
+				fn(Entry{Key: k, File: file, Line: n, Unset: true})
+			}
+			return nil
 		}
 
 		// Parse key=value
-		parts := strings.SplitN(line, "=", 2)
-		if len(parts) != 2 {
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil
+		}
+
+		key, op := parseKey(key)
+		value = strings.TrimSpace(value)
+
+		// Remove quotes (and a comment following the closing quote) if present
+		var literal bool
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') {
+			if i, ok := closingQuote(value[1:], value[0]); ok {
+				if value[0] == '"' {
+					value = unescapeDoubleQuoted(value[1 : i+1])
+				} else {
+					value, literal = value[1:i+1], true
+				}
+			}
+		}
+
+		fn(Entry{Key: key, Value: value, File: file, Line: n, Append: op == listAppend, Prepend: op == listPrepend, Literal: literal})
+		return nil
+	})
+	var qe *UnterminatedQuoteError
+	if errors.As(err, &qe) {
+		qe.File = file
+	}
+	return err
+}
+
+// parseUnset parses a line unsetting variables (!KEY or unset KEY [KEY...]) and returns the keys.
+func parseUnset(line string) ([]string, bool) {
+	if strings.Contains(line, "=") {
+		return nil, false
+	}
+	if k, ok := strings.CutPrefix(line, "!"); ok {
+		return []string{strings.TrimSpace(k)}, true
+	}
+	fields := strings.Fields(line)
+	if len(fields) < 2 || fields[0] != "unset" {
+		return nil, false
+	}
+	return fields[1:], true
+}
+
+// UnterminatedQuoteError is the error of a quoted value not closed until the end of a .env file.
+type UnterminatedQuoteError struct {
+	// File is the path of the file, or empty if unknown.
+	File string
+	// Line is the line number where the value starts.
+	Line int
+	// Key is the key of the value.
+	Key string
+}
+
+func (e *UnterminatedQuoteError) Error() string {
+	if e.File == "" {
+		return fmt.Sprintf("line %d: unterminated quoted value of %s", e.Line, e.Key)
+	}
+	return fmt.Sprintf("%s:%d: unterminated quoted value of %s", e.File, e.Line, e.Key)
+}
+
+// scanLogicalLines reads lines of a .env file from r and calls fn for each logical line
+// with its first line number. A double-quoted value spanning multiple lines is joined
+// with "\n" into one logical line. A double-quoted value not closed until the end of r is an UnterminatedQuoteError.
+func scanLogicalLines(r io.Reader, fn func(n int, line string) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	var (
+		pending strings.Builder
+		start   int
+		// open is the quote of the value continuing on the next lines, or 0
+		open byte
+	)
+	n := 0
+	for scanner.Scan() {
+		n++
+		line := scanner.Text()
+		if open != 0 {
+			pending.WriteByte('\n')
+			pending.WriteString(line)
+			if _, ok := closingQuote(line, open); ok {
+				open = 0
+				if err := fn(start, pending.String()); err != nil {
+					return err
+				}
+			}
 			continue
 		}
+		if q, ok := opensMultiline(line); ok {
+			pending.Reset()
+			pending.WriteString(line)
+			start, open = n, q
+			continue
+		}
+		if err := fn(n, line); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if open != 0 {
+		key, _, _ := strings.Cut(strings.TrimSpace(pending.String()), "=")
+		key, _ = parseKey(key)
+		return &UnterminatedQuoteError{Line: start, Key: key}
+	}
+	return nil
+}
 
-		key := strings.TrimSpace(parts[0])
-		value := strings.TrimSpace(parts[1])
+// opensMultiline returns the quote of the line starting a quoted value not closed on the line.
+func opensMultiline(line string) (byte, bool) {
+	line = strings.TrimSpace(line)
+	if line == "" || line[0] == '#' {
+		return 0, false
+	}
+	_, value, ok := strings.Cut(line, "=")
+	if !ok {
+		return 0, false
+	}
+	value = strings.TrimSpace(value)
+	if value == "" || (value[0] != '"' && value[0] != '\'') {
+		return 0, false
+	}
+	_, closed := closingQuote(value[1:], value[0])
+	return value[0], !closed
+}
 
-		// Remove quotes if present
-		if len(value) >= 2 {
-			if (value[0] == '"' && value[len(value)-1] == '"') ||
-				(value[0] == '\'' && value[len(value)-1] == '\'') {
-				value = value[1 : len(value)-1]
+// closingQuote returns the index in s (a quoted value after its opening quote q) of the quote closing the value,
+// which is followed only by whitespace or a comment (# ...). Backslashes escape characters in double-quoted values.
+func closingQuote(s string, q byte) (int, bool) {
+	for i := 0; i < len(s); i++ {
+		switch {
+		case q == '"' && s[i] == '\\':
+			i++
+		case s[i] == q:
+			if rest := strings.TrimSpace(s[i+1:]); rest == "" || rest[0] == '#' {
+				return i, true
 			}
 		}
+	}
+	return 0, false
+}
+
+// unescapeDoubleQuoted processes escape sequences (\n, \r, \t, \" and \\) in a double-quoted value.
+// Other backslashes are kept as they are.
+func unescapeDoubleQuoted(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			b.WriteByte(s[i])
+			continue
+		}
+		switch s[i+1] {
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		case '"', '\\':
+			b.WriteByte(s[i+1])
+		default:
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+	}
+	return b.String()
+}
 
-		envs[key] = value
+// sizeHint estimates the number of definitions in files from their sizes.
+func sizeHint(files []string) int {
+	var size int64
+	for _, f := range files {
+		if fi, err := os.Stat(f); err == nil {
+			size += fi.Size()
+		}
 	}
+	return int(size / avgLineSize)
+}
 
-	return scanner.Err()
+// ParentDirs returns the parent directories of dir, nearest first, up to the git repository root
+// containing dir or the filesystem root.
+func ParentDirs(dir string) []string {
+	var dirs []string
+	for {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dirs
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return dirs
+		}
+		dirs = append(dirs, parent)
+		dir = parent
+	}
 }
@@ -0,0 +1,184 @@
+package env
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LoadEnvrc evaluates a direnv .envrc file in a limited way and returns the exported variables.
+// Supported statements are `export KEY=value` (and plain `KEY=value`) assignments,
+// `dotenv [path]` and `dotenv_if_exists [path]`. Other statements are not evaluated
+// and are returned as warnings.
+func LoadEnvrc(path string) (map[string]string, []string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	envs := make(map[string]string)
+	var warnings []string
+	lookup := func(key string) string {
+		if v, ok := envs[key]; ok {
+			return v
+		}
+		return os.Getenv(key)
+	}
+
+	scanner := bufio.NewScanner(file)
+	n := 0
+	for scanner.Scan() {
+		n++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		words, err := splitShellWords(line, lookup)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s:%d: %v", path, n, err))
+			continue
+		}
+		if len(words) == 0 {
+			continue
+		}
+		switch words[0] {
+		case "export":
+			for _, w := range words[1:] {
+				k, v, ok := strings.Cut(w, "=")
+				if !ok {
+					// `export KEY` exports an existing variable
+					if v, ok := os.LookupEnv(k); ok {
+						envs[k] = v
+					}
+					continue
+				}
+				envs[k] = v
+			}
+		case "dotenv", "dotenv_if_exists":
+			p := ".env"
+			if len(words) > 1 {
+				p = words[1]
+			}
+			if !filepath.IsAbs(p) {
+				p = filepath.Join(filepath.Dir(path), p)
+			}
+			if _, err := os.Stat(p); err != nil {
+				if words[0] == "dotenv_if_exists" && os.IsNotExist(err) {
+					continue
+				}
+				return nil, nil, fmt.Errorf("%s:%d: %w", path, n, err)
+			}
+			if err := loadEnvFile(p, envs); err != nil {
+				return nil, nil, fmt.Errorf("failed to load %s: %w", p, err)
+			}
+		default:
+			if k, v, ok := strings.Cut(words[0], "="); ok && len(words) == 1 && k != "" {
+				envs[k] = v
+				continue
+			}
+			warnings = append(warnings, fmt.Sprintf("%s:%d: unsupported statement: %s", path, n, line))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	return envs, warnings, nil
+}
+
+// splitShellWords splits a line into words like a POSIX shell does,
+// handling quotes, backslash escapes, comments and $VAR / ${VAR} expansion.
+func splitShellWords(line string, lookup func(string) string) ([]string, error) {
+	var (
+		words   []string
+		word    strings.Builder
+		inWord  bool
+		inQuote rune
+	)
+	rs := []rune(line)
+	for i := 0; i < len(rs); i++ {
+		r := rs[i]
+		switch {
+		case inQuote == '\'':
+			if r == '\'' {
+				inQuote = 0
+				continue
+			}
+			word.WriteRune(r)
+		case r == '\\' && i+1 < len(rs):
+			i++
+			if inQuote == '"' && !strings.ContainsRune(`$"\`+"`", rs[i]) {
+				word.WriteRune(r)
+			}
+			word.WriteRune(rs[i])
+			inWord = true
+		case r == '$':
+			name, l := shellVarName(rs[i+1:])
+			if l == 0 {
+				word.WriteRune(r)
+				inWord = true
+				continue
+			}
+			word.WriteString(lookup(name))
+			i += l
+			inWord = true
+		case inQuote == '"':
+			if r == '"' {
+				inQuote = 0
+				continue
+			}
+			word.WriteRune(r)
+		case r == '\'' || r == '"':
+			inQuote = r
+			inWord = true
+		case r == '`':
+			return nil, errors.New("command substitution is not supported")
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		case r == '#' && !inWord:
+			i = len(rs)
+		case r == ';' || r == '|' || r == '&' || r == '(' || r == ')':
+			return nil, fmt.Errorf("unsupported shell syntax: %q", r)
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if inQuote != 0 {
+		return nil, errors.New("unterminated quote")
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// shellVarName returns the variable name at the beginning of rs and the number of runes consumed.
+func shellVarName(rs []rune) (string, int) {
+	if len(rs) == 0 {
+		return "", 0
+	}
+	if rs[0] == '{' {
+		for i := 1; i < len(rs); i++ {
+			if rs[i] == '}' {
+				return string(rs[1:i]), i + 1
+			}
+		}
+		return "", 0
+	}
+	if rs[0] == '(' {
+		return "", 0
+	}
+	i := 0
+	for i < len(rs) && (rs[i] == '_' || (rs[i] >= 'A' && rs[i] <= 'Z') || (rs[i] >= 'a' && rs[i] <= 'z') || (i > 0 && rs[i] >= '0' && rs[i] <= '9')) {
+		i++
+	}
+	return string(rs[:i]), i
+}
@@ -0,0 +1,46 @@
+package env
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadEnvrc(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("ENVRC_PARENT", "parent")
+	createTestFile(t, dir, ".env", "FROM_DOTENV=dotenv\n")
+	createTestFile(t, dir, ".envrc", `# direnv config
+export A=a
+export B="hello $A" C='single $A'
+export D=${ENVRC_PARENT}/bin # comment
+E=plain
+dotenv
+dotenv_if_exists .env.missing
+use nix
+export F=$(date)
+`)
+
+	got, warnings, err := LoadEnvrc(filepath.Join(dir, ".envrc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"A":           "a",
+		"B":           "hello a",
+		"C":           "single $A",
+		"D":           "parent/bin",
+		"E":           "plain",
+		"FROM_DOTENV": "dotenv",
+	}
+	if len(got) != len(want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("key %q: want %q, got %q", k, v, got[k])
+		}
+	}
+	if len(warnings) != 2 {
+		t.Errorf("want 2 warnings, got %v", warnings)
+	}
+}
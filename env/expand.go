@@ -0,0 +1,213 @@
+package env
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// CycleError is returned by Expand when variables reference each other in a cycle.
+type CycleError struct {
+	// Path is the cycle of references. The first variable is referenced again by the last one.
+	Path []Var
+}
+
+// Error returns the cycle like "A (/app/.env:1) -> B (/app/.env.dev:3) -> A".
+func (e *CycleError) Error() string {
+	refs := make([]string, 0, len(e.Path)+1)
+	for _, v := range e.Path {
+		refs = append(refs, fmt.Sprintf("%s (%s:%d)", v.Key, v.File, v.Line))
+	}
+	refs = append(refs, e.Path[0].Key)
+	return "reference cycle: " + strings.Join(refs, " -> ")
+}
+
+// Expand expands $VAR and ${VAR} in values of vars with the expanded values of the other vars,
+// resolving nested references across files in dependency order. References to variables
+// not in vars, and to the variable itself (e.g. PATH=$PATH:/opt/bin), are expanded with the environment
+// of the process. Undefined variables are expanded to empty strings.
+// The parameter expansions ${VAR:-default}, ${VAR:?message} and ${VAR:+alt} are supported as described in ExpandString.
+// Literal vars (single-quoted values) are kept as they are, and are referenced by the others as they are.
+// It returns a *CycleError if variables reference each other in a cycle.
+func Expand(vars []Var) ([]Var, error) {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	index := make(map[string]int, len(vars))
+	for i, v := range vars {
+		index[v.Key] = i
+	}
+	expanded := slices.Clone(vars)
+	state := make([]int, len(vars))
+	var stack []int
+
+	var visit func(i int) error
+	visit = func(i int) error {
+		switch state[i] {
+		case done:
+			return nil
+		case visiting:
+			start := slices.Index(stack, i)
+			path := make([]Var, 0, len(stack)-start)
+			for _, j := range stack[start:] {
+				path = append(path, vars[j])
+			}
+			return &CycleError{Path: path}
+		}
+		if vars[i].Literal {
+			state[i] = done
+			return nil
+		}
+		state[i] = visiting
+		stack = append(stack, i)
+		var err error
+		value, expandErr := ExpandString(vars[i].Value, func(key string) (string, bool) {
+			j, ok := index[key]
+			if !ok || j == i {
+				return os.LookupEnv(key)
+			}
+			if err == nil {
+				err = visit(j)
+			}
+			return expanded[j].Value, true
+		})
+		if err != nil {
+			return err
+		}
+		if expandErr != nil {
+			return fmt.Errorf("failed to expand %s (%s:%d): %w", vars[i].Key, vars[i].File, vars[i].Line, expandErr)
+		}
+		expanded[i].Value = value
+		stack = stack[:len(stack)-1]
+		state[i] = done
+		return nil
+	}
+	for i := range vars {
+		if err := visit(i); err != nil {
+			return nil, err
+		}
+	}
+	return expanded, nil
+}
+
+// ExpandString expands $VAR and ${VAR} in s with the values given by lookup, which reports whether the variable is set.
+// Unset variables are expanded to empty strings. The POSIX parameter expansions are supported:
+//
+//   - ${VAR:-default} is default if VAR is unset or empty (${VAR-default}: if unset)
+//   - ${VAR:?message} fails with message if VAR is unset or empty (${VAR?message}: if unset)
+//   - ${VAR:+alt} is alt if VAR is set and not empty (${VAR+alt}: if set), and empty otherwise
+//
+// The words are expanded only when they are used, so they may contain references too (e.g. ${A:-${B}}).
+func ExpandString(s string, lookup func(key string) (string, bool)) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		if s[i] != '$' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			i++
+			continue
+		}
+		if s[i+1] == '{' {
+			end := closingBrace(s, i+2)
+			if end < 0 {
+				return "", fmt.Errorf("bad substitution: missing '}' in %s", s[i:])
+			}
+			v, err := expandParameter(s[i+2:end], lookup)
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(v)
+			i = end + 1
+			continue
+		}
+		n := nameLen(s[i+1:])
+		if n == 0 {
+			b.WriteByte('$')
+			i++
+			continue
+		}
+		v, _ := lookup(s[i+1 : i+1+n])
+		b.WriteString(v)
+		i += 1 + n
+	}
+	return b.String(), nil
+}
+
+// expandParameter expands the parameter expansion in ${...}.
+func expandParameter(expr string, lookup func(key string) (string, bool)) (string, error) {
+	n := nameLen(expr)
+	if n == 0 {
+		return "", fmt.Errorf("bad substitution: ${%s}", expr)
+	}
+	name, op := expr[:n], expr[n:]
+	v, ok := lookup(name)
+	if op == "" {
+		return v, nil
+	}
+	op, colon := strings.CutPrefix(op, ":")
+	if op == "" {
+		return "", fmt.Errorf("bad substitution: ${%s}", expr)
+	}
+	word := op[1:]
+	set := ok && (!colon || v != "")
+	switch op[0] {
+	case '-':
+		if set {
+			return v, nil
+		}
+		return ExpandString(word, lookup)
+	case '+':
+		if set {
+			return ExpandString(word, lookup)
+		}
+		return "", nil
+	case '?':
+		if set {
+			return v, nil
+		}
+		msg, err := ExpandString(word, lookup)
+		if err != nil {
+			return "", err
+		}
+		if msg == "" {
+			msg = "parameter not set"
+			if colon {
+				msg = "parameter null or not set"
+			}
+		}
+		return "", fmt.Errorf("%s: %s", name, msg)
+	default:
+		return "", fmt.Errorf("bad substitution: ${%s}", expr)
+	}
+}
+
+// closingBrace returns the index of the '}' closing the '{' before s[start:], skipping nested braces,
+// or -1 if it is not closed.
+func closingBrace(s string, start int) int {
+	depth := 0
+	for i := start; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		}
+	}
+	return -1
+}
+
+// nameLen returns the length of the variable name at the start of s.
+func nameLen(s string) int {
+	for i := range len(s) {
+		c := s[i]
+		if c != '_' && (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			return i
+		}
+	}
+	return len(s)
+}
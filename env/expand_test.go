@@ -0,0 +1,127 @@
+package env
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestExpand(t *testing.T) {
+	t.Setenv("ENVDO_TEST_HOME", "/home/alice")
+	t.Setenv("PORT", "1")
+	vars := []Var{
+		{Key: "DATA_DIR", Value: "${ROOT_DIR}/data", File: "/app/.env", Line: 1},
+		{Key: "DB_PATH", Value: "$DATA_DIR/db.sqlite", File: "/app/.env.dev", Line: 1},
+		{Key: "PORT", Value: "$PORT0", File: "/app/.env", Line: 2},
+		{Key: "ROOT_DIR", Value: "${ENVDO_TEST_HOME}/app", File: "/config/envdo/.env", Line: 1},
+		{Key: "SELF", Value: "${PORT}:$SELF", File: "/app/.env", Line: 3},
+		{Key: "PRICE", Value: "$5 ${DATA_DIR}", File: "/app/.env", Line: 4, Literal: true},
+	}
+	got, err := Expand(vars)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"DATA_DIR": "/home/alice/app/data",
+		"DB_PATH":  "/home/alice/app/data/db.sqlite",
+		"PORT":     "",
+		"ROOT_DIR": "/home/alice/app",
+		"SELF":     ":",
+		"PRICE":    "$5 ${DATA_DIR}",
+	}
+	for _, v := range got {
+		if v.Value != want[v.Key] {
+			t.Errorf("%s = %q, want %q", v.Key, v.Value, want[v.Key])
+		}
+	}
+	if vars[1].Value != "$DATA_DIR/db.sqlite" {
+		t.Error("vars should not be modified")
+	}
+}
+
+func TestExpandCycle(t *testing.T) {
+	vars := []Var{
+		{Key: "A", Value: "${B}", File: "/app/.env", Line: 1},
+		{Key: "B", Value: "x${C}", File: "/app/.env.dev", Line: 2},
+		{Key: "C", Value: "$A", File: "/app/.env", Line: 3},
+		{Key: "D", Value: "$A", File: "/app/.env", Line: 4},
+	}
+	_, err := Expand(vars)
+	var cycleErr *CycleError
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("got %v, want *CycleError", err)
+	}
+	want := "reference cycle: A (/app/.env:1) -> B (/app/.env.dev:2) -> C (/app/.env:3) -> A"
+	if err.Error() != want {
+		t.Errorf("got %q, want %q", err.Error(), want)
+	}
+}
+
+func TestExpandString(t *testing.T) {
+	envs := map[string]string{"SET": "value", "EMPTY": "", "DEFAULT": "fallback"}
+	lookup := func(key string) (string, bool) {
+		v, ok := envs[key]
+		return v, ok
+	}
+	tests := []struct {
+		in      string
+		want    string
+		wantErr string
+	}{
+		{"$SET/${SET}", "value/value", ""},
+		{"${UNSET:-default}", "default", ""},
+		{"${EMPTY:-default}", "default", ""},
+		{"${EMPTY-default}", "", ""},
+		{"${SET:-default}", "value", ""},
+		{"${UNSET:-${DEFAULT}/x}", "fallback/x", ""},
+		{"${SET:+alt}", "alt", ""},
+		{"${EMPTY:+alt}", "", ""},
+		{"${EMPTY+alt}", "alt", ""},
+		{"${UNSET+alt}", "", ""},
+		{"${SET:?required}", "value", ""},
+		{"${UNSET:-$}", "$", ""},
+		{"$SET$ and $", "value$ and $", ""},
+		{"${UNSET:?UNSET is required}", "", "UNSET: UNSET is required"},
+		{"${EMPTY:?}", "", "EMPTY: parameter null or not set"},
+		{"${UNSET?}", "", "UNSET: parameter not set"},
+		{"${SET:1}", "", "bad substitution: ${SET:1}"},
+		{"${SET", "", "bad substitution: missing '}' in ${SET"},
+	}
+	for _, tt := range tests {
+		got, err := ExpandString(tt.in, lookup)
+		if tt.wantErr != "" {
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("ExpandString(%q) error = %v, want %q", tt.in, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ExpandString(%q): %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ExpandString(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestExpandParameters(t *testing.T) {
+	vars := []Var{
+		{Key: "DB_HOST", Value: "${DB_HOST_OVERRIDE:-localhost}", File: "/app/.env", Line: 1},
+		{Key: "DB_URL", Value: "postgres://${DB_HOST}${DB_PORT:+:$DB_PORT}/app", File: "/app/.env", Line: 2},
+		{Key: "DB_PORT", Value: "5432", File: "/app/.env", Line: 3},
+	}
+	got, err := Expand(vars)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got[1].Value != "postgres://localhost:5432/app" {
+		t.Errorf("got %q", got[1].Value)
+	}
+
+	vars = []Var{{Key: "TOKEN", Value: "${ENVDO_TEST_UNSET_TOKEN:?set ENVDO_TEST_UNSET_TOKEN}", File: "/app/.env", Line: 4}}
+	_, err = Expand(vars)
+	want := "failed to expand TOKEN (/app/.env:4): ENVDO_TEST_UNSET_TOKEN: set ENVDO_TEST_UNSET_TOKEN"
+	if err == nil || err.Error() != want {
+		t.Errorf("got %v, want %q", err, want)
+	}
+}
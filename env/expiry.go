@@ -0,0 +1,87 @@
+package env
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// ExpiryDirective is the comment directive to annotate the expiry date of the following key.
+const ExpiryDirective = "envdo:expires"
+
+// ExpiryWarningPeriod is the period before the expiry date in which keys are reported as expiring soon.
+const ExpiryWarningPeriod = 14 * 24 * time.Hour
+
+// CheckExpiry checks the expiry annotations of keys in a .env file at now.
+//
+//	# envdo:expires 2025-09-01
+//	API_KEY=xxx
+//
+// Expired keys are reported as errors and keys expiring within ExpiryWarningPeriod as warnings.
+// Encrypted and structured (JSON, YAML, TOML and INI) files are not checked.
+func CheckExpiry(path string, now time.Time) ([]Issue, error) {
+	if IsEncrypted(path) || IsStructured(path) {
+		return nil, nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var (
+		issues  []Issue
+		expires time.Time
+		pending bool
+	)
+	if err := scanLogicalLines(file, func(n int, line string) error {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			pending = false
+			return nil
+		}
+		if strings.HasPrefix(line, "#") {
+			v, ok := strings.CutPrefix(strings.TrimSpace(strings.TrimPrefix(line, "#")), ExpiryDirective)
+			if !ok {
+				return nil
+			}
+			t, err := parseExpiry(strings.TrimSpace(v))
+			if err != nil {
+				issues = append(issues, Issue{Severity: SeverityWarning, File: path, Line: n, Message: fmt.Sprintf("invalid expiry date: %s", strings.TrimSpace(v))})
+				pending = false
+				return nil
+			}
+			expires, pending = t, true
+			return nil
+		}
+		if !pending {
+			return nil
+		}
+		pending = false
+		key, _, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil
+		}
+		key, _ = parseKey(key)
+		date := expires.Format(time.DateOnly)
+		switch {
+		case !now.Before(expires):
+			issues = append(issues, Issue{Severity: SeverityError, File: path, Line: n, Key: key, Message: fmt.Sprintf("key %s expired on %s", key, date)})
+		case expires.Sub(now) <= ExpiryWarningPeriod:
+			issues = append(issues, Issue{Severity: SeverityWarning, File: path, Line: n, Key: key, Message: fmt.Sprintf("key %s expires on %s", key, date)})
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return issues, nil
+}
+
+// parseExpiry parses an expiry date in YYYY-MM-DD (the beginning of the day in local time) or RFC 3339.
+func parseExpiry(v string) (time.Time, error) {
+	if t, err := time.ParseInLocation(time.DateOnly, v, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, v)
+}
@@ -0,0 +1,42 @@
+package env
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCheckExpiry(t *testing.T) {
+	dir := t.TempDir()
+	createTestFile(t, dir, ".env", `# envdo:expires 2025-01-01
+EXPIRED=1
+# envdo:expires 2025-06-10
+SOON=1
+# envdo:expires 2026-01-01
+LATER=1
+# envdo:expires 2025-01-01
+
+UNANNOTATED=1
+# envdo:expires tomorrow
+INVALID=1
+`)
+	path := filepath.Join(dir, ".env")
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local)
+	issues, err := CheckExpiry(path, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Issue{
+		{Severity: SeverityError, File: path, Line: 2, Key: "EXPIRED", Message: "key EXPIRED expired on 2025-01-01"},
+		{Severity: SeverityWarning, File: path, Line: 4, Key: "SOON", Message: "key SOON expires on 2025-06-10"},
+		{Severity: SeverityWarning, File: path, Line: 10, Message: "invalid expiry date: tomorrow"},
+	}
+	if len(issues) != len(want) {
+		t.Fatalf("got %+v", issues)
+	}
+	for i := range want {
+		if issues[i] != want[i] {
+			t.Errorf("got %+v, want %+v", issues[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package env
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// UpdateFile sets envs in the .env file at path.
+// Existing definitions are replaced in place and other lines (comments, blank lines,
+// unrelated keys) are kept as they are. New keys are appended in sorted order.
+// The file is created with 0600 permissions if it does not exist.
+// The file is locked with LockFile while it is updated, and written atomically.
+func UpdateFile(path string, envs map[string]string) error {
+	unlock, err := LockFile(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	lines, err := readLines(path)
+	if err != nil {
+		return err
+	}
+	return writeLines(path, setLines(lines, envs))
+}
+
+// ModifyFile updates the .env file at path with the variables returned by fn like UpdateFile,
+// holding the lock of LockFile while it reads the file, calls fn and writes the file,
+// so concurrent read-modify-write invocations never lose updates nor act on stale values.
+// fn is called with the variables defined in the file (empty if it does not exist),
+// and the file is not written if fn returns no variables.
+func ModifyFile(path string, fn func(current map[string]string) (map[string]string, error)) error {
+	unlock, err := LockFile(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	lines, err := readLines(path)
+	if err != nil {
+		return err
+	}
+	current, err := Parse(strings.NewReader(strings.Join(lines, "\n")))
+	if err != nil {
+		return err
+	}
+	envs, err := fn(current)
+	if err != nil || len(envs) == 0 {
+		return err
+	}
+	return writeLines(path, setLines(lines, envs))
+}
+
+// setLines returns lines of a .env file with envs set. Existing definitions are replaced in place
+// and new keys are appended in sorted order.
+func setLines(lines []string, envs map[string]string) []string {
+	updated := make(map[string]bool)
+	for i, line := range lines {
+		key, ok := lineKey(line)
+		if !ok {
+			continue
+		}
+		value, ok := envs[key]
+		if !ok {
+			continue
+		}
+		lines[i] = formatLine(key, value)
+		updated[key] = true
+	}
+
+	var keys []string
+	for key := range envs {
+		if !updated[key] {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		lines = append(lines, formatLine(key, envs[key]))
+	}
+	return lines
+}
+
+// DeleteKeys removes the definitions of keys (including KEY+=value lines) from the .env file at path,
+// together with the directive comments annotating them (e.g. # envdo:expires). Other lines are kept as they are.
+// It returns the deleted keys in sorted order. The file is locked with LockFile while it is updated.
+func DeleteKeys(path string, keys []string) ([]string, error) {
+	unlock, err := LockFile(path)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	lines, err := readLines(path)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		kept    []string
+		deleted []string
+	)
+	for _, line := range lines {
+		key, ok := lineKey(line)
+		if key, _ = parseKey(key); !ok || !slices.Contains(keys, key) {
+			kept = append(kept, line)
+			continue
+		}
+		for len(kept) > 0 && isDirectiveComment(kept[len(kept)-1]) {
+			kept = kept[:len(kept)-1]
+		}
+		if !slices.Contains(deleted, key) {
+			deleted = append(deleted, key)
+		}
+	}
+	slices.Sort(deleted)
+	if len(deleted) == 0 {
+		return nil, nil
+	}
+	return deleted, writeLines(path, kept)
+}
+
+// RenameKey renames the key from to to in the .env file at path, keeping the values and the other lines.
+// It returns an error if from is not defined or to is already defined in the file.
+// The file is locked with LockFile while it is updated.
+func RenameKey(path, from, to string) error {
+	unlock, err := LockFile(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	lines, err := readLines(path)
+	if err != nil {
+		return err
+	}
+	renamed := false
+	for i, line := range lines {
+		key, ok := lineKey(line)
+		if !ok {
+			continue
+		}
+		switch key, _ = parseKey(key); key {
+		case to:
+			return fmt.Errorf("%s is already defined in %s", to, path)
+		case from:
+			// The key is at the end of the part before "=", after the export keyword if any
+			eq := strings.Index(line, "=")
+			j := strings.LastIndex(line[:eq], from)
+			lines[i] = line[:j] + to + line[j+len(from):]
+			renamed = true
+		}
+	}
+	if !renamed {
+		return fmt.Errorf("%s is not defined in %s", from, path)
+	}
+	return writeLines(path, lines)
+}
+
+// readLines reads the logical lines of the .env file at path. A missing file has no lines.
+func readLines(path string) ([]string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	var lines []string
+	if err := scanLogicalLines(bytes.NewReader(b), func(_ int, line string) error {
+		lines = append(lines, line)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+// writeLines writes lines to the .env file at path atomically.
+func writeLines(path string, lines []string) error {
+	if len(lines) == 0 {
+		return writeFileAtomic(path, nil)
+	}
+	return writeFileAtomic(path, []byte(strings.Join(lines, "\n")+"\n"))
+}
+
+// isDirectiveComment reports whether the line is a comment of an envdo directive annotating the following key.
+func isDirectiveComment(line string) bool {
+	c, ok := strings.CutPrefix(strings.TrimSpace(line), "#")
+	return ok && strings.HasPrefix(strings.TrimSpace(c), "envdo:")
+}
+
+// Marshal formats envs in .env format sorted by key.
+func Marshal(envs map[string]string) ([]byte, error) {
+	return MarshalKeys(envs, OrderedKeys(envs, nil))
+}
+
+// MarshalKeys formats the variables of keys in envs in .env format in the order of keys.
+func MarshalKeys(envs map[string]string, keys []string) ([]byte, error) {
+	var b strings.Builder
+	for _, k := range keys {
+		b.WriteString(formatLine(k, envs[k]))
+		b.WriteByte('\n')
+	}
+	return []byte(b.String()), nil
+}
+
+// lineKey returns the key defined by a line of a .env file.
+func lineKey(line string) (string, bool) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return "", false
+	}
+	parts := strings.SplitN(line, "=", 2)
+	if len(parts) != 2 {
+		return "", false
+	}
+	return trimExport(strings.TrimSpace(parts[0])), true
+}
+
+// singleQuotedLine returns the line defining key with value single-quoted, if it is loaded as value.
+// Single-quoted values have no escapes, so values with carriage returns or quotes followed by a comment
+// cannot be single-quoted.
+func singleQuotedLine(key, value string) (string, bool) {
+	line := key + "='" + value + "'"
+	var entries []Entry
+	err := scanEntries(strings.NewReader(line), "", func(e Entry) {
+		entries = append(entries, e)
+	})
+	if err != nil || len(entries) != 1 || !entries[0].Literal || entries[0].Value != value {
+		return "", false
+	}
+	return line, true
+}
+
+// doubleQuoteEscaper escapes a value in double quotes.
+var doubleQuoteEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`)
+
+// formatLine formats a key and value as a line of a .env file.
+// Values containing "$" or "`" are single-quoted, so they are loaded as they are without expanding
+// variables or running command substitutions. Other values with special characters are double-quoted
+// and escaped, so multi-line values fit on one line.
+func formatLine(key, value string) string {
+	if strings.ContainsAny(value, "$`") {
+		if line, ok := singleQuotedLine(key, value); ok {
+			return line
+		}
+	}
+	if strings.ContainsAny(value, " \t\"'#\\\r\n") {
+		value = `"` + doubleQuoteEscaper.Replace(value) + `"`
+	}
+	return key + "=" + value
+}
@@ -0,0 +1,229 @@
+package env
+
+import (
+	"bytes"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestUpdateFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		envs     map[string]string
+		want     string
+		wantEnvs map[string]string
+	}{
+		{
+			name:    "create new file",
+			content: "",
+			envs: map[string]string{
+				"B": "b",
+				"A": "a",
+			},
+			want: "A=a\nB=b\n",
+		},
+		{
+			name:    "replace in place and keep comments",
+			content: "# comment\nA=old\n\nC=c\n",
+			envs: map[string]string{
+				"A": "new",
+				"B": "b",
+			},
+			want: "# comment\nA=new\n\nC=c\nB=b\n",
+		},
+		{
+			name:    "replace export lines",
+			content: "export A=old\n",
+			envs: map[string]string{
+				"A": "new",
+			},
+			want: "A=new\n",
+		},
+		{
+			name:    "quote values",
+			content: "",
+			envs: map[string]string{
+				"SPACE": "hello world",
+				"QUOTE": `"quoted"`,
+			},
+			want: "QUOTE=\"\\\"quoted\\\"\"\nSPACE=\"hello world\"\n",
+			wantEnvs: map[string]string{
+				"SPACE": "hello world",
+				"QUOTE": `"quoted"`,
+			},
+		},
+		{
+			name:    "replace multi-line value",
+			content: "A=\"line1\nline2\"\nB=b\n",
+			envs: map[string]string{
+				"A": "new",
+			},
+			want: "A=new\nB=b\n",
+		},
+		{
+			name:    "escape multi-line value",
+			content: "",
+			envs: map[string]string{
+				"CERT": "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----",
+				"PATH": `C:\new dir`,
+			},
+			want: "CERT=\"-----BEGIN CERTIFICATE-----\\nMIIB\\n-----END CERTIFICATE-----\"\nPATH=\"C:\\\\new dir\"\n",
+			wantEnvs: map[string]string{
+				"CERT": "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----",
+				"PATH": `C:\new dir`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "envdo", ".env")
+			if tt.content != "" {
+				if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(tt.content), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := UpdateFile(path, tt.envs); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			b, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := string(b); got != tt.want {
+				t.Errorf("want %q, got %q", tt.want, got)
+			}
+			if tt.wantEnvs == nil {
+				return
+			}
+			got := make(map[string]string)
+			if err := loadEnvFile(path, got); err != nil {
+				t.Fatal(err)
+			}
+			for key, want := range tt.wantEnvs {
+				if got[key] != want {
+					t.Errorf("key %q: want %q, got %q", key, want, got[key])
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateFileRoundTrip(t *testing.T) {
+	t.Setenv("ENVDO_TEST_HOME", "/home/alice")
+	envs := map[string]string{
+		"DOLLAR":    "a$ENVDO_TEST_HOME",
+		"BRACE":     "${ENVDO_TEST_HOME}/app",
+		"COMMAND":   "$(id)",
+		"BACKTICK":  "`id`",
+		"QUOTE":     "it's $5",
+		"HASH":      "a #b",
+		"NEWLINE":   "line1\nline2",
+		"MULTILINE": "$HOME\n`id`",
+		"PLUS":      "+81",
+	}
+	dir := t.TempDir()
+	if err := UpdateFile(filepath.Join(dir, ".env"), envs); err != nil {
+		t.Fatal(err)
+	}
+	r, err := Load(WithDirs(dir), WithExpansion())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := r.Map(); !maps.Equal(got, envs) {
+		t.Errorf("got %q, want %q", got, envs)
+	}
+	// Command substitutions are written as literals, which are not run
+	if v, ok := r.Lookup("COMMAND"); !ok || !v.Literal {
+		t.Errorf("got %+v, want a literal", v)
+	}
+}
+
+func TestMarshalAndParse(t *testing.T) {
+	envs := map[string]string{
+		"B":     "b",
+		"A":     "a",
+		"SPACE": "hello world",
+		"EMPTY": "",
+		"PHONE": "+81",
+	}
+	b, err := Marshal(envs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "A=a\nB=b\nEMPTY=\nPHONE=+81\nSPACE=\"hello world\"\n"; string(b) != want {
+		t.Errorf("want %q, got %q", want, b)
+	}
+	got, err := Parse(bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(envs) {
+		t.Fatalf("want %v, got %v", envs, got)
+	}
+	for k, v := range envs {
+		if got[k] != v {
+			t.Errorf("key %q: want %q, got %q", k, v, got[k])
+		}
+	}
+}
+
+func TestDeleteKeys(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	path := filepath.Join(t.TempDir(), ".env")
+	content := `# database
+# envdo:expires 2030-01-01
+DB_PASSWORD=secret
+PORT=8080
+PATH+=/opt/bin
+export PATH=/bin
+`
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	deleted, err := DeleteKeys(path, []string{"PATH", "DB_PASSWORD", "MISSING"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"DB_PASSWORD", "PATH"}; !slices.Equal(deleted, want) {
+		t.Errorf("deleted %v, want %v", deleted, want)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(b), "# database\nPORT=8080\n"; got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+}
+
+func TestRenameKey(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	path := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(path, []byte("# comment\nexport port=8080 # port\nport+=8081\nHOST=localhost\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := RenameKey(path, "port", "PORT"); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(b), "# comment\nexport PORT=8080 # port\nPORT+=8081\nHOST=localhost\n"; got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+	if err := RenameKey(path, "PORT", "HOST"); err == nil {
+		t.Error("want error for an existing key")
+	}
+	if err := RenameKey(path, "MISSING", "NEW"); err == nil {
+		t.Error("want error for a missing key")
+	}
+}
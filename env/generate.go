@@ -0,0 +1,67 @@
+package env
+
+import (
+	"crypto/rand"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Prefixes of the directives to generate values per invocation.
+const (
+	UUIDPrefix      = "uuid:"
+	TimestampPrefix = "timestamp:"
+)
+
+// timestampFormats are the formats of the timestamp directive.
+var timestampFormats = map[string]func(time.Time) string{
+	"":            func(t time.Time) string { return t.Format(time.RFC3339) },
+	"rfc3339":     func(t time.Time) string { return t.Format(time.RFC3339) },
+	"rfc3339nano": func(t time.Time) string { return t.Format(time.RFC3339Nano) },
+	"date":        func(t time.Time) string { return t.Format(time.DateOnly) },
+	"unix":        func(t time.Time) string { return strconv.FormatInt(t.Unix(), 10) },
+	"unixmilli":   func(t time.Time) string { return strconv.FormatInt(t.UnixMilli(), 10) },
+}
+
+// GenerateValues replaces values of the uuid and timestamp directives in envs
+// with values generated at now (in UTC). All timestamps share the same time.
+// Only the values of vars, the variables of local .env files, are replaced, and single-quoted values
+// (RUN_ID='uuid:') are kept as they are.
+//
+//	RUN_ID=uuid:
+//	BUILD_TIME=timestamp:rfc3339
+func GenerateValues(envs map[string]string, vars []Var, now time.Time) error {
+	for _, v := range vars {
+		k := v.Key
+		if v.Literal || IsRemote(v.File) || envs[k] != v.Value {
+			continue
+		}
+		switch {
+		case v.Value == UUIDPrefix:
+			u, err := uuid()
+			if err != nil {
+				return err
+			}
+			envs[k] = u
+		case strings.HasPrefix(v.Value, TimestampPrefix):
+			f, ok := timestampFormats[strings.ToLower(strings.TrimPrefix(v.Value, TimestampPrefix))]
+			if !ok {
+				return fmt.Errorf("invalid timestamp directive of %s: %s (format must be rfc3339, rfc3339nano, date, unix or unixmilli, or single-quote the value to keep it)", k, v.Value)
+			}
+			envs[k] = f(now.UTC())
+		}
+	}
+	return nil
+}
+
+// uuid returns a random (version 4) UUID.
+func uuid() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
@@ -0,0 +1,52 @@
+package env
+
+import (
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestGenerateValues(t *testing.T) {
+	envs := map[string]string{
+		"RUN_ID":     "uuid:",
+		"BUILD_TIME": "timestamp:rfc3339",
+		"BUILD_DATE": "timestamp:date",
+		"BUILD_UNIX": "timestamp:unix",
+		"DEFAULT":    "timestamp:",
+		"PLAIN":      "uuid:foo",
+	}
+	vars := []Var{{Key: "LITERAL", Value: "timestamp:not-a-directive", Literal: true}, {Key: "REMOTE", Value: "uuid:", File: "https://example.com/.env"}}
+	for k, v := range envs {
+		vars = append(vars, Var{Key: k, Value: v, File: ".env"})
+	}
+	// Single-quoted values, values of remote files and values overridden by other sources are kept
+	envs["LITERAL"] = "timestamp:not-a-directive"
+	envs["REMOTE"] = "uuid:"
+	envs["STDIN"] = "uuid:"
+	now := time.Date(2025, 6, 1, 12, 34, 56, 0, time.FixedZone("JST", 9*60*60))
+	if err := GenerateValues(envs, vars, now); err != nil {
+		t.Fatal(err)
+	}
+	if !regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(envs["RUN_ID"]) {
+		t.Errorf("invalid uuid: %s", envs["RUN_ID"])
+	}
+	want := map[string]string{
+		"BUILD_TIME": "2025-06-01T03:34:56Z",
+		"BUILD_DATE": "2025-06-01",
+		"BUILD_UNIX": "1748748896",
+		"DEFAULT":    "2025-06-01T03:34:56Z",
+		"PLAIN":      "uuid:foo",
+		"LITERAL":    "timestamp:not-a-directive",
+		"REMOTE":     "uuid:",
+		"STDIN":      "uuid:",
+	}
+	for k, v := range want {
+		if envs[k] != v {
+			t.Errorf("%s: got %q, want %q", k, envs[k], v)
+		}
+	}
+
+	if err := GenerateValues(map[string]string{"T": "timestamp:unknown"}, []Var{{Key: "T", Value: "timestamp:unknown", File: ".env"}}, now); err == nil {
+		t.Error("want error")
+	}
+}
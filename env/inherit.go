@@ -0,0 +1,87 @@
+package env
+
+import (
+	"bufio"
+	"os"
+	"strings"
+)
+
+// InheritDirective is the directive to inherit base profiles, written at the top of a .env file.
+//
+//	#!inherit base
+//
+// Without arguments (or with .env), the file inherits the default .env file.
+const InheritDirective = "#!inherit"
+
+// inheritedProfiles returns the base profiles declared by the #!inherit directives in files.
+// Encrypted files are skipped.
+func inheritedProfiles(files []string) []string {
+	var bases []string
+	for _, f := range files {
+		if IsEncrypted(f) {
+			continue
+		}
+		bs, err := readInherit(f)
+		if err != nil {
+			continue
+		}
+		bases = append(bases, bs...)
+	}
+	return bases
+}
+
+// readInherit reads the #!inherit directives in the leading comment lines of the .env file at path.
+func readInherit(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var bases []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "#") {
+			break
+		}
+		args, ok := strings.CutPrefix(line, InheritDirective)
+		if !ok || (args != "" && args[0] != ' ' && args[0] != '\t') {
+			continue
+		}
+		fields := strings.Fields(args)
+		if len(fields) == 0 {
+			fields = []string{""}
+		}
+		for _, f := range fields {
+			bases = append(bases, BaseProfile(f))
+		}
+	}
+	return bases, scanner.Err()
+}
+
+// parentProfiles returns the parents of a hierarchical profile in dot notation, lower priority first:
+// the profiles of the leading parts ("prod" for "prod.eu", and "prod" and "prod.eu" for "prod.eu.west").
+// A profile without dots has no parents. Like other profiles, the default profile is layered
+// only by #!inherit or --base, in the profile or its parents.
+func parentProfiles(profile string) []string {
+	var parents []string
+	for i, c := range profile {
+		if c == '.' {
+			parents = append(parents, profile[:i])
+		}
+	}
+	return parents
+}
+
+// BaseProfile returns the profile name of a base given to #!inherit or --base.
+// ".env" means the default profile.
+func BaseProfile(name string) string {
+	if name == Filename("") {
+		return ""
+	}
+	return name
+}
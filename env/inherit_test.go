@@ -0,0 +1,126 @@
+package env
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestInherit(t *testing.T) {
+	pwd := t.TempDir()
+	configDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(configDir, "envdo"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	createTestFile(t, pwd, ".env", "A=default\nB=default\nC=default\n")
+	createTestFile(t, pwd, ".env.base", "#!inherit\nB=base\nD=base\n")
+	createTestFile(t, pwd, ".env.production", "# production\n#!inherit base\nC=production\n")
+	createTestFile(t, filepath.Join(configDir, "envdo"), ".env.production", "D=config\n")
+	createTestFile(t, pwd, ".env.loop", "#!inherit loop\nA=loop\n")
+
+	e := New(pwd, configDir)
+	got := e.Files("production")
+	want := []string{
+		filepath.Join(pwd, ".env.production"),
+		filepath.Join(configDir, "envdo", ".env.production"),
+		filepath.Join(pwd, ".env.base"),
+		filepath.Join(pwd, ".env"),
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	envs, err := e.LoadEnvFiles("production")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantEnvs := map[string]string{"A": "default", "B": "base", "C": "production", "D": "config"}
+	for k, v := range wantEnvs {
+		if envs[k] != v {
+			t.Errorf("%s: got %q, want %q", k, envs[k], v)
+		}
+	}
+
+	// Cyclic inheritance is ignored
+	if got := e.Files("loop"); len(got) != 1 {
+		t.Errorf("got %v", got)
+	}
+
+	// Base set by SetBase
+	e.SetBase(".env")
+	envs, err = e.LoadEnvFiles("loop")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if envs["A"] != "loop" || envs["B"] != "default" {
+		t.Errorf("got %v", envs)
+	}
+
+	// The profile itself must exist
+	if _, err := e.LoadEnvFiles("missing"); err == nil {
+		t.Error("want error")
+	}
+}
+
+func TestHierarchicalProfiles(t *testing.T) {
+	pwd := t.TempDir()
+	createTestFile(t, pwd, ".env", "A=default\nB=default\nC=default\n")
+	createTestFile(t, pwd, ".env.prod", "#!inherit\nB=prod\nC=prod\n")
+	createTestFile(t, pwd, ".env.prod.eu", "C=prod.eu\n")
+	createTestFile(t, pwd, ".env.prod.eu.west", "#!inherit\nD=west\n")
+
+	e := New(pwd, "")
+	got := e.Files("prod.eu.west")
+	want := []string{
+		filepath.Join(pwd, ".env.prod.eu.west"),
+		filepath.Join(pwd, ".env.prod.eu"),
+		filepath.Join(pwd, ".env.prod"),
+		filepath.Join(pwd, ".env"),
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	envs, err := e.LoadEnvFiles("prod.eu")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantEnvs := map[string]string{"A": "default", "B": "prod", "C": "prod.eu"}
+	if len(envs) != len(wantEnvs) {
+		t.Errorf("got %v, want %v", envs, wantEnvs)
+	}
+	for k, v := range wantEnvs {
+		if envs[k] != v {
+			t.Errorf("%s: got %q, want %q", k, envs[k], v)
+		}
+	}
+	if got := parentProfiles("prod"); got != nil {
+		t.Errorf("got %v, want no parents", got)
+	}
+}
+
+func TestHierarchicalProfilesDefault(t *testing.T) {
+	pwd := t.TempDir()
+	createTestFile(t, pwd, ".env", "A=default\n")
+	createTestFile(t, pwd, ".env.production", "B=production\n")
+	createTestFile(t, pwd, ".env.production.local", "C=local\n")
+
+	// The default .env file is layered only by #!inherit or --base, like profiles without dots
+	e := New(pwd, "")
+	for _, p := range []string{"production", "production.local"} {
+		if slices.Contains(e.Files(p), filepath.Join(pwd, ".env")) {
+			t.Errorf("%s: got %v, want no default .env file", p, e.Files(p))
+		}
+	}
+	got := e.Files("production.local")
+	want := []string{filepath.Join(pwd, ".env.production.local"), filepath.Join(pwd, ".env.production")}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	e.SetBase(".env")
+	for _, p := range []string{"production", "production.local"} {
+		if files := e.Files(p); files[len(files)-1] != filepath.Join(pwd, ".env") {
+			t.Errorf("%s: got %v, want the default .env file with --base", p, files)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package env
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// scanINI parses definitions of environment variables in INI from r and calls fn for each definition in order.
+// Keys in sections are prefixed with the section names and upper-cased ([database] url= → DATABASE_URL).
+// Keys and values are separated by "=" or ":", lines starting with ";" or "#" are comments,
+// and quotes around values are removed.
+func scanINI(r io.Reader, file string, fn func(Entry)) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	var section string
+	n := 0
+	for scanner.Scan() {
+		n++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == ';' || line[0] == '#' {
+			continue
+		}
+		if line[0] == '[' {
+			name, ok := strings.CutSuffix(line, "]")
+			if !ok {
+				return fmt.Errorf("line %d: invalid section %s", n, line)
+			}
+			section = strings.TrimSpace(name[1:])
+			continue
+		}
+		i := strings.IndexAny(line, "=:")
+		if i < 0 {
+			return fmt.Errorf("line %d: invalid line without '=' or ':'", n)
+		}
+		value := strings.TrimSpace(line[i+1:])
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		fn(Entry{Key: sectionKey(section, strings.TrimSpace(line[:i])), Value: value, File: file, Line: n})
+	}
+	return scanner.Err()
+}
+
+// sectionKey returns the key of an environment variable for the key path of TOML or INI,
+// joining the non-empty parts with "_" in upper case and replacing "-", "." and spaces with "_".
+func sectionKey(parts ...string) string {
+	var nonEmpty []string
+	for _, p := range parts {
+		if p != "" {
+			nonEmpty = append(nonEmpty, p)
+		}
+	}
+	return strings.NewReplacer("-", "_", ".", "_", " ", "_").Replace(strings.ToUpper(strings.Join(nonEmpty, "_")))
+}
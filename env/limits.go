@@ -0,0 +1,119 @@
+package env
+
+import (
+	"cmp"
+	"fmt"
+	"runtime"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultMaxValueSize is the size of values in bytes over which CheckValues reports warnings by default.
+const DefaultMaxValueSize = 32 * 1024
+
+// pointerSize is the size of a pointer to each argument and variable counted against the limit of execve.
+const pointerSize = 8
+
+// Limits are the limits of the arguments and environment of commands started by the OS.
+type Limits struct {
+	// Total is the limit of the total size of the arguments and environment in bytes (ARG_MAX), or 0 if none.
+	Total int
+	// Variable is the limit of the size of a KEY=value string in bytes, or 0 if none.
+	Variable int
+}
+
+// OSLimits returns the approximate limits of the OS goos.
+// Linux limits each string to 128 KiB (MAX_ARG_STRLEN) and the total to a quarter of the stack size (2 MiB by default),
+// macOS limits the total to 1 MiB and Windows limits each variable to 32767 characters.
+func OSLimits(goos string) Limits {
+	switch goos {
+	case "linux", "android":
+		return Limits{Total: 2 * 1024 * 1024, Variable: 128 * 1024}
+	case "darwin", "ios":
+		return Limits{Total: 1024 * 1024}
+	case "windows":
+		return Limits{Variable: 32767}
+	default:
+		return Limits{Total: 256 * 1024}
+	}
+}
+
+// CheckValues checks the values of envs for sizes over maxValueSize bytes and binary or control characters.
+// maxValueSize 0 means DefaultMaxValueSize and a negative maxValueSize disables the size check.
+// NUL bytes, which cannot be passed to commands, are errors and the others are warnings.
+func CheckValues(envs map[string]string, maxValueSize int) []Issue {
+	if maxValueSize == 0 {
+		maxValueSize = DefaultMaxValueSize
+	}
+	var issues []Issue
+	for _, k := range OrderedKeys(envs, nil) {
+		v := envs[k]
+		if maxValueSize > 0 && len(v) > maxValueSize {
+			issues = append(issues, Issue{Severity: SeverityWarning, Key: k, Message: fmt.Sprintf("value of %s is %d bytes, larger than %d bytes", k, len(v), maxValueSize)})
+		}
+		switch {
+		case strings.ContainsRune(v, 0):
+			issues = append(issues, Issue{Severity: SeverityError, Key: k, Message: fmt.Sprintf("value of %s contains a NUL byte, which cannot be passed to commands", k)})
+		case !utf8.ValidString(v):
+			issues = append(issues, Issue{Severity: SeverityWarning, Key: k, Message: fmt.Sprintf("value of %s contains binary data (invalid UTF-8)", k)})
+		default:
+			if i := strings.IndexFunc(v, isControl); i >= 0 {
+				r, _ := utf8.DecodeRuneInString(v[i:])
+				issues = append(issues, Issue{Severity: SeverityWarning, Key: k, Message: fmt.Sprintf("value of %s contains the control character %U", k, r)})
+			}
+		}
+	}
+	return issues
+}
+
+// isControl reports whether r is a control character other than tab and line breaks.
+func isControl(r rune) bool {
+	return unicode.IsControl(r) && r != '\t' && r != '\n' && r != '\r'
+}
+
+// CheckEnviron checks the arguments args and the environment environ ("KEY=value") of a command
+// against the limits of the OS running envdo, over which starting the command fails with E2BIG
+// (argument list too long). Exceeding the limits is an error and using more than 3/4 of the total is a warning.
+func CheckEnviron(environ, args []string) []Issue {
+	return checkEnviron(environ, args, OSLimits(runtime.GOOS))
+}
+
+func checkEnviron(environ, args []string, limits Limits) []Issue {
+	var issues []Issue
+	total := 0
+	for _, a := range args {
+		total += len(a) + 1 + pointerSize
+	}
+	for _, kv := range environ {
+		total += len(kv) + 1 + pointerSize
+		if limits.Variable > 0 && len(kv) >= limits.Variable {
+			k, _, _ := strings.Cut(kv, "=")
+			issues = append(issues, Issue{Severity: SeverityError, Key: k, Message: fmt.Sprintf("%s is %d bytes, over the limit of a variable of %d bytes", k, len(kv), limits.Variable)})
+		}
+	}
+	if limits.Total <= 0 || total <= limits.Total*3/4 {
+		return issues
+	}
+	msg := fmt.Sprintf("the arguments and environment of the command are %d bytes, over the limit of %d bytes", total, limits.Total)
+	severity := SeverityError
+	if total <= limits.Total {
+		msg = fmt.Sprintf("the arguments and environment of the command are %d bytes, close to the limit of %d bytes", total, limits.Total)
+		severity = SeverityWarning
+	}
+	return append(issues, Issue{Severity: severity, Message: msg + " (largest: " + largestVariables(environ, 3) + ")"})
+}
+
+// largestVariables returns the keys and sizes of the n largest variables in environ.
+func largestVariables(environ []string, n int) string {
+	environ = slices.SortedStableFunc(slices.Values(environ), func(a, b string) int {
+		return cmp.Compare(len(b), len(a))
+	})
+	var s []string
+	for _, kv := range environ[:min(n, len(environ))] {
+		k, _, _ := strings.Cut(kv, "=")
+		s = append(s, fmt.Sprintf("%s %d bytes", k, len(kv)))
+	}
+	return strings.Join(s, ", ")
+}
@@ -0,0 +1,62 @@
+package env
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCheckValues(t *testing.T) {
+	envs := map[string]string{
+		"BIG":     strings.Repeat("a", 11),
+		"BINARY":  "\xff\xfe",
+		"CONTROL": "a\x1b[31m",
+		"NUL":     "a\x00b",
+		"OK":      "a\n\tb\r\n",
+	}
+	var got []string
+	for _, i := range CheckValues(envs, 10) {
+		got = append(got, i.Severity+" "+i.Message)
+	}
+	want := []string{
+		"warning value of BIG is 11 bytes, larger than 10 bytes",
+		"warning value of BINARY contains binary data (invalid UTF-8)",
+		"warning value of CONTROL contains the control character U+001B",
+		"error value of NUL contains a NUL byte, which cannot be passed to commands",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if issues := CheckValues(map[string]string{"BIG": strings.Repeat("a", DefaultMaxValueSize+1)}, -1); len(issues) != 0 {
+		t.Errorf("got %v with the size check disabled", issues)
+	}
+	if issues := CheckValues(map[string]string{"BIG": strings.Repeat("a", DefaultMaxValueSize+1)}, 0); len(issues) != 1 {
+		t.Errorf("got %v with the default size", issues)
+	}
+}
+
+func TestCheckEnviron(t *testing.T) {
+	environ := []string{"A=1", "B=" + strings.Repeat("b", 140)}
+	tests := []struct {
+		name   string
+		limits Limits
+		want   []string
+	}{
+		{"within the limits", Limits{Total: 1000, Variable: 1000}, nil},
+		{"no limits", Limits{}, nil},
+		{"large variable", Limits{Variable: 142}, []string{"error B is 142 bytes, over the limit of a variable of 142 bytes"}},
+		{"close to the total", Limits{Total: 200}, []string{"warning the arguments and environment of the command are 175 bytes, close to the limit of 200 bytes (largest: B 142 bytes, A 3 bytes)"}},
+		{"over the total", Limits{Total: 170}, []string{"error the arguments and environment of the command are 175 bytes, over the limit of 170 bytes (largest: B 142 bytes, A 3 bytes)"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, i := range checkEnviron(environ, []string{"cmd"}, tt.limits) {
+				got = append(got, i.Severity+" "+i.Message)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Severities of issues.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+var keyRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Issue represents a problem found in a .env file.
+type Issue struct {
+	Severity string `json:"severity"`
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Key      string `json:"key,omitempty"`
+	Message  string `json:"message"`
+}
+
+// Lint checks the syntax and key names of a .env file.
+// Encrypted files are not checked.
+func Lint(path string) ([]Issue, error) {
+	if IsEncrypted(path) {
+		return nil, nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	if IsStructured(path) {
+		return lintStructured(file, path), nil
+	}
+
+	var issues []Issue
+	defined := make(map[string]int)
+	if err := scanLogicalLines(file, func(n int, line string) error {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			return nil
+		}
+		if keys, ok := parseUnset(line); ok {
+			for _, k := range keys {
+				issues = append(issues, lintKey(path, n, k)...)
+			}
+			return nil
+		}
+		key, _, ok := strings.Cut(line, "=")
+		if !ok {
+			issues = append(issues, Issue{Severity: SeverityWarning, File: path, Line: n, Message: "invalid line without '=' is ignored"})
+			return nil
+		}
+		key, op := parseKey(key)
+		issues = append(issues, lintKey(path, n, key)...)
+		// KEY+=value and KEY^=value lines extend the value defined before
+		if op != listSet {
+			return nil
+		}
+		if prev, ok := defined[key]; ok {
+			issues = append(issues, Issue{Severity: SeverityWarning, File: path, Line: n, Key: key, Message: fmt.Sprintf("duplicate key %s (previously defined at line %d)", key, prev)})
+		}
+		defined[key] = n
+		return nil
+	}); err != nil {
+		var qe *UnterminatedQuoteError
+		if !errors.As(err, &qe) {
+			return nil, err
+		}
+		issues = append(issues, Issue{Severity: SeverityError, File: path, Line: qe.Line, Key: qe.Key, Message: fmt.Sprintf("unterminated quoted value of %s", qe.Key)})
+	}
+	return issues, nil
+}
+
+// lintStructured checks the syntax and flattened key names of a JSON, YAML, TOML or INI env file.
+func lintStructured(r io.Reader, path string) []Issue {
+	var issues []Issue
+	if err := scanStructured(r, path, func(e Entry) {
+		issues = append(issues, lintKey(path, e.Line, e.Key)...)
+	}); err != nil {
+		return []Issue{{Severity: SeverityError, File: path, Message: fmt.Sprintf("invalid syntax: %v", err)}}
+	}
+	return issues
+}
+
+// lintKey checks a key name.
+func lintKey(path string, line int, key string) []Issue {
+	switch {
+	case key == "":
+		return []Issue{{Severity: SeverityError, File: path, Line: line, Message: "empty key"}}
+	case key[0] >= '0' && key[0] <= '9':
+		return []Issue{{Severity: SeverityError, File: path, Line: line, Key: key, Message: fmt.Sprintf("key %s starts with a digit", key)}}
+	case !keyRe.MatchString(key):
+		return []Issue{{Severity: SeverityError, File: path, Line: line, Key: key, Message: fmt.Sprintf("key %s contains invalid characters", key)}}
+	case strings.ToUpper(key) != key:
+		return []Issue{{Severity: SeverityWarning, File: path, Line: line, Key: key, Message: fmt.Sprintf("key %s contains lowercase letters", key)}}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package env
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	dir := t.TempDir()
+	createTestFile(t, dir, ".env", `# comment
+VALID=1
+INVALID_LINE
+=empty
+1KEY=digit
+BAD-KEY=dash
+lower=case
+VALID=2
+!AWS_PROFILE
+unset HOME bad-key
+`)
+	path := filepath.Join(dir, ".env")
+	got, err := Lint(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Issue{
+		{Severity: SeverityWarning, File: path, Line: 3},
+		{Severity: SeverityError, File: path, Line: 4},
+		{Severity: SeverityError, File: path, Line: 5, Key: "1KEY"},
+		{Severity: SeverityError, File: path, Line: 6, Key: "BAD-KEY"},
+		{Severity: SeverityWarning, File: path, Line: 7, Key: "lower"},
+		{Severity: SeverityWarning, File: path, Line: 8, Key: "VALID"},
+		{Severity: SeverityError, File: path, Line: 10, Key: "bad-key"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("want %d issues, got %d: %v", len(want), len(got), got)
+	}
+	for i := range want {
+		if got[i].Severity != want[i].Severity || got[i].Line != want[i].Line || got[i].Key != want[i].Key {
+			t.Errorf("issue %d: want %+v, got %+v", i, want[i], got[i])
+		}
+	}
+}
+
+func TestLintMultiline(t *testing.T) {
+	dir := t.TempDir()
+	createTestFile(t, dir, ".env", "CERT=\"line1\nline2\"\nBROKEN=\"unterminated\nNEXT=1\n")
+	path := filepath.Join(dir, ".env")
+	issues, err := Lint(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 1 {
+		t.Fatalf("got %+v", issues)
+	}
+	if issues[0].Severity != SeverityError || issues[0].Key != "BROKEN" || issues[0].Line != 3 {
+		t.Errorf("got %+v", issues[0])
+	}
+}
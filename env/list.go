@@ -0,0 +1,84 @@
+package env
+
+import (
+	"os"
+	"strings"
+	"unicode"
+)
+
+// ListSeparator is the separator of list values extended by KEY+=value (append) and KEY^=value (prepend) lines,
+// the path list separator of the OS (":", or ";" on Windows).
+const ListSeparator = string(os.PathListSeparator)
+
+// listMerger merges definitions in load order (lower priority first), extending list values
+// appended or prepended to the value defined before, or to the value in the environment
+// of the process if the key is not defined or unset before.
+type listMerger struct {
+	values map[string]string
+	unset  map[string]bool
+}
+
+func newListMerger() *listMerger {
+	return &listMerger{values: make(map[string]string), unset: make(map[string]bool)}
+}
+
+// merge merges the definition and returns the value of the key.
+func (m *listMerger) merge(en Entry) string {
+	if en.Unset {
+		delete(m.values, en.Key)
+		m.unset[en.Key] = true
+		return ""
+	}
+	value := en.Value
+	if en.Append || en.Prepend {
+		base, ok := m.values[en.Key]
+		if !ok && !m.unset[en.Key] {
+			base = os.Getenv(en.Key)
+		}
+		switch {
+		case base == "":
+		case value == "":
+			value = base
+		case en.Append:
+			value = base + ListSeparator + value
+		default:
+			value = value + ListSeparator + base
+		}
+	}
+	m.values[en.Key] = value
+	return value
+}
+
+// listOp is the operation of a KEY=value line on the value defined before.
+type listOp int
+
+const (
+	// listSet replaces the value (KEY=value).
+	listSet listOp = iota
+	// listAppend appends the value to the list value (KEY+=value).
+	listAppend
+	// listPrepend prepends the value to the list value (KEY^=value).
+	listPrepend
+)
+
+// parseKey parses the key of a KEY=value line, returning the key without the "+" of KEY+=value
+// or the "^" of KEY^=value and the operation on the list value. The export keyword of export KEY=value lines
+// (written for sourcing the file in a shell) is stripped.
+func parseKey(s string) (string, listOp) {
+	s = trimExport(strings.TrimSpace(s))
+	if k, ok := strings.CutSuffix(s, "+"); ok {
+		return strings.TrimSpace(k), listAppend
+	}
+	if k, ok := strings.CutSuffix(s, "^"); ok {
+		return strings.TrimSpace(k), listPrepend
+	}
+	return s, listSet
+}
+
+// trimExport strips the export keyword of the key of an export KEY=value line.
+func trimExport(key string) string {
+	if k, ok := strings.CutPrefix(key, "export"); ok && k != "" && unicode.IsSpace(rune(k[0])) {
+		return strings.TrimSpace(k)
+	}
+	return key
+}
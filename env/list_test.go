@@ -0,0 +1,72 @@
+package env
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadFilesListValues(t *testing.T) {
+	t.Setenv("PATH", "/usr/bin")
+	t.Setenv("LIST_EMPTY", "")
+	t.Setenv("TZ", "UTC")
+	dir := t.TempDir()
+	createTestFile(t, dir, "base.env", "GOFLAGS=-mod=mod\nGOPATH+=/go\n")
+	createTestFile(t, dir, "dev.env", strings.Join([]string{
+		"PATH+=/opt/tool/bin",
+		"PATH^=/opt/first/bin",
+		"GOFLAGS+=-race",
+		"LIST_EMPTY+=/a",
+		"unset GOPATH",
+		"GOPATH^=/b",
+		`QUOTED="+81"`,
+		// Values starting with "+" are literal
+		"PHONE=+81-90-1234",
+		"TZ=+09:00",
+		"",
+	}, "\n"))
+	e := New("", "")
+	envs, err := e.LoadFiles([]string{filepath.Join(dir, "base.env"), filepath.Join(dir, "dev.env")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"PATH":       strings.Join([]string{"/opt/first/bin", "/usr/bin", "/opt/tool/bin"}, ListSeparator),
+		"GOFLAGS":    "-mod=mod" + ListSeparator + "-race",
+		"LIST_EMPTY": "/a",
+		"GOPATH":     "/b",
+		"QUOTED":     "+81",
+		"PHONE":      "+81-90-1234",
+		"TZ":         "+09:00",
+	}
+	for k, v := range want {
+		if envs[k] != v {
+			t.Errorf("%s = %q, want %q", k, envs[k], v)
+		}
+	}
+}
+
+func TestResolveVarsListValues(t *testing.T) {
+	t.Setenv("PATH", "/usr/bin")
+	entries := []Entry{
+		{Key: "PATH", Value: "/opt/dev/bin", Prepend: true, File: ".env.dev", Line: 1},
+		{Key: "PATH", Value: "/opt/base/bin", Append: true, File: ".env", Line: 1},
+	}
+	vars := ResolveVars(entries)
+	want := strings.Join([]string{"/opt/dev/bin", "/usr/bin", "/opt/base/bin"}, ListSeparator)
+	if len(vars) != 1 || vars[0].Value != want || vars[0].File != ".env.dev" {
+		t.Errorf("got %v, want PATH=%s", vars, want)
+	}
+}
+
+func TestLintListValues(t *testing.T) {
+	dir := t.TempDir()
+	createTestFile(t, dir, ".env", "PATH=/bin\nPATH+=/opt/bin\nPATH^=/usr/local/bin\n")
+	issues, err := Lint(filepath.Join(dir, ".env"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 0 {
+		t.Errorf("got %v, want no issues", issues)
+	}
+}
@@ -0,0 +1,275 @@
+package env
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/k1LoW/envdo/env/provider"
+)
+
+// Option is an option of Load.
+type Option func(*loadOptions)
+
+type loadOptions struct {
+	ctx       context.Context
+	profile   string
+	dirs      []string
+	resolvers []provider.Resolver
+	expand    bool
+	walkUp    bool
+	decryptor Decryptor
+}
+
+// Var is a resolved environment variable with its origin.
+type Var struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	File  string `json:"file"`
+	Line  int    `json:"line"`
+	// Overrides are the lower-priority definitions of the key overridden by this one, in priority order.
+	Overrides []Location `json:"overrides,omitempty"`
+	// Literal is true if the value is not expanded (single-quoted).
+	Literal bool `json:"literal,omitempty"`
+}
+
+// Location is a position of a definition in a .env file.
+type Location struct {
+	File string `json:"file"`
+	Line int    `json:"line"`
+}
+
+// Result is the result of Load.
+type Result struct {
+	// Vars are the resolved environment variables sorted by key.
+	Vars []Var
+	// Unset are the keys unset by !KEY or unset KEY lines, which should be removed
+	// from the environment inherited by commands.
+	Unset []string
+}
+
+// WithProfile sets the profile to load. The default .env files are loaded by default.
+func WithProfile(profile string) Option {
+	return func(o *loadOptions) {
+		o.profile = profile
+	}
+}
+
+// WithDirs sets the directories to search for .env files in priority order.
+// By default, the current directory and $XDG_CONFIG_HOME/envdo are searched.
+func WithDirs(dirs ...string) Option {
+	return func(o *loadOptions) {
+		o.dirs = dirs
+	}
+}
+
+// WithWalkUp makes the parent directories of the current directory searched too,
+// up to the git repository root. Nearer directories take priority.
+func WithWalkUp() Option {
+	return func(o *loadOptions) {
+		o.walkUp = true
+	}
+}
+
+// WithProviders sets the resolvers of secret references (e.g. provider.NewOnePassword()).
+// By default, references are not resolved.
+func WithProviders(resolvers ...provider.Resolver) Option {
+	return func(o *loadOptions) {
+		o.resolvers = resolvers
+	}
+}
+
+// WithExpansion enables expansion of $VAR and ${VAR} in values with the loaded
+// environment variables, falling back to the environment of the process.
+// Nested references across files are resolved, and reference cycles are reported as *CycleError.
+// Values are expanded before secret references are resolved, so resolved secrets and single-quoted values are kept as they are.
+func WithExpansion() Option {
+	return func(o *loadOptions) {
+		o.expand = true
+	}
+}
+
+// WithDecryptor sets the decryptor of encrypted .env files.
+// By default, they are decrypted with age identities.
+func WithDecryptor(d Decryptor) Option {
+	return func(o *loadOptions) {
+		o.decryptor = d
+	}
+}
+
+// WithContext sets the context used to resolve secret references.
+func WithContext(ctx context.Context) Option {
+	return func(o *loadOptions) {
+		o.ctx = ctx
+	}
+}
+
+// Load loads environment variables the same way as envdo: .env files are merged by priority,
+// including base profiles inherited with #!inherit, and the uuid and timestamp directives are generated.
+// Unlike envdo, values of random directives are not generated because it modifies files.
+func Load(opts ...Option) (*Result, error) {
+	o := &loadOptions{ctx: context.Background()}
+	for _, opt := range opts {
+		opt(o)
+	}
+	e := Default()
+	if o.dirs != nil {
+		e = &Env{dirs: o.dirs, decryptor: e.decryptor, remote: e.remote, vault: e.vault, logger: e.logger}
+	}
+	if o.decryptor != nil {
+		e.SetDecryptor(o.decryptor)
+	}
+	e.SetWalkUp(o.walkUp)
+	if o.profile != "" && len(e.profileFiles(o.profile)) == 0 {
+		return nil, fmt.Errorf("environment file %s not found in any search directory", Filename(o.profile))
+	}
+	entries, err := e.Entries(o.profile)
+	if err != nil {
+		return nil, err
+	}
+	r := &Result{Vars: ResolveVars(entries), Unset: UnsetKeys(entries)}
+
+	envs := r.Map()
+	if err := GenerateValues(envs, r.Vars, time.Now()); err != nil {
+		return nil, err
+	}
+	for i := range r.Vars {
+		r.Vars[i].Value = envs[r.Vars[i].Key]
+	}
+	if o.expand {
+		r.Vars, err = Expand(r.Vars)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(o.resolvers) > 0 {
+		envs = r.Map()
+		if err := provider.NewRegistry(o.resolvers...).Resolve(o.ctx, envs); err != nil {
+			return nil, err
+		}
+		for i := range r.Vars {
+			r.Vars[i].Value = envs[r.Vars[i].Key]
+		}
+	}
+	return r, nil
+}
+
+// ResolveVars resolves definitions in priority order into variables sorted by key.
+// Keys whose first entries are unset lines (!KEY or unset KEY) are omitted,
+// and list values of KEY+=value and KEY^=value lines are extended with the lower-priority definitions.
+func ResolveVars(entries []Entry) []Var {
+	values := make(map[string]string)
+	m := newListMerger()
+	for _, e := range slices.Backward(entries) {
+		values[e.Key] = m.merge(e)
+	}
+	var vars []Var
+	index := make(map[string]int)
+	unset := make(map[string]bool)
+	for _, e := range entries {
+		if i, ok := index[e.Key]; ok {
+			vars[i].Overrides = append(vars[i].Overrides, Location{File: e.File, Line: e.Line})
+			continue
+		}
+		if unset[e.Key] {
+			continue
+		}
+		if e.Unset {
+			unset[e.Key] = true
+			continue
+		}
+		index[e.Key] = len(vars)
+		vars = append(vars, Var{Key: e.Key, Value: values[e.Key], File: e.File, Line: e.Line, Literal: e.Literal})
+	}
+	slices.SortFunc(vars, func(a, b Var) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+	return vars
+}
+
+// DefinitionOrder returns the keys defined in entries given in priority order, in the order of their first
+// definitions when loading (lower-priority files first, from top to bottom). A key overridden by higher-priority
+// files keeps its position, like a key updated in an ordered map.
+func DefinitionOrder(entries []Entry) []string {
+	var keys []string
+	seen := make(map[string]bool)
+	for _, e := range slices.Backward(entries) {
+		if seen[e.Key] {
+			continue
+		}
+		seen[e.Key] = true
+		keys = append(keys, e.Key)
+	}
+	return keys
+}
+
+// OrderedKeys returns the keys of envs in the order of order, followed by the keys not in order sorted.
+// With an empty order, all keys are sorted.
+func OrderedKeys(envs map[string]string, order []string) []string {
+	keys := make([]string, 0, len(envs))
+	seen := make(map[string]bool, len(envs))
+	for _, k := range order {
+		if _, ok := envs[k]; ok && !seen[k] {
+			keys = append(keys, k)
+			seen[k] = true
+		}
+	}
+	var rest []string
+	for k := range envs {
+		if !seen[k] {
+			rest = append(rest, k)
+		}
+	}
+	slices.Sort(rest)
+	return append(keys, rest...)
+}
+
+// UnsetKeys returns the keys unset by !KEY or unset KEY lines in entries given in priority order,
+// that is, the keys whose first entries are unset lines, sorted.
+func UnsetKeys(entries []Entry) []string {
+	var keys []string
+	seen := make(map[string]bool)
+	for _, e := range entries {
+		if seen[e.Key] {
+			continue
+		}
+		seen[e.Key] = true
+		if e.Unset {
+			keys = append(keys, e.Key)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// Map returns the environment variables as a map.
+func (r *Result) Map() map[string]string {
+	envs := make(map[string]string, len(r.Vars))
+	for _, v := range r.Vars {
+		envs[v.Key] = v.Value
+	}
+	return envs
+}
+
+// Lookup returns the variable of key.
+func (r *Result) Lookup(key string) (Var, bool) {
+	i, ok := slices.BinarySearchFunc(r.Vars, key, func(v Var, k string) int {
+		return strings.Compare(v.Key, k)
+	})
+	if !ok {
+		return Var{}, false
+	}
+	return r.Vars[i], true
+}
+
+// Environ returns the environment variables in the form "KEY=value" sorted by key,
+// to be appended to exec.Cmd.Env.
+func (r *Result) Environ() []string {
+	environ := make([]string, 0, len(r.Vars))
+	for _, v := range r.Vars {
+		environ = append(environ, v.Key+"="+v.Value)
+	}
+	return environ
+}
@@ -0,0 +1,139 @@
+package env
+
+import (
+	"context"
+	"maps"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+type staticResolver map[string]string
+
+func (s staticResolver) Scheme() string {
+	return "static"
+}
+
+func (s staticResolver) Resolve(_ context.Context, ref string) (string, error) {
+	return s[ref], nil
+}
+
+func TestLoad(t *testing.T) {
+	pwd := t.TempDir()
+	global := t.TempDir()
+	createTestFile(t, global, ".env.dev", "HOST=global\nPORT=5432\n")
+	createTestFile(t, pwd, ".env.dev", "HOST=localhost\nPASSWORD=static://db\nURL=postgres://${HOST}:${PORT}/app\n")
+
+	r, err := Load(WithProfile("dev"), WithDirs(pwd, global), WithProviders(staticResolver{"static://db": "secret"}), WithExpansion())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Var{
+		{Key: "HOST", Value: "localhost", File: filepath.Join(pwd, ".env.dev"), Line: 1, Overrides: []Location{{File: filepath.Join(global, ".env.dev"), Line: 1}}},
+		{Key: "PASSWORD", Value: "secret", File: filepath.Join(pwd, ".env.dev"), Line: 2},
+		{Key: "PORT", Value: "5432", File: filepath.Join(global, ".env.dev"), Line: 2},
+		{Key: "URL", Value: "postgres://localhost:5432/app", File: filepath.Join(pwd, ".env.dev"), Line: 3},
+	}
+	if len(r.Vars) != len(want) {
+		t.Fatalf("got %v, want %v", r.Vars, want)
+	}
+	for i := range want {
+		got := r.Vars[i]
+		if got.Key != want[i].Key || got.Value != want[i].Value || got.File != want[i].File || got.Line != want[i].Line || !slices.Equal(got.Overrides, want[i].Overrides) {
+			t.Errorf("Vars[%d] = %+v, want %+v", i, got, want[i])
+		}
+	}
+	if v, ok := r.Lookup("PORT"); !ok || v.Value != "5432" {
+		t.Errorf("Lookup(PORT) = %+v, %v", v, ok)
+	}
+	if _, ok := r.Lookup("MISSING"); ok {
+		t.Error("Lookup(MISSING) should not be found")
+	}
+	if got := r.Environ(); len(got) != 4 || got[0] != "HOST=localhost" {
+		t.Errorf("Environ() = %v", got)
+	}
+
+	// Without options, references and variables are kept as they are
+	r, err = Load(WithProfile("dev"), WithDirs(pwd))
+	if err != nil {
+		t.Fatal(err)
+	}
+	envs := r.Map()
+	if envs["PASSWORD"] != "static://db" || envs["URL"] != "postgres://${HOST}:${PORT}/app" {
+		t.Errorf("got %v", envs)
+	}
+
+	if _, err := Load(WithProfile("missing"), WithDirs(pwd)); err == nil {
+		t.Error("expected error for missing profile")
+	}
+}
+
+func TestLoadExpansionLiterals(t *testing.T) {
+	t.Setenv("ENVDO_TEST_HOME", "/home/alice")
+	pwd := t.TempDir()
+	createTestFile(t, pwd, ".env", "PASSWORD=static://db\nCOST='cost $5'\nDIR=$ENVDO_TEST_HOME/app\nREF=$COST\n")
+
+	r, err := Load(WithDirs(pwd), WithProviders(staticResolver{"static://db": "pa$$w0rd$ENVDO_TEST_HOME"}), WithExpansion())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"PASSWORD": "pa$$w0rd$ENVDO_TEST_HOME",
+		"COST":     "cost $5",
+		"DIR":      "/home/alice/app",
+		"REF":      "cost $5",
+	}
+	if got := r.Map(); !maps.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestLoadUnset(t *testing.T) {
+	pwd := t.TempDir()
+	global := t.TempDir()
+	createTestFile(t, global, ".env", "AWS_PROFILE=global\nHOME_DIR=/home\nREGION=us\n")
+	createTestFile(t, pwd, ".env", "!AWS_PROFILE\nunset HOME_DIR REGION\nREGION=eu\n")
+
+	r, err := Load(WithDirs(pwd, global))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := r.Environ(); !slices.Equal(got, []string{"REGION=eu"}) {
+		t.Errorf("Environ() = %v", got)
+	}
+	if want := []string{"AWS_PROFILE", "HOME_DIR"}; !slices.Equal(r.Unset, want) {
+		t.Errorf("Unset = %v, want %v", r.Unset, want)
+	}
+
+	envs, err := New(pwd, filepath.Dir(global)).LoadFiles([]string{filepath.Join(global, ".env"), filepath.Join(pwd, ".env")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(envs) != 1 || envs["REGION"] != "eu" {
+		t.Errorf("got %v", envs)
+	}
+}
+
+func TestDefinitionOrder(t *testing.T) {
+	pwd := t.TempDir()
+	global := t.TempDir()
+	createTestFile(t, global, ".env", "ZONE=global\nPORT=80\nHOST=example.com\n")
+	createTestFile(t, pwd, ".env", "DEBUG=1\nPORT=8080\nAPP=web\n")
+
+	entries, err := New(pwd, filepath.Dir(global)).ReadEntries([]string{filepath.Join(pwd, ".env"), filepath.Join(global, ".env")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	order := DefinitionOrder(entries)
+	if want := []string{"ZONE", "PORT", "HOST", "DEBUG", "APP"}; !slices.Equal(order, want) {
+		t.Errorf("DefinitionOrder() = %v, want %v", order, want)
+	}
+
+	envs := map[string]string{"APP": "web", "PORT": "8080", "ZONE": "global", "EXTRA": "1", "BETA": "2"}
+	if got, want := OrderedKeys(envs, order), []string{"ZONE", "PORT", "APP", "BETA", "EXTRA"}; !slices.Equal(got, want) {
+		t.Errorf("OrderedKeys() = %v, want %v", got, want)
+	}
+	if got, want := OrderedKeys(envs, nil), []string{"APP", "BETA", "EXTRA", "PORT", "ZONE"}; !slices.Equal(got, want) {
+		t.Errorf("OrderedKeys(nil) = %v, want %v", got, want)
+	}
+}
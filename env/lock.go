@@ -0,0 +1,121 @@
+package env
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// LockFile takes an exclusive advisory lock of the file at path, waiting until other processes
+// release it, and returns the function to release the lock. Writers of .env files hold the lock
+// while reading and writing them, so concurrent invocations never lose updates.
+// The lock file is created in $XDG_CACHE_HOME/envdo/locks instead of next to path,
+// so it is never found as a .env file.
+func LockFile(path string) (func(), error) {
+	lp, err := lockPath(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(lp), 0700); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(lp, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	if err := lockFile(f); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return func() {
+		_ = unlockFile(f)
+		_ = f.Close()
+	}, nil
+}
+
+// lockPath returns the path of the lock file of the file at path, named by the hash of its absolute path.
+func lockPath(path string) (string, error) {
+	abs, err := targetPath(path)
+	if err != nil {
+		return "", err
+	}
+	cacheDir := os.Getenv("XDG_CACHE_HOME")
+	if cacheDir == "" {
+		if cacheDir, err = os.UserCacheDir(); err != nil {
+			return "", err
+		}
+	}
+	h := sha256.Sum256([]byte(abs))
+	return filepath.Join(cacheDir, "envdo", "locks", hex.EncodeToString(h[:16])+".lock"), nil
+}
+
+// targetPath returns the absolute path of the file at path, following symbolic links if it exists.
+func targetPath(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	resolved, err := filepath.EvalSymlinks(abs)
+	switch {
+	case err == nil:
+		return resolved, nil
+	case errors.Is(err, fs.ErrNotExist):
+		return abs, nil
+	default:
+		return "", err
+	}
+}
+
+// WriteFile writes b to the .env file at path atomically while holding the lock of LockFile.
+// The file is created with 0600 permissions if it does not exist.
+func WriteFile(path string, b []byte) error {
+	unlock, err := LockFile(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return writeFileAtomic(path, b)
+}
+
+// writeFileAtomic writes b to a temporary file in the directory of path and renames it to path,
+// so readers never see a partially written file. The mode of an existing file is kept,
+// and a symbolic link at path is followed, replacing the file it points to.
+func writeFileAtomic(path string, b []byte) error {
+	target, err := targetPath(path)
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(target)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	mode := fs.FileMode(0600)
+	if fi, err := os.Stat(target); err == nil {
+		mode = fi.Mode().Perm()
+	}
+	// Not named .env.* not to be found as a profile while it exists
+	f, err := os.CreateTemp(dir, ".envdo-*.tmp")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	defer func() { _ = os.Remove(tmp) }()
+	if _, err := f.Write(b); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp, mode); err != nil {
+		return err
+	}
+	return os.Rename(tmp, target)
+}
@@ -0,0 +1,137 @@
+package env
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestUpdateFileConcurrently(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	path := filepath.Join(t.TempDir(), ".env")
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := UpdateFile(path, map[string]string{fmt.Sprintf("KEY%d", i): "v"}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	envs, err := Parse(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(envs) != 20 {
+		t.Errorf("got %d keys, want 20: %v", len(envs), envs)
+	}
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("got %d files, want only .env", len(entries))
+	}
+}
+
+func TestModifyFileConcurrently(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	dir := t.TempDir()
+	createTestFile(t, dir, ".env", "# counter\nCOUNT=0\nSECRET=random:16\n")
+	path := filepath.Join(dir, ".env")
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		generated int
+	)
+	for range 20 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if err := ModifyFile(path, func(current map[string]string) (map[string]string, error) {
+				n, err := strconv.Atoi(current["COUNT"])
+				if err != nil {
+					return nil, err
+				}
+				return map[string]string{"COUNT": strconv.Itoa(n + 1)}, nil
+			}); err != nil {
+				t.Error(err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			keys, err := GenerateRandomValues(path)
+			if err != nil {
+				t.Error(err)
+			}
+			mu.Lock()
+			generated += len(keys)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	envs, err := Parse(bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if envs["COUNT"] != "20" {
+		t.Errorf("COUNT = %s, want 20", envs["COUNT"])
+	}
+	if generated != 1 || len(envs["SECRET"]) != 16 {
+		t.Errorf("SECRET = %q generated %d times, want once", envs["SECRET"], generated)
+	}
+	if !strings.HasPrefix(string(b), "# counter\n") {
+		t.Errorf("the comment was not kept: %q", b)
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symbolic links and file modes are not supported")
+	}
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	dir := t.TempDir()
+	dotfiles := t.TempDir()
+	createTestFile(t, dotfiles, ".env.dev", "A=1\n")
+	target := filepath.Join(dotfiles, ".env.dev")
+	if err := os.Chmod(target, 0640); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(dir, ".env.dev")
+	if err := os.Symlink(target, link); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFile(link, []byte("A=2\n")); err != nil {
+		t.Fatal(err)
+	}
+	if fi, err := os.Lstat(link); err != nil || fi.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("the symbolic link was replaced: %v", err)
+	}
+	b, err := os.ReadFile(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "A=2\n" {
+		t.Errorf("got %q", b)
+	}
+	if fi, err := os.Stat(target); err != nil || fi.Mode().Perm() != 0640 {
+		t.Errorf("mode = %v, want 0640 (%v)", fi.Mode().Perm(), err)
+	}
+}
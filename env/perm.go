@@ -0,0 +1,32 @@
+package env
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+)
+
+// CheckPermission checks that a .env file is not accessible by other users.
+// World-accessible files and files owned by another user are errors, and
+// group-accessible files are warnings. It always returns no issues on Windows.
+func CheckPermission(path string) ([]Issue, error) {
+	if runtime.GOOS == "windows" {
+		return nil, nil
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	var issues []Issue
+	mode := fi.Mode().Perm()
+	switch {
+	case mode&0o007 != 0:
+		issues = append(issues, Issue{Severity: SeverityError, File: path, Message: fmt.Sprintf("permissions %04o are too open: the file is accessible by other users", mode)})
+	case mode&0o070 != 0:
+		issues = append(issues, Issue{Severity: SeverityWarning, File: path, Message: fmt.Sprintf("permissions %04o are too open: the file is accessible by the group", mode)})
+	}
+	if uid, ok := fileOwner(fi); ok && uid != os.Getuid() {
+		issues = append(issues, Issue{Severity: SeverityError, File: path, Message: fmt.Sprintf("the file is owned by another user (uid %d)", uid)})
+	}
+	return issues, nil
+}
@@ -0,0 +1,43 @@
+//go:build !windows
+
+package env
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckPermission(t *testing.T) {
+	tests := []struct {
+		mode os.FileMode
+		want []string
+	}{
+		{0600, nil},
+		{0640, []string{SeverityWarning}},
+		{0644, []string{SeverityError}},
+		{0666, []string{SeverityError}},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), ".env")
+		if err := os.WriteFile(path, []byte("KEY=value\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chmod(path, tt.mode); err != nil {
+			t.Fatal(err)
+		}
+		issues, err := CheckPermission(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(issues) != len(tt.want) {
+			t.Errorf("mode %04o: want %v, got %v", tt.mode, tt.want, issues)
+			continue
+		}
+		for i, s := range tt.want {
+			if issues[i].Severity != s {
+				t.Errorf("mode %04o: want %s, got %s", tt.mode, s, issues[i].Severity)
+			}
+		}
+	}
+}
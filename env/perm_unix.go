@@ -0,0 +1,17 @@
+//go:build !windows
+
+package env
+
+import (
+	"os"
+	"syscall"
+)
+
+// fileOwner returns the uid of the owner of the file.
+func fileOwner(fi os.FileInfo) (int, bool) {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, false
+	}
+	return int(st.Uid), true
+}
@@ -0,0 +1,10 @@
+//go:build windows
+
+package env
+
+import "os"
+
+// fileOwner is not supported on Windows.
+func fileOwner(_ os.FileInfo) (int, bool) {
+	return 0, false
+}
@@ -0,0 +1,139 @@
+package env
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DefaultProfileName is the name of the profile of the default .env files.
+const DefaultProfileName = "default"
+
+// Profile is a profile available in the search directories.
+type Profile struct {
+	// Name is the name of the profile (DefaultProfileName for the default .env files).
+	Name string `json:"name"`
+	// Vars is the number of variables of the profile, or nil if some files cannot be read.
+	Vars  *int          `json:"vars"`
+	Files []ProfileFile `json:"files"`
+}
+
+// ProfileFile is a file backing a profile.
+type ProfileFile struct {
+	Path string `json:"path"`
+	// Vars is the number of variables defined in the file, or nil if it cannot be read.
+	Vars     *int      `json:"vars"`
+	Modified time.Time `json:"modified"`
+}
+
+// Profiles returns the profiles found in the search directories, sorted by name
+// with the default profile first. Files of each profile are in priority order.
+func (e *Env) Profiles() ([]Profile, error) {
+	index := make(map[string]int)
+	var (
+		profiles []Profile
+		entries  [][]Entry
+	)
+	for _, dir := range e.getSearchDirectories() {
+		des, err := os.ReadDir(dir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		var names []string
+		for _, de := range des {
+			if de.IsDir() {
+				continue
+			}
+			if _, ok := profileName(de.Name()); ok {
+				names = append(names, de.Name())
+			}
+		}
+		// Order files in the directory by priority, as Files does
+		slices.SortStableFunc(names, func(a, b string) int {
+			return filePriority(a) - filePriority(b)
+		})
+		for _, n := range names {
+			name, _ := profileName(n)
+			path := filepath.Join(dir, n)
+			fi, err := os.Stat(path)
+			if err != nil {
+				return nil, err
+			}
+			i, ok := index[name]
+			if !ok {
+				i = len(profiles)
+				index[name] = i
+				profiles = append(profiles, Profile{Name: name, Vars: ptr(0)})
+				entries = append(entries, nil)
+			}
+			f := ProfileFile{Path: path, Modified: fi.ModTime()}
+			if es, err := e.ReadEntries([]string{path}); err == nil {
+				f.Vars = ptr(len(ResolveVars(es)))
+				entries[i] = append(entries[i], es...)
+			} else {
+				// The number of variables of the profile is unknown if a file cannot be read
+				profiles[i].Vars = nil
+			}
+			profiles[i].Files = append(profiles[i].Files, f)
+		}
+	}
+	for i := range profiles {
+		if profiles[i].Vars != nil {
+			profiles[i].Vars = ptr(len(ResolveVars(entries[i])))
+		}
+	}
+	slices.SortFunc(profiles, func(a, b Profile) int {
+		switch {
+		case a.Name == b.Name:
+			return 0
+		case a.Name == DefaultProfileName:
+			return -1
+		case b.Name == DefaultProfileName:
+			return 1
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return profiles, nil
+}
+
+// profileName returns the name of the profile of the .env file named filename.
+// .env.vault files are not profiles, but loaded for the environments of their keys.
+func profileName(filename string) (string, bool) {
+	if filename == VaultFilename {
+		return "", false
+	}
+	base := strings.TrimSuffix(filename, EncryptedSuffix)
+	if IsStructured(base) {
+		base = strings.TrimSuffix(base, filepath.Ext(base))
+	}
+	if base == ".env" {
+		return DefaultProfileName, true
+	}
+	name, ok := strings.CutPrefix(base, ".env.")
+	if !ok || name == "" {
+		return "", false
+	}
+	return name, true
+}
+
+// filePriority returns the priority of the .env file named filename in a directory. Lower is higher.
+func filePriority(filename string) int {
+	p := 0
+	if IsEncrypted(filename) {
+		p += len(StructuredExtensions) + 1
+	}
+	ext := filepath.Ext(strings.TrimSuffix(filename, EncryptedSuffix))
+	if i := slices.Index(StructuredExtensions, ext); i >= 0 {
+		p += i + 1
+	}
+	return p
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,63 @@
+package env
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestProfiles(t *testing.T) {
+	pwd := t.TempDir()
+	configDir := t.TempDir()
+	global := filepath.Join(configDir, "envdo")
+	if err := os.MkdirAll(global, 0700); err != nil {
+		t.Fatal(err)
+	}
+	createTestFile(t, pwd, ".env", "A=1\nB=2\n")
+	createTestFile(t, pwd, ".env.dev.yaml", "A: 1\n")
+	createTestFile(t, pwd, ".env.dev", "A=2\nC=3\n")
+	createTestFile(t, pwd, ".envrc", "export A=1\n")
+	createTestFile(t, pwd, ".envdo.yml", "walk_up: true\n")
+	createTestFile(t, global, ".env.dev", "D=4\n")
+	createTestFile(t, global, ".env.prod.age", "not encrypted\n")
+
+	profiles, err := New(pwd, configDir).Profiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	type want struct {
+		name  string
+		vars  int
+		files []string
+	}
+	wants := []want{
+		{DefaultProfileName, 2, []string{filepath.Join(pwd, ".env")}},
+		{"dev", 3, []string{filepath.Join(pwd, ".env.dev"), filepath.Join(pwd, ".env.dev.yaml"), filepath.Join(global, ".env.dev")}},
+		{"prod", -1, []string{filepath.Join(global, ".env.prod.age")}},
+	}
+	if len(profiles) != len(wants) {
+		t.Fatalf("got %+v", profiles)
+	}
+	for i, w := range wants {
+		p := profiles[i]
+		if p.Name != w.name {
+			t.Errorf("profiles[%d].Name = %s, want %s", i, p.Name, w.name)
+		}
+		vars := -1
+		if p.Vars != nil {
+			vars = *p.Vars
+		}
+		if vars != w.vars {
+			t.Errorf("%s: vars = %d, want %d", p.Name, vars, w.vars)
+		}
+		if len(p.Files) != len(w.files) {
+			t.Errorf("%s: files = %+v, want %v", p.Name, p.Files, w.files)
+			continue
+		}
+		for j, f := range w.files {
+			if p.Files[j].Path != f {
+				t.Errorf("%s: files[%d] = %s, want %s", p.Name, j, p.Files[j].Path, f)
+			}
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/k1LoW/envdo/internal/api"
+)
+
+// Doppler resolves doppler://project/config/SECRET references with the Doppler API
+// using the service token in $DOPPLER_TOKEN.
+type Doppler struct {
+	apiURL string
+	token  string
+	client *http.Client
+
+	mu      sync.Mutex
+	secrets map[string]func() (map[string]string, error)
+}
+
+// NewDoppler creates a new Doppler resolver.
+func NewDoppler() *Doppler {
+	return &Doppler{
+		apiURL: "https://api.doppler.com",
+		token:  os.Getenv("DOPPLER_TOKEN"),
+		client: api.NewClient(),
+	}
+}
+
+// Scheme returns "doppler".
+func (d *Doppler) Scheme() string {
+	return "doppler"
+}
+
+// Resolve returns the value of the secret referenced by ref.
+func (d *Doppler) Resolve(ctx context.Context, ref string) (string, error) {
+	if d.token == "" {
+		return "", errors.New("DOPPLER_TOKEN is required to resolve Doppler references")
+	}
+	parts := strings.Split(strings.TrimPrefix(ref, "doppler://"), "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", fmt.Errorf("invalid Doppler reference (doppler://project/config/SECRET): %s", ref)
+	}
+	secrets, err := d.download(ctx, parts[0], parts[1])
+	if err != nil {
+		return "", err
+	}
+	v, ok := secrets[parts[2]]
+	if !ok {
+		return "", fmt.Errorf("%w in Doppler: %s", ErrSecretNotFound, strings.Join(parts, "/"))
+	}
+	return v, nil
+}
+
+// CacheScope returns the URL of the Doppler API and the service token.
+func (d *Doppler) CacheScope() string {
+	return d.apiURL + "\x00" + d.token
+}
+
+// download returns the secrets of the config of the project, downloading them once per config
+// even if called concurrently.
+func (d *Doppler) download(ctx context.Context, project, config string) (map[string]string, error) {
+	d.mu.Lock()
+	if d.secrets == nil {
+		d.secrets = make(map[string]func() (map[string]string, error))
+	}
+	download, ok := d.secrets[project+"/"+config]
+	if !ok {
+		download = sync.OnceValues(func() (map[string]string, error) {
+			return d.fetch(ctx, project, config)
+		})
+		d.secrets[project+"/"+config] = download
+	}
+	d.mu.Unlock()
+	return download()
+}
+
+// fetch downloads the secrets of the config of the project from Doppler.
+func (d *Doppler) fetch(ctx context.Context, project, config string) (map[string]string, error) {
+	q := url.Values{"project": {project}, "config": {config}, "format": {"json"}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.apiURL+"/v3/configs/config/secrets/download?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+d.token)
+	var secrets map[string]string
+	if err := api.DoJSON(d.client, req, &secrets); err != nil {
+		return nil, err
+	}
+	return secrets, nil
+}
@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDoppler(t *testing.T) {
+	var downloads int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer dp.st.token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/v3/configs/config/secrets/download" || r.URL.Query().Get("project") != "app" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		downloads++
+		switch r.URL.Query().Get("config") {
+		case "prd":
+			_, _ = w.Write([]byte(`{"DB_PASSWORD":"prd-pass","API_KEY":"prd-key"}`))
+		default:
+			_, _ = w.Write([]byte(`{"DB_PASSWORD":"dev-pass"}`))
+		}
+	}))
+	defer ts.Close()
+
+	d := &Doppler{apiURL: ts.URL, token: "dp.st.token", client: ts.Client()}
+	ctx := context.Background()
+	tests := []struct {
+		ref  string
+		want string
+	}{
+		{"doppler://app/prd/DB_PASSWORD", "prd-pass"},
+		{"doppler://app/prd/API_KEY", "prd-key"},
+		{"doppler://app/dev/DB_PASSWORD", "dev-pass"},
+	}
+	for _, tt := range tests {
+		got, err := d.Resolve(ctx, tt.ref)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.ref, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.ref, got, tt.want)
+		}
+	}
+	if downloads != 2 {
+		t.Errorf("downloads = %d, want 2", downloads)
+	}
+
+	if _, err := d.Resolve(ctx, "doppler://app/dev/MISSING"); !errors.Is(err, ErrSecretNotFound) {
+		t.Errorf("got %v, want ErrSecretNotFound", err)
+	}
+	for _, ref := range []string{"doppler://app/prd", "doppler://app//DB_PASSWORD", "doppler://other/prd/DB_PASSWORD"} {
+		if _, err := d.Resolve(ctx, ref); err == nil {
+			t.Errorf("%s: expected error", ref)
+		}
+	}
+}
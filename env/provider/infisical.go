@@ -0,0 +1,77 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/k1LoW/envdo/internal/api"
+)
+
+// Infisical resolves infisical://project/environment/[path/]SECRET references with the Infisical API
+// at $INFISICAL_API_URL (https://app.infisical.com/api by default) using the token in $INFISICAL_TOKEN
+// (a service token or an access token of a machine identity). The project is the ID of the project.
+type Infisical struct {
+	apiURL string
+	token  string
+	client *http.Client
+}
+
+// NewInfisical creates a new Infisical resolver.
+func NewInfisical() *Infisical {
+	apiURL := strings.TrimSuffix(os.Getenv("INFISICAL_API_URL"), "/")
+	if apiURL == "" {
+		apiURL = "https://app.infisical.com/api"
+	}
+	return &Infisical{
+		apiURL: apiURL,
+		token:  os.Getenv("INFISICAL_TOKEN"),
+		client: api.NewClient(),
+	}
+}
+
+// Scheme returns "infisical".
+func (i *Infisical) Scheme() string {
+	return "infisical"
+}
+
+// CacheScope returns the URL of the Infisical API and the token.
+func (i *Infisical) CacheScope() string {
+	return i.apiURL + "\x00" + i.token
+}
+
+// Resolve returns the value of the secret referenced by ref.
+func (i *Infisical) Resolve(ctx context.Context, ref string) (string, error) {
+	if i.token == "" {
+		return "", errors.New("INFISICAL_TOKEN is required to resolve Infisical references")
+	}
+	parts := strings.Split(strings.TrimPrefix(ref, "infisical://"), "/")
+	if len(parts) < 3 || slices.Contains(parts, "") {
+		return "", fmt.Errorf("invalid Infisical reference (infisical://project/environment/[path/]SECRET): %s", ref)
+	}
+	project, environment, name := parts[0], parts[1], parts[len(parts)-1]
+	q := url.Values{
+		"workspaceId": {project},
+		"environment": {environment},
+		"secretPath":  {"/" + strings.Join(parts[2:len(parts)-1], "/")},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, i.apiURL+"/v3/secrets/raw/"+url.PathEscape(name)+"?"+q.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+i.token)
+	var res struct {
+		Secret struct {
+			SecretValue string `json:"secretValue"`
+		} `json:"secret"`
+	}
+	if err := api.DoJSON(i.client, req, &res); err != nil {
+		return "", err
+	}
+	return res.Secret.SecretValue, nil
+}
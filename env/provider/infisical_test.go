@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestInfisical(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.Header.Get("Authorization") != "Bearer token" || q.Get("workspaceId") != "proj-1" || q.Get("environment") != "prod" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path + " " + q.Get("secretPath") {
+		case "/v3/secrets/raw/DB_PASSWORD /":
+			_, _ = w.Write([]byte(`{"secret":{"secretKey":"DB_PASSWORD","secretValue":"root-pass"}}`))
+		case "/v3/secrets/raw/DB_PASSWORD /backend/db":
+			_, _ = w.Write([]byte(`{"secret":{"secretKey":"DB_PASSWORD","secretValue":"backend-pass"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	i := &Infisical{apiURL: ts.URL, token: "token", client: ts.Client()}
+	tests := []struct {
+		ref     string
+		want    string
+		wantErr bool
+	}{
+		{"infisical://proj-1/prod/DB_PASSWORD", "root-pass", false},
+		{"infisical://proj-1/prod/backend/db/DB_PASSWORD", "backend-pass", false},
+		{"infisical://proj-1/prod/MISSING", "", true},
+		{"infisical://proj-1/dev/DB_PASSWORD", "", true},
+		{"infisical://proj-1/prod", "", true},
+		{"infisical://proj-1/prod/backend//DB_PASSWORD", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			got, err := i.Resolve(context.Background(), tt.ref)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
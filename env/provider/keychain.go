@@ -0,0 +1,65 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// KeychainService is the service name of secrets stored in the OS credential store by envdo.
+const KeychainService = "envdo"
+
+// ErrSecretNotFound is returned when a secret is not found in the OS credential store.
+var ErrSecretNotFound = errors.New("secret not found")
+
+// Keychain resolves keychain://NAME references with the OS credential store:
+// macOS Keychain (security), the Secret Service on Linux (secret-tool) or Windows Credential Manager.
+type Keychain struct {
+	service string
+	get     func(ctx context.Context, service, account string) (string, error)
+	set     func(ctx context.Context, service, account, secret string) error
+}
+
+// NewKeychain creates a new Keychain resolver.
+func NewKeychain() *Keychain {
+	return &Keychain{
+		service: KeychainService,
+		get:     keychainGet,
+		set:     keychainSet,
+	}
+}
+
+// Scheme returns "keychain".
+func (k *Keychain) Scheme() string {
+	return "keychain"
+}
+
+// Resolve returns the secret referenced by ref.
+func (k *Keychain) Resolve(ctx context.Context, ref string) (string, error) {
+	name := strings.TrimPrefix(ref, "keychain://")
+	if name == "" || strings.Contains(name, "/") {
+		return "", fmt.Errorf("invalid keychain reference: %s", ref)
+	}
+	return k.Get(ctx, name)
+}
+
+// Get returns the secret of name.
+func (k *Keychain) Get(ctx context.Context, name string) (string, error) {
+	v, err := k.get(ctx, k.service, name)
+	if err != nil {
+		if errors.Is(err, ErrSecretNotFound) {
+			return "", fmt.Errorf("%w in the OS credential store: %s", ErrSecretNotFound, name)
+		}
+		return "", err
+	}
+	return v, nil
+}
+
+// Set stores the secret of name, replacing the existing one.
+func (k *Keychain) Set(ctx context.Context, name, secret string) error {
+	if name == "" || strings.Contains(name, "/") {
+		return fmt.Errorf("invalid secret name: %q", name)
+	}
+	return k.set(ctx, k.service, name, secret)
+}
@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/k1LoW/exec"
+)
+
+// exitItemNotFound is the exit status of security when the item is not found (errSecItemNotFound).
+const exitItemNotFound = 44
+
+// keychainGet returns the generic password of service and account in macOS Keychain.
+func keychainGet(ctx context.Context, service, account string) (string, error) {
+	c := exec.CommandContext(ctx, "security", "find-generic-password", "-s", service, "-a", account, "-w")
+	var stderr bytes.Buffer
+	c.Stderr = &stderr
+	out, err := c.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == exitItemNotFound {
+			return "", ErrSecretNotFound
+		}
+		return "", fmt.Errorf("security find-generic-password: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
+
+// keychainSet stores the generic password of service and account in macOS Keychain.
+// The command is passed to security -i via stdin so that the secret does not appear in the process list.
+func keychainSet(ctx context.Context, service, account, secret string) error {
+	c := exec.CommandContext(ctx, "security", "-i")
+	c.Stdin = strings.NewReader(fmt.Sprintf("add-generic-password -U -s %s -a %s -X %s\n",
+		quoteSecurityArg(service), quoteSecurityArg(account), hex.EncodeToString([]byte(secret))))
+	var stderr bytes.Buffer
+	c.Stderr = &stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("security add-generic-password: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	if msg := strings.TrimSpace(stderr.String()); msg != "" {
+		return fmt.Errorf("security add-generic-password: %s", msg)
+	}
+	return nil
+}
+
+// quoteSecurityArg quotes s as an argument of a command of security -i.
+func quoteSecurityArg(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
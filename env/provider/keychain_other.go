@@ -0,0 +1,54 @@
+//go:build !darwin && !windows
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/k1LoW/exec"
+)
+
+// keychainGet returns the secret of service and account in the Secret Service with secret-tool.
+func keychainGet(ctx context.Context, service, account string) (string, error) {
+	if err := lookSecretTool(); err != nil {
+		return "", err
+	}
+	c := exec.CommandContext(ctx, "secret-tool", "lookup", "service", service, "account", account)
+	var stderr bytes.Buffer
+	c.Stderr = &stderr
+	out, err := c.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && stderr.Len() == 0 {
+			return "", ErrSecretNotFound
+		}
+		return "", fmt.Errorf("secret-tool lookup: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return string(out), nil
+}
+
+// keychainSet stores the secret of service and account in the Secret Service with secret-tool.
+func keychainSet(ctx context.Context, service, account, secret string) error {
+	if err := lookSecretTool(); err != nil {
+		return err
+	}
+	c := exec.CommandContext(ctx, "secret-tool", "store", "--label", service+": "+account, "service", service, "account", account)
+	c.Stdin = strings.NewReader(secret)
+	var stderr bytes.Buffer
+	c.Stderr = &stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("secret-tool store: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+func lookSecretTool() error {
+	if _, err := exec.LookPath("secret-tool"); err != nil {
+		return errors.New("secret-tool is not found: install libsecret-tools (or libsecret) to use the Secret Service")
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func newFakeKeychain() *Keychain {
+	store := map[string]string{}
+	return &Keychain{
+		service: KeychainService,
+		get: func(_ context.Context, service, account string) (string, error) {
+			v, ok := store[service+":"+account]
+			if !ok {
+				return "", ErrSecretNotFound
+			}
+			return v, nil
+		},
+		set: func(_ context.Context, service, account, secret string) error {
+			store[service+":"+account] = secret
+			return nil
+		},
+	}
+}
+
+func TestKeychain(t *testing.T) {
+	ctx := context.Background()
+	k := newFakeKeychain()
+	if err := k.Set(ctx, "DB_PASSWORD", "s3cr3t"); err != nil {
+		t.Fatal(err)
+	}
+	v, err := k.Resolve(ctx, "keychain://DB_PASSWORD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != "s3cr3t" {
+		t.Errorf("got %q, want %q", v, "s3cr3t")
+	}
+
+	if _, err := k.Resolve(ctx, "keychain://MISSING"); !errors.Is(err, ErrSecretNotFound) {
+		t.Errorf("got %v, want ErrSecretNotFound", err)
+	}
+	for _, ref := range []string{"keychain://", "keychain://a/b"} {
+		if _, err := k.Resolve(ctx, ref); err == nil {
+			t.Errorf("%s: expected error", ref)
+		}
+	}
+	if err := k.Set(ctx, "", "v"); err == nil {
+		t.Error("expected error for empty name")
+	}
+}
@@ -0,0 +1,95 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	credTypeGeneric          = 1
+	credPersistLocalMachine  = 2
+	errorNotFound            = syscall.Errno(1168)
+	credMaxCredentialBlobLen = 5 * 512
+)
+
+var (
+	advapi32       = syscall.NewLazyDLL("advapi32.dll")
+	procCredReadW  = advapi32.NewProc("CredReadW")
+	procCredWriteW = advapi32.NewProc("CredWriteW")
+	procCredFree   = advapi32.NewProc("CredFree")
+)
+
+// credential is CREDENTIALW of wincred.h.
+type credential struct {
+	Flags              uint32
+	Type               uint32
+	TargetName         *uint16
+	Comment            *uint16
+	LastWritten        syscall.Filetime
+	CredentialBlobSize uint32
+	CredentialBlob     *byte
+	Persist            uint32
+	AttributeCount     uint32
+	Attributes         uintptr
+	TargetAlias        *uint16
+	UserName           *uint16
+}
+
+// keychainGet returns the generic credential of service and account in Windows Credential Manager.
+func keychainGet(_ context.Context, service, account string) (string, error) {
+	target, err := syscall.UTF16PtrFromString(credTarget(service, account))
+	if err != nil {
+		return "", err
+	}
+	var cred *credential
+	r, _, err := procCredReadW.Call(uintptr(unsafe.Pointer(target)), credTypeGeneric, 0, uintptr(unsafe.Pointer(&cred)))
+	if r == 0 {
+		if errors.Is(err, errorNotFound) {
+			return "", ErrSecretNotFound
+		}
+		return "", fmt.Errorf("CredRead: %w", err)
+	}
+	defer func() { _, _, _ = procCredFree.Call(uintptr(unsafe.Pointer(cred))) }()
+	if cred.CredentialBlobSize == 0 {
+		return "", nil
+	}
+	return string(unsafe.Slice(cred.CredentialBlob, cred.CredentialBlobSize)), nil
+}
+
+// keychainSet stores the generic credential of service and account in Windows Credential Manager.
+func keychainSet(_ context.Context, service, account, secret string) error {
+	if len(secret) > credMaxCredentialBlobLen {
+		return fmt.Errorf("secret is too long for Windows Credential Manager (max %d bytes)", credMaxCredentialBlobLen)
+	}
+	target, err := syscall.UTF16PtrFromString(credTarget(service, account))
+	if err != nil {
+		return err
+	}
+	user, err := syscall.UTF16PtrFromString(account)
+	if err != nil {
+		return err
+	}
+	blob := []byte(secret)
+	cred := credential{
+		Type:               credTypeGeneric,
+		TargetName:         target,
+		CredentialBlobSize: uint32(len(blob)),
+		Persist:            credPersistLocalMachine,
+		UserName:           user,
+	}
+	if len(blob) > 0 {
+		cred.CredentialBlob = &blob[0]
+	}
+	if r, _, err := procCredWriteW.Call(uintptr(unsafe.Pointer(&cred)), 0); r == 0 {
+		return fmt.Errorf("CredWrite: %w", err)
+	}
+	return nil
+}
+
+// credTarget returns the target name of the credential of service and account.
+func credTarget(service, account string) string {
+	return service + ":" + account
+}
@@ -0,0 +1,172 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/k1LoW/envdo/internal/api"
+	"github.com/k1LoW/exec"
+)
+
+// OnePassword resolves op://vault/item/[section/]field references with 1Password.
+// If OP_CONNECT_HOST and OP_CONNECT_TOKEN are set, the 1Password Connect API is used,
+// otherwise the 1Password CLI (op).
+type OnePassword struct {
+	connectHost  string
+	connectToken string
+	client       *http.Client
+	run          func(ctx context.Context, args ...string) ([]byte, error)
+}
+
+type opVault struct {
+	ID string `json:"id"`
+}
+
+type opItem struct {
+	ID       string      `json:"id"`
+	Fields   []opField   `json:"fields"`
+	Sections []opSection `json:"sections"`
+}
+
+type opField struct {
+	ID      string     `json:"id"`
+	Label   string     `json:"label"`
+	Value   string     `json:"value"`
+	Section *opSection `json:"section"`
+}
+
+type opSection struct {
+	ID    string `json:"id"`
+	Label string `json:"label"`
+}
+
+// NewOnePassword creates a new OnePassword resolver.
+func NewOnePassword() *OnePassword {
+	return &OnePassword{
+		connectHost:  strings.TrimSuffix(os.Getenv("OP_CONNECT_HOST"), "/"),
+		connectToken: os.Getenv("OP_CONNECT_TOKEN"),
+		client:       api.NewClient(),
+		run:          runOp,
+	}
+}
+
+// Scheme returns "op".
+func (o *OnePassword) Scheme() string {
+	return "op"
+}
+
+// Resolve returns the value of the field referenced by ref.
+func (o *OnePassword) Resolve(ctx context.Context, ref string) (string, error) {
+	if o.connectHost != "" && o.connectToken != "" {
+		return o.resolveConnect(ctx, ref)
+	}
+	out, err := o.run(ctx, "read", "--no-newline", ref)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// CacheScope returns the host and the token of 1Password Connect, or the account and the service account token
+// of the 1Password CLI.
+func (o *OnePassword) CacheScope() string {
+	if o.connectHost != "" && o.connectToken != "" {
+		return strings.Join([]string{"connect", o.connectHost, o.connectToken}, "\x00")
+	}
+	return strings.Join([]string{"cli", os.Getenv("OP_ACCOUNT"), os.Getenv("OP_SERVICE_ACCOUNT_TOKEN")}, "\x00")
+}
+
+// Interactive reports whether the 1Password CLI may prompt to unlock, unless 1Password Connect
+// or a service account token is used.
+func (o *OnePassword) Interactive() bool {
+	return (o.connectHost == "" || o.connectToken == "") && os.Getenv("OP_SERVICE_ACCOUNT_TOKEN") == ""
+}
+
+// resolveConnect resolves ref with the 1Password Connect API.
+func (o *OnePassword) resolveConnect(ctx context.Context, ref string) (string, error) {
+	path, _, _ := strings.Cut(strings.TrimPrefix(ref, "op://"), "?")
+	parts := strings.Split(path, "/")
+	if len(parts) < 3 || len(parts) > 4 {
+		return "", fmt.Errorf("invalid 1Password reference: %s", ref)
+	}
+	vault, item, field := parts[0], parts[1], parts[len(parts)-1]
+	section := ""
+	if len(parts) == 4 {
+		section = parts[2]
+	}
+
+	var vaults []opVault
+	if err := o.get(ctx, "/v1/vaults?filter="+url.QueryEscape(fmt.Sprintf("name eq %q", vault)), &vaults); err != nil {
+		return "", err
+	}
+	vaultID := vault
+	if len(vaults) > 0 {
+		vaultID = vaults[0].ID
+	}
+	var items []opItem
+	if err := o.get(ctx, fmt.Sprintf("/v1/vaults/%s/items?filter=%s", url.PathEscape(vaultID), url.QueryEscape(fmt.Sprintf("title eq %q", item))), &items); err != nil {
+		return "", err
+	}
+	itemID := item
+	if len(items) > 0 {
+		itemID = items[0].ID
+	}
+	var it opItem
+	if err := o.get(ctx, fmt.Sprintf("/v1/vaults/%s/items/%s", url.PathEscape(vaultID), url.PathEscape(itemID)), &it); err != nil {
+		return "", err
+	}
+	for _, f := range it.Fields {
+		if f.Label != field && f.ID != field {
+			continue
+		}
+		if section != "" && (f.Section == nil || !o.matchSection(it.Sections, f.Section.ID, section)) {
+			continue
+		}
+		return f.Value, nil
+	}
+	return "", fmt.Errorf("field %s not found in %s", field, ref)
+}
+
+// matchSection reports whether the section of id is named (or identified by) name.
+func (o *OnePassword) matchSection(sections []opSection, id, name string) bool {
+	if id == name {
+		return true
+	}
+	for _, s := range sections {
+		if s.ID == id && s.Label == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (o *OnePassword) get(ctx context.Context, path string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, o.connectHost+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+o.connectToken)
+	return api.DoJSON(o.client, req, v)
+}
+
+// runOp runs the 1Password CLI with args and returns its stdout.
+func runOp(ctx context.Context, args ...string) ([]byte, error) {
+	if _, err := exec.LookPath("op"); err != nil {
+		return nil, errors.New("1Password CLI (op) is not found: install it or set OP_CONNECT_HOST and OP_CONNECT_TOKEN")
+	}
+	c := exec.CommandContext(ctx, "op", args...)
+	c.Stdin = os.Stdin
+	var stderr bytes.Buffer
+	c.Stderr = &stderr
+	out, err := c.Output()
+	if err != nil {
+		return nil, fmt.Errorf("op %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
@@ -0,0 +1,82 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestOnePasswordCLI(t *testing.T) {
+	var got []string
+	o := &OnePassword{
+		run: func(_ context.Context, args ...string) ([]byte, error) {
+			got = args
+			return []byte("s3cr3t"), nil
+		},
+	}
+	v, err := o.Resolve(context.Background(), "op://dev/db/password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != "s3cr3t" {
+		t.Errorf("got %q, want %q", v, "s3cr3t")
+	}
+	if want := []string{"read", "--no-newline", "op://dev/db/password"}; !slices.Equal(got, want) {
+		t.Errorf("args = %v, want %v", got, want)
+	}
+}
+
+func TestOnePasswordConnect(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/vaults", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Query().Get("filter") != `name eq "dev"` {
+			_, _ = w.Write([]byte(`[]`))
+			return
+		}
+		_, _ = w.Write([]byte(`[{"id":"v1"}]`))
+	})
+	mux.HandleFunc("GET /v1/vaults/v1/items", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"id":"i1"}]`))
+	})
+	mux.HandleFunc("GET /v1/vaults/v1/items/i1", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{
+  "id": "i1",
+  "sections": [{"id": "s1", "label": "admin"}],
+  "fields": [
+    {"id": "password", "label": "password", "value": "user-pass"},
+    {"id": "f2", "label": "password", "value": "admin-pass", "section": {"id": "s1"}}
+  ]
+}`))
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	o := &OnePassword{connectHost: ts.URL, connectToken: "token", client: ts.Client()}
+	tests := []struct {
+		ref     string
+		want    string
+		wantErr bool
+	}{
+		{"op://dev/db/password", "user-pass", false},
+		{"op://dev/db/admin/password", "admin-pass", false},
+		{"op://dev/db/username", "", true},
+		{"op://dev/db", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			got, err := o.Resolve(context.Background(), tt.ref)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
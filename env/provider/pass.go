@@ -0,0 +1,99 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/k1LoW/exec"
+)
+
+// Pass resolves pass://path/in/store[#key] references by reading the password store
+// of pass and gopass directly, decrypting the entry with gpg.
+// The value is the first line of the entry (the password), or the value of a
+// "key: value" line after it if #key is given.
+type Pass struct {
+	dirs    []string
+	decrypt func(ctx context.Context, path string) ([]byte, error)
+}
+
+// NewPass creates a new Pass resolver searching $PASSWORD_STORE_DIR, ~/.password-store
+// and the root store of gopass (~/.local/share/gopass/stores/root) in this order.
+func NewPass() *Pass {
+	var dirs []string
+	if d := os.Getenv("PASSWORD_STORE_DIR"); d != "" {
+		dirs = append(dirs, d)
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs, filepath.Join(home, ".password-store"), filepath.Join(home, ".local", "share", "gopass", "stores", "root"))
+	}
+	return &Pass{dirs: dirs, decrypt: gpgDecrypt}
+}
+
+// Scheme returns "pass".
+func (p *Pass) Scheme() string {
+	return "pass"
+}
+
+// Resolve returns the password or the value of the key of the entry referenced by ref.
+func (p *Pass) Resolve(ctx context.Context, ref string) (string, error) {
+	name, key, _ := strings.Cut(strings.TrimPrefix(ref, "pass://"), "#")
+	name = strings.Trim(name, "/")
+	if name == "" || strings.Contains(name, "..") {
+		return "", fmt.Errorf("invalid pass reference: %s", ref)
+	}
+	path, err := p.entryPath(name)
+	if err != nil {
+		return "", err
+	}
+	b, err := p.decrypt(ctx, path)
+	if err != nil {
+		return "", err
+	}
+	lines := strings.Split(strings.ReplaceAll(string(b), "\r\n", "\n"), "\n")
+	if key == "" {
+		return lines[0], nil
+	}
+	for _, l := range lines[1:] {
+		k, v, ok := strings.Cut(l, ":")
+		if ok && strings.TrimSpace(k) == key {
+			return strings.TrimSpace(v), nil
+		}
+	}
+	return "", fmt.Errorf("key %s not found in the pass entry %s", key, name)
+}
+
+// Interactive returns true, as gpg may prompt for the passphrase.
+func (p *Pass) Interactive() bool {
+	return true
+}
+
+// entryPath returns the path of the encrypted file of the entry in the first store containing it.
+func (p *Pass) entryPath(name string) (string, error) {
+	for _, d := range p.dirs {
+		path := filepath.Join(d, filepath.FromSlash(name)+".gpg")
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("%w in the password store: %s", ErrSecretNotFound, name)
+}
+
+// gpgDecrypt decrypts the file at path with gpg.
+func gpgDecrypt(ctx context.Context, path string) ([]byte, error) {
+	if _, err := exec.LookPath("gpg"); err != nil {
+		return nil, errors.New("gpg is not found: install GnuPG to decrypt the password store")
+	}
+	c := exec.CommandContext(ctx, "gpg", "--quiet", "--batch", "--decrypt", path)
+	var stderr bytes.Buffer
+	c.Stderr = &stderr
+	out, err := c.Output()
+	if err != nil {
+		return nil, fmt.Errorf("gpg --decrypt %s: %w: %s", path, err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
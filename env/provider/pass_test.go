@@ -0,0 +1,50 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPass(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "work", "db"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "work", "db", "prod.gpg"), []byte("s3cr3t\nuser: admin\nurl: https://db.example.com\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	// The fake decryption returns the content as it is
+	p := &Pass{dirs: []string{filepath.Join(dir, "missing"), dir}, decrypt: func(_ context.Context, path string) ([]byte, error) {
+		return os.ReadFile(path)
+	}}
+	ctx := context.Background()
+	tests := []struct {
+		ref  string
+		want string
+	}{
+		{"pass://work/db/prod", "s3cr3t"},
+		{"pass://work/db/prod#user", "admin"},
+		{"pass://work/db/prod#url", "https://db.example.com"},
+	}
+	for _, tt := range tests {
+		got, err := p.Resolve(ctx, tt.ref)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.ref, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.ref, got, tt.want)
+		}
+	}
+
+	if _, err := p.Resolve(ctx, "pass://work/db/dev"); !errors.Is(err, ErrSecretNotFound) {
+		t.Errorf("got %v, want ErrSecretNotFound", err)
+	}
+	for _, ref := range []string{"pass://", "pass://../secret", "pass://work/db/prod#password"} {
+		if _, err := p.Resolve(ctx, ref); err == nil {
+			t.Errorf("%s: expected error", ref)
+		}
+	}
+}
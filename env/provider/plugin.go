@@ -0,0 +1,154 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/k1LoW/exec"
+)
+
+// PluginPrefix is the prefix of the names of the executables of provider plugins (envdo-provider-<scheme>).
+const PluginPrefix = "envdo-provider-"
+
+// PluginProtocolVersion is the version of the JSON messages exchanged with provider plugins.
+const PluginProtocolVersion = 1
+
+// schemeRe matches URI schemes handled by provider plugins.
+var schemeRe = regexp.MustCompile(`^[a-z][a-z0-9+.-]*$`)
+
+// Plugin resolves references of its scheme with an external executable, so that secret backends
+// can be added without modifying envdo. For each reference, the executable is run with a JSON request
+//
+//	{"version": 1, "ref": "<scheme>://..."}
+//
+// on stdin, and writes a JSON response {"value": "..."} to stdout, or {"error": "..."} with a non-zero exit code.
+type Plugin struct {
+	scheme string
+	path   string
+}
+
+// pluginRequest is the request written to stdin of provider plugins.
+type pluginRequest struct {
+	Version int    `json:"version"`
+	Ref     string `json:"ref"`
+}
+
+// pluginResponse is the response read from stdout of provider plugins.
+type pluginResponse struct {
+	Value *string `json:"value"`
+	Error string  `json:"error"`
+}
+
+// NewPlugin creates a new Plugin resolving references of scheme with the executable at path.
+func NewPlugin(scheme, path string) *Plugin {
+	return &Plugin{scheme: scheme, path: path}
+}
+
+// Scheme returns the scheme of the plugin.
+func (p *Plugin) Scheme() string {
+	return p.scheme
+}
+
+// Path returns the path of the executable of the plugin.
+func (p *Plugin) Path() string {
+	return p.path
+}
+
+// CacheScope returns the path of the executable of the plugin.
+func (p *Plugin) CacheScope() string {
+	return p.path
+}
+
+// Resolve returns the secret value referenced by ref, running the executable of the plugin.
+func (p *Plugin) Resolve(ctx context.Context, ref string) (string, error) {
+	req, err := json.Marshal(pluginRequest{Version: PluginProtocolVersion, Ref: ref})
+	if err != nil {
+		return "", err
+	}
+	c := exec.CommandContext(ctx, p.path)
+	c.Stdin = bytes.NewReader(req)
+	var stderr bytes.Buffer
+	c.Stderr = &stderr
+	out, runErr := c.Output()
+	var res pluginResponse
+	if err := json.Unmarshal(out, &res); err != nil {
+		if runErr != nil {
+			return "", fmt.Errorf("%s: %w: %s", filepath.Base(p.path), runErr, strings.TrimSpace(stderr.String()))
+		}
+		return "", fmt.Errorf("%s: invalid response: %w", filepath.Base(p.path), err)
+	}
+	switch {
+	case res.Error != "":
+		return "", fmt.Errorf("%s: %s", filepath.Base(p.path), res.Error)
+	case runErr != nil:
+		return "", fmt.Errorf("%s: %w: %s", filepath.Base(p.path), runErr, strings.TrimSpace(stderr.String()))
+	case res.Value == nil:
+		return "", fmt.Errorf("%s: no value in the response", filepath.Base(p.path))
+	}
+	return *res.Value, nil
+}
+
+// FindPlugins returns the provider plugins found in the directories of pathList ($PATH) sorted by scheme.
+// The first executable of each name is used, and plugins of the schemes of the built-in resolvers are ignored.
+func FindPlugins(pathList string) []*Plugin {
+	var plugins []*Plugin
+	seen := make(map[string]bool)
+	for _, dir := range filepath.SplitList(pathList) {
+		if dir == "" {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			scheme, ok := pluginScheme(e.Name())
+			if !ok || seen[scheme] || slices.Contains(Schemes, scheme) {
+				continue
+			}
+			path := filepath.Join(dir, e.Name())
+			if !isExecutable(path) {
+				continue
+			}
+			seen[scheme] = true
+			plugins = append(plugins, NewPlugin(scheme, path))
+		}
+	}
+	slices.SortFunc(plugins, func(a, b *Plugin) int {
+		return strings.Compare(a.scheme, b.scheme)
+	})
+	return plugins
+}
+
+// pluginScheme returns the scheme of the plugin of the executable name, without the extension on Windows.
+func pluginScheme(name string) (string, bool) {
+	if runtime.GOOS == "windows" {
+		ext := strings.ToLower(filepath.Ext(name))
+		if !slices.Contains([]string{".exe", ".bat", ".cmd"}, ext) {
+			return "", false
+		}
+		name = name[:len(name)-len(ext)]
+	}
+	scheme, ok := strings.CutPrefix(name, PluginPrefix)
+	if !ok || !schemeRe.MatchString(scheme) {
+		return "", false
+	}
+	return scheme, true
+}
+
+// isExecutable reports whether path is a regular file executable by someone (any file on Windows).
+func isExecutable(path string) bool {
+	fi, err := os.Stat(path)
+	if err != nil || !fi.Mode().IsRegular() {
+		return false
+	}
+	return runtime.GOOS == "windows" || fi.Mode().Perm()&0o111 != 0
+}
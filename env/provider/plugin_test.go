@@ -0,0 +1,114 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+const pluginScript = `#!/bin/sh
+IFS= read -r req
+case "$req" in
+  *'"ref":"corp://fail"'*) echo '{"error":"no such secret"}'; exit 1;;
+  *'"ref":"corp://crash"'*) echo 'crashed' >&2; exit 2;;
+  *'"version":1,"ref":"corp://db/password"'*) echo '{"value":"s3cret"}';;
+  *) echo '{}';;
+esac
+`
+
+func writePlugin(t *testing.T, dir, name string, mode os.FileMode) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(pluginScript), mode); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFindPlugins(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are shell scripts")
+	}
+	dir1, dir2 := t.TempDir(), t.TempDir()
+	writePlugin(t, dir1, "envdo-provider-corp", 0o755)
+	writePlugin(t, dir2, "envdo-provider-corp", 0o755)
+	writePlugin(t, dir2, "envdo-provider-aws", 0o755)
+	writePlugin(t, dir2, "envdo-provider-vault", 0o755)
+	writePlugin(t, dir2, "envdo-provider-noexec", 0o644)
+	writePlugin(t, dir2, "envdo-provider-Invalid", 0o755)
+
+	plugins := FindPlugins(strings.Join([]string{dir1, "", filepath.Join(dir1, "missing"), dir2}, string(os.PathListSeparator)))
+	var got []string
+	for _, p := range plugins {
+		got = append(got, p.Scheme()+"="+p.Path())
+	}
+	want := []string{
+		"aws=" + filepath.Join(dir2, "envdo-provider-aws"),
+		"corp=" + filepath.Join(dir1, "envdo-provider-corp"),
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestPluginResolve(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are shell scripts")
+	}
+	dir := t.TempDir()
+	writePlugin(t, dir, "envdo-provider-corp", 0o755)
+	p := NewPlugin("corp", filepath.Join(dir, "envdo-provider-corp"))
+
+	tests := []struct {
+		ref     string
+		want    string
+		wantErr string
+	}{
+		{"corp://db/password", "s3cret", ""},
+		{"corp://fail", "", "envdo-provider-corp: no such secret"},
+		{"corp://crash", "", "crashed"},
+		{"corp://empty", "", "no value in the response"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			got, err := p.Resolve(context.Background(), tt.ref)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAvailable(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are shell scripts")
+	}
+	dir := t.TempDir()
+	writePlugin(t, dir, "envdo-provider-corp", 0o755)
+	t.Setenv("PATH", dir)
+
+	r, err := Available("corp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	envs := map[string]string{"A": "corp://db/password", "B": "op://dev/db/password"}
+	if err := r.Resolve(context.Background(), envs); err != nil {
+		t.Fatal(err)
+	}
+	if envs["A"] != "s3cret" || envs["B"] != "op://dev/db/password" {
+		t.Errorf("got %v", envs)
+	}
+	if _, err := Available("aws"); err == nil || !strings.Contains(err.Error(), "available: corp, doppler") {
+		t.Errorf("err = %v, want error for an unknown provider", err)
+	}
+}
@@ -0,0 +1,240 @@
+// Package provider resolves references to secrets in values of environment variables,
+// such as op://vault/item/field, with secret backends.
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Resolver resolves references of a URI scheme to secret values.
+type Resolver interface {
+	// Scheme returns the URI scheme of references handled by the resolver (e.g. "op").
+	Scheme() string
+	// Resolve returns the secret value referenced by ref (e.g. "op://vault/item/field").
+	Resolve(ctx context.Context, ref string) (string, error)
+}
+
+// Cache caches resolved secret values by key.
+type Cache interface {
+	// Get returns the cached value of key.
+	Get(key string) (string, bool)
+	// Set caches the value of key.
+	Set(key, value string)
+}
+
+// CacheScoper is implemented by resolvers whose resolved values may be cached.
+// CacheScope returns the endpoint and the identity references are resolved with (e.g. the address
+// of the server and the token), which are part of the cache keys, so cached values are not served
+// after they change. Values of resolvers not implementing it, such as those reading secrets stored
+// on the machine (keychain and pass), are not cached.
+type CacheScoper interface {
+	CacheScope() string
+}
+
+// Interactive is implemented by resolvers that may prompt the user, such as CLIs asking for a password,
+// a passphrase or biometric unlock. References of interactive resolvers are resolved one at a time,
+// so that their prompts do not compete for the terminal.
+type Interactive interface {
+	Interactive() bool
+}
+
+// DefaultConcurrency is the default number of references resolved concurrently.
+const DefaultConcurrency = 8
+
+// Registry resolves references with registered resolvers.
+type Registry struct {
+	resolvers   map[string]Resolver
+	cache       Cache
+	concurrency int
+}
+
+// NewRegistry creates a new Registry with resolvers.
+func NewRegistry(resolvers ...Resolver) *Registry {
+	r := &Registry{resolvers: make(map[string]Resolver), concurrency: DefaultConcurrency}
+	for _, res := range resolvers {
+		r.Register(res)
+	}
+	return r
+}
+
+// Schemes are the URI schemes of the built-in resolvers.
+var Schemes = []string{"op", "vault", "keychain", "pass", "doppler", "infisical"}
+
+// Default creates a new Registry with the built-in resolvers.
+func Default() *Registry {
+	return NewRegistry(NewOnePassword(), NewVault(), NewKeychain(), NewPass(), NewDoppler(), NewInfisical())
+}
+
+// Builtin creates a new Registry with the built-in resolvers of schemes.
+// All built-in resolvers are registered if no scheme is given.
+func Builtin(schemes ...string) (*Registry, error) {
+	return Default().enable(schemes)
+}
+
+// Available creates a new Registry with the built-in resolvers and the plugins found in $PATH of schemes.
+// All of them are registered if no scheme is given.
+func Available(schemes ...string) (*Registry, error) {
+	r := Default()
+	for _, p := range FindPlugins(os.Getenv("PATH")) {
+		r.Register(p)
+	}
+	return r.enable(schemes)
+}
+
+// enable returns a new Registry with the resolvers of schemes in r, or r if no scheme is given.
+func (r *Registry) enable(schemes []string) (*Registry, error) {
+	if len(schemes) == 0 {
+		return r, nil
+	}
+	enabled := NewRegistry()
+	for _, s := range schemes {
+		res, ok := r.resolvers[s]
+		if !ok {
+			return nil, fmt.Errorf("unknown secret provider: %s (available: %s)", s, strings.Join(r.Schemes(), ", "))
+		}
+		enabled.Register(res)
+	}
+	return enabled, nil
+}
+
+// Schemes returns the URI schemes of the registered resolvers sorted.
+func (r *Registry) Schemes() []string {
+	return slices.Sorted(maps.Keys(r.resolvers))
+}
+
+// Register registers a resolver. It replaces the resolver of the same scheme.
+func (r *Registry) Register(res Resolver) {
+	r.resolvers[res.Scheme()] = res
+}
+
+// SetCache sets the cache of resolved values consulted before resolvers.
+func (r *Registry) SetCache(c Cache) {
+	r.cache = c
+}
+
+// SetConcurrency sets the maximum number of references resolved concurrently.
+// Values less than 1 mean 1, resolving references one by one.
+func (r *Registry) SetConcurrency(n int) {
+	r.concurrency = max(n, 1)
+}
+
+// HasReferences reports whether envs contain references of registered schemes.
+func (r *Registry) HasReferences(envs map[string]string) bool {
+	for _, v := range envs {
+		if r.resolver(v) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolve replaces values of envs that are references of registered schemes with the secret values.
+// Each distinct reference is resolved once, or taken from the cache if set. References are resolved
+// concurrently by up to the concurrency of the registry, except those of interactive resolvers resolved
+// one at a time, and all failures are returned together.
+// envs is not modified if any reference fails.
+func (r *Registry) Resolve(ctx context.Context, envs map[string]string) error {
+	keys := make([]string, 0, len(envs))
+	for k := range envs {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	// The first key referring to each reference, to report failures
+	referrers := make(map[string]string)
+	resolved := make(map[string]string)
+	var refs []string
+	for _, k := range keys {
+		ref := envs[k]
+		if r.resolver(ref) == nil {
+			continue
+		}
+		if _, ok := referrers[ref]; ok {
+			continue
+		}
+		referrers[ref] = k
+		if key, ok := r.cacheKey(ref); ok {
+			if v, ok := r.cache.Get(key); ok {
+				resolved[ref] = v
+				continue
+			}
+		}
+		refs = append(refs, ref)
+	}
+
+	values := make([]string, len(refs))
+	errs := make([]error, len(refs))
+	sem := make(chan struct{}, max(r.concurrency, 1))
+	var (
+		wg          sync.WaitGroup
+		interactive sync.Mutex
+	)
+	for i, ref := range refs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res := r.resolver(ref)
+			if p, ok := res.(Interactive); ok && p.Interactive() {
+				interactive.Lock()
+				defer interactive.Unlock()
+			}
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			v, err := res.Resolve(ctx, ref)
+			if err != nil {
+				errs[i] = fmt.Errorf("failed to resolve %s of %s: %w", ref, referrers[ref], err)
+				return
+			}
+			values[i] = v
+		}()
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	for i, ref := range refs {
+		resolved[ref] = values[i]
+		if key, ok := r.cacheKey(ref); ok {
+			r.cache.Set(key, values[i])
+		}
+	}
+	for _, k := range keys {
+		if v, ok := resolved[envs[k]]; ok {
+			envs[k] = v
+		}
+	}
+	return nil
+}
+
+// cacheKey returns the key of ref in the cache, the reference followed by the hash of the cache scope
+// of its resolver, or false if the cache is not set or the values of the resolver are not cached.
+func (r *Registry) cacheKey(ref string) (string, bool) {
+	if r.cache == nil {
+		return "", false
+	}
+	s, ok := r.resolver(ref).(CacheScoper)
+	if !ok {
+		return "", false
+	}
+	sum := sha256.Sum256([]byte(r.resolver(ref).Scheme() + "\x00" + s.CacheScope()))
+	return ref + " " + hex.EncodeToString(sum[:]), true
+}
+
+// resolver returns the resolver for the reference, or nil if value is not a reference.
+func (r *Registry) resolver(value string) Resolver {
+	scheme, _, ok := strings.Cut(value, "://")
+	if !ok {
+		return nil
+	}
+	return r.resolvers[scheme]
+}
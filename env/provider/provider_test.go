@@ -0,0 +1,251 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeResolver struct {
+	mu     sync.Mutex
+	values map[string]string
+	calls  int
+}
+
+func (f *fakeResolver) Scheme() string {
+	return "fake"
+}
+
+func (f *fakeResolver) Resolve(_ context.Context, ref string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	v, ok := f.values[ref]
+	if !ok {
+		return "", errors.New("not found")
+	}
+	return v, nil
+}
+
+func TestRegistryResolve(t *testing.T) {
+	fake := &fakeResolver{values: map[string]string{"fake://a": "secret"}}
+	r := NewRegistry(fake)
+	envs := map[string]string{
+		"A":     "fake://a",
+		"B":     "fake://a",
+		"PLAIN": "value",
+		"URL":   "https://example.com",
+	}
+	if err := r.Resolve(context.Background(), envs); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"A":     "secret",
+		"B":     "secret",
+		"PLAIN": "value",
+		"URL":   "https://example.com",
+	}
+	for k, v := range want {
+		if envs[k] != v {
+			t.Errorf("%s = %q, want %q", k, envs[k], v)
+		}
+	}
+	if fake.calls != 1 {
+		t.Errorf("calls = %d, want 1", fake.calls)
+	}
+
+	if err := r.Resolve(context.Background(), map[string]string{"C": "fake://missing"}); err == nil {
+		t.Error("expected error for unresolvable reference")
+	}
+}
+
+// slowResolver resolves references after a delay, recording the maximum number of concurrent calls.
+type slowResolver struct {
+	mu      sync.Mutex
+	running int
+	peak    int
+}
+
+func (s *slowResolver) Scheme() string {
+	return "slow"
+}
+
+func (s *slowResolver) Resolve(_ context.Context, ref string) (string, error) {
+	s.mu.Lock()
+	s.running++
+	s.peak = max(s.peak, s.running)
+	s.mu.Unlock()
+	time.Sleep(20 * time.Millisecond)
+	s.mu.Lock()
+	s.running--
+	s.mu.Unlock()
+	if strings.HasSuffix(ref, "-error") {
+		return "", errors.New("denied")
+	}
+	return strings.TrimPrefix(ref, "slow://"), nil
+}
+
+func TestRegistryResolveConcurrently(t *testing.T) {
+	slow := &slowResolver{}
+	r := NewRegistry(slow)
+	r.SetConcurrency(3)
+	envs := make(map[string]string)
+	for i := range 10 {
+		envs[fmt.Sprintf("KEY%d", i)] = fmt.Sprintf("slow://value%d", i)
+	}
+	if err := r.Resolve(context.Background(), envs); err != nil {
+		t.Fatal(err)
+	}
+	for i := range 10 {
+		if got, want := envs[fmt.Sprintf("KEY%d", i)], fmt.Sprintf("value%d", i); got != want {
+			t.Errorf("KEY%d = %q, want %q", i, got, want)
+		}
+	}
+	if slow.peak < 2 || slow.peak > 3 {
+		t.Errorf("peak = %d, want 2 or 3", slow.peak)
+	}
+
+	envs = map[string]string{"A": "slow://a-error", "B": "slow://b", "C": "slow://c-error"}
+	err := r.Resolve(context.Background(), envs)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	want := "failed to resolve slow://a-error of A: denied\nfailed to resolve slow://c-error of C: denied"
+	if err.Error() != want {
+		t.Errorf("error = %q, want %q", err.Error(), want)
+	}
+	if envs["B"] != "slow://b" {
+		t.Errorf("B = %q, want unchanged on error", envs["B"])
+	}
+}
+
+// promptResolver is a slowResolver that may prompt the user.
+type promptResolver struct {
+	*slowResolver
+}
+
+func (p *promptResolver) Scheme() string {
+	return "prompt"
+}
+
+func (p *promptResolver) Interactive() bool {
+	return true
+}
+
+func TestRegistryResolveInteractive(t *testing.T) {
+	slow := &slowResolver{}
+	prompt := &promptResolver{&slowResolver{}}
+	r := NewRegistry(slow, prompt)
+	r.SetConcurrency(4)
+	envs := make(map[string]string)
+	for i := range 4 {
+		envs[fmt.Sprintf("SLOW%d", i)] = fmt.Sprintf("slow://value%d", i)
+		envs[fmt.Sprintf("PROMPT%d", i)] = fmt.Sprintf("prompt://value%d", i)
+	}
+	if err := r.Resolve(context.Background(), envs); err != nil {
+		t.Fatal(err)
+	}
+	if envs["SLOW0"] != "value0" {
+		t.Errorf("SLOW0 = %q, want value0", envs["SLOW0"])
+	}
+	// References of interactive resolvers are resolved one at a time, alongside the others
+	if prompt.peak != 1 {
+		t.Errorf("peak of the interactive resolver = %d, want 1", prompt.peak)
+	}
+	if slow.peak < 2 {
+		t.Errorf("peak of the other resolver = %d, want 2 or more", slow.peak)
+	}
+}
+
+func TestBuiltin(t *testing.T) {
+	r, err := Builtin("vault")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.resolver("vault://secret/app#KEY") == nil {
+		t.Error("vault should be enabled")
+	}
+	if r.resolver("op://dev/db/password") != nil {
+		t.Error("op should not be enabled")
+	}
+	if _, err := Builtin("aws"); err == nil {
+		t.Error("want error for an unknown provider")
+	}
+}
+
+type mapCache map[string]string
+
+func (m mapCache) Get(ref string) (string, bool) {
+	v, ok := m[ref]
+	return v, ok
+}
+
+func (m mapCache) Set(ref, value string) {
+	m[ref] = value
+}
+
+type scopedResolver struct {
+	*fakeResolver
+	scope string
+}
+
+func (s *scopedResolver) CacheScope() string {
+	return s.scope
+}
+
+func TestRegistryResolveCache(t *testing.T) {
+	fake := &fakeResolver{values: map[string]string{"fake://a": "secret", "fake://b": "fresh"}}
+	r := NewRegistry(&scopedResolver{fake, "https://example.com\x00token"})
+	c := mapCache{}
+	r.SetCache(c)
+	keyB, _ := r.cacheKey("fake://b")
+	c[keyB] = "cached"
+	envs := map[string]string{"A": "fake://a", "B": "fake://b", "PLAIN": "value"}
+	if !r.HasReferences(envs) {
+		t.Error("envs have references")
+	}
+	if err := r.Resolve(context.Background(), envs); err != nil {
+		t.Fatal(err)
+	}
+	if envs["A"] != "secret" || envs["B"] != "cached" {
+		t.Errorf("got %v", envs)
+	}
+	keyA, _ := r.cacheKey("fake://a")
+	if fake.calls != 1 || c[keyA] != "secret" {
+		t.Errorf("calls = %d, cache = %v", fake.calls, c)
+	}
+	if _, ok := c["fake://a"]; ok {
+		t.Error("the cache key should include the scope of the resolver")
+	}
+	if r.HasReferences(map[string]string{"PLAIN": "value"}) {
+		t.Error("plain values are not references")
+	}
+
+	// Cached values are not served after the token changes
+	r = NewRegistry(&scopedResolver{fake, "https://example.com\x00rotated"})
+	r.SetCache(c)
+	envs = map[string]string{"B": "fake://b"}
+	if err := r.Resolve(context.Background(), envs); err != nil {
+		t.Fatal(err)
+	}
+	if envs["B"] != "fresh" {
+		t.Errorf("got %q, want the value resolved with the new token", envs["B"])
+	}
+}
+
+func TestRegistryResolveCacheLocal(t *testing.T) {
+	fake := &fakeResolver{values: map[string]string{"fake://a": "secret"}}
+	r := NewRegistry(fake)
+	c := mapCache{}
+	r.SetCache(c)
+	if err := r.Resolve(context.Background(), map[string]string{"A": "fake://a"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(c) != 0 {
+		t.Errorf("values of resolvers without a cache scope should not be cached: %v", c)
+	}
+}
@@ -0,0 +1,171 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/k1LoW/envdo/internal/api"
+)
+
+// Vault resolves vault://path#key references with the KV secrets engine (v1 and v2)
+// of HashiCorp Vault at $VAULT_ADDR with $VAULT_TOKEN.
+//
+//	vault://secret/data/app#DB_PASSWORD
+//
+// For KV v2, the data/ segment of the path may be omitted.
+// A renewable token is renewed once before the first read.
+type Vault struct {
+	addr      string
+	token     string
+	namespace string
+	client    *http.Client
+
+	renew   sync.Once
+	mu      sync.Mutex
+	secrets map[string]func() (map[string]json.RawMessage, error)
+}
+
+// NewVault creates a new Vault resolver.
+func NewVault() *Vault {
+	return &Vault{
+		addr:      strings.TrimSuffix(os.Getenv("VAULT_ADDR"), "/"),
+		token:     os.Getenv("VAULT_TOKEN"),
+		namespace: os.Getenv("VAULT_NAMESPACE"),
+		client:    api.NewClient(),
+	}
+}
+
+// Scheme returns "vault".
+func (v *Vault) Scheme() string {
+	return "vault"
+}
+
+// Resolve returns the value of the key of the secret referenced by ref.
+func (v *Vault) Resolve(ctx context.Context, ref string) (string, error) {
+	if v.addr == "" || v.token == "" {
+		return "", errors.New("VAULT_ADDR and VAULT_TOKEN are required to resolve Vault references")
+	}
+	path, key, ok := strings.Cut(strings.TrimPrefix(ref, "vault://"), "#")
+	path = strings.Trim(path, "/")
+	if !ok || path == "" || key == "" {
+		return "", fmt.Errorf("invalid Vault reference (vault://path#key): %s", ref)
+	}
+	v.renew.Do(func() {
+		v.renewToken(ctx)
+	})
+	data, err := v.read(ctx, path)
+	if err != nil {
+		return "", err
+	}
+	raw, ok := data[key]
+	if !ok {
+		return "", fmt.Errorf("key %s not found in %s", key, path)
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s, nil
+	}
+	return string(raw), nil
+}
+
+// CacheScope returns the address, the namespace and the token of Vault.
+func (v *Vault) CacheScope() string {
+	return strings.Join([]string{v.addr, v.namespace, v.token}, "\x00")
+}
+
+// read returns the data of the secret at path, reading it once per path even if called concurrently.
+func (v *Vault) read(ctx context.Context, path string) (map[string]json.RawMessage, error) {
+	v.mu.Lock()
+	if v.secrets == nil {
+		v.secrets = make(map[string]func() (map[string]json.RawMessage, error))
+	}
+	read, ok := v.secrets[path]
+	if !ok {
+		read = sync.OnceValues(func() (map[string]json.RawMessage, error) {
+			return v.fetch(ctx, path)
+		})
+		v.secrets[path] = read
+	}
+	v.mu.Unlock()
+	return read()
+}
+
+// fetch reads the data of the secret at path from Vault.
+func (v *Vault) fetch(ctx context.Context, path string) (map[string]json.RawMessage, error) {
+	apiPath, v2 := v.kvPath(ctx, path)
+	var res struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := v.do(ctx, http.MethodGet, "/v1/"+apiPath, &res); err != nil {
+		return nil, err
+	}
+	var data map[string]json.RawMessage
+	if v2 {
+		var kv2 struct {
+			Data map[string]json.RawMessage `json:"data"`
+		}
+		if err := json.Unmarshal(res.Data, &kv2); err != nil {
+			return nil, err
+		}
+		data = kv2.Data
+	} else if err := json.Unmarshal(res.Data, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// kvPath returns the API path of the secret at path and whether it is in a KV v2 mount.
+// If the mount cannot be looked up, path is read as KV v1 unless it contains a data/ segment.
+func (v *Vault) kvPath(ctx context.Context, path string) (string, bool) {
+	var res struct {
+		Data struct {
+			Path    string            `json:"path"`
+			Options map[string]string `json:"options"`
+		} `json:"data"`
+	}
+	if err := v.do(ctx, http.MethodGet, "/v1/sys/internal/ui/mounts/"+path, &res); err != nil || res.Data.Path == "" {
+		_, rest, _ := strings.Cut(path, "/")
+		return path, strings.HasPrefix(rest, "data/")
+	}
+	if res.Data.Options["version"] != "2" {
+		return path, false
+	}
+	mount := strings.TrimSuffix(res.Data.Path, "/")
+	rest := strings.TrimPrefix(strings.TrimPrefix(path, mount), "/")
+	if strings.HasPrefix(rest, "data/") {
+		return path, true
+	}
+	return mount + "/data/" + rest, true
+}
+
+// renewToken renews the token if it is renewable. Failures are ignored
+// because the token may still be valid.
+func (v *Vault) renewToken(ctx context.Context) {
+	var res struct {
+		Data struct {
+			Renewable bool `json:"renewable"`
+		} `json:"data"`
+	}
+	if err := v.do(ctx, http.MethodGet, "/v1/auth/token/lookup-self", &res); err != nil || !res.Data.Renewable {
+		return
+	}
+	_ = v.do(ctx, http.MethodPost, "/v1/auth/token/renew-self", nil)
+}
+
+func (v *Vault) do(ctx context.Context, method, path string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, method, v.addr+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Vault-Token", v.token)
+	if v.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.namespace)
+	}
+	return api.DoJSON(v.client, req, out)
+}
@@ -0,0 +1,72 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestVault(t *testing.T) {
+	var renewed, reads int
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/auth/token/lookup-self", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":{"renewable":true}}`))
+	})
+	mux.HandleFunc("POST /v1/auth/token/renew-self", func(w http.ResponseWriter, r *http.Request) {
+		renewed++
+		_, _ = w.Write([]byte(`{}`))
+	})
+	mux.HandleFunc("GET /v1/sys/internal/ui/mounts/", func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v1/sys/internal/ui/mounts/kv/app":
+			_, _ = w.Write([]byte(`{"data":{"path":"kv/","options":{"version":"1"}}}`))
+		default:
+			_, _ = w.Write([]byte(`{"data":{"path":"secret/","options":{"version":"2"}}}`))
+		}
+	})
+	mux.HandleFunc("GET /v1/secret/data/app", func(w http.ResponseWriter, r *http.Request) {
+		reads++
+		_, _ = w.Write([]byte(`{"data":{"data":{"DB_PASSWORD":"v2-pass","PORT":5432},"metadata":{"version":3}}}`))
+	})
+	mux.HandleFunc("GET /v1/kv/app", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"DB_PASSWORD":"v1-pass"}}`))
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	v := &Vault{addr: ts.URL, token: "token", client: ts.Client()}
+	tests := []struct {
+		ref     string
+		want    string
+		wantErr bool
+	}{
+		{"vault://secret/data/app#DB_PASSWORD", "v2-pass", false},
+		{"vault://secret/app#DB_PASSWORD", "v2-pass", false},
+		{"vault://secret/data/app#PORT", "5432", false},
+		{"vault://kv/app#DB_PASSWORD", "v1-pass", false},
+		{"vault://secret/data/app#MISSING", "", true},
+		{"vault://secret/data/app", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			got, err := v.Resolve(context.Background(), tt.ref)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+	if renewed != 1 {
+		t.Errorf("renewed = %d, want 1", renewed)
+	}
+	if reads != 2 {
+		t.Errorf("reads = %d, want 2", reads)
+	}
+}
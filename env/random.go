@@ -0,0 +1,145 @@
+package env
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// RandomPrefix is the prefix of the directive to generate a random value.
+// For example, SESSION_SECRET=random:32 generates a random value of 32 characters.
+const RandomPrefix = "random:"
+
+const (
+	randomChars     = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+	maxRandomLength = 4096
+)
+
+// GenerateRandomValues replaces values of random directives in the .env file at path
+// with cryptographically random values and persists them to the file.
+// It returns the keys whose values were generated. Single-quoted values ('random:32') are kept as they are,
+// and remote, encrypted and structured (JSON, YAML, TOML and INI) files are skipped.
+// The file is read and written under the lock of ModifyFile, so concurrent invocations generate each value only once.
+// The file is not locked if it has no random directives.
+func GenerateRandomValues(path string) ([]string, error) {
+	if IsRemote(path) || IsEncrypted(path) || IsStructured(path) {
+		return nil, nil
+	}
+	directives, err := randomDirectives(path)
+	if err != nil || len(directives) == 0 {
+		return nil, err
+	}
+	var keys []string
+	if err := ModifyFile(path, func(envs map[string]string) (map[string]string, error) {
+		generated := make(map[string]string)
+		for _, k := range sortedKeys(envs) {
+			v, ok := strings.CutPrefix(envs[k], RandomPrefix)
+			if !ok || !directives[k] {
+				continue
+			}
+			n, err := strconv.Atoi(v)
+			if err != nil || n <= 0 || n > maxRandomLength {
+				return nil, fmt.Errorf("invalid random directive of %s: %s (length must be between 1 and %d, or single-quote the value to keep it)", k, envs[k], maxRandomLength)
+			}
+			r, err := randomString(n)
+			if err != nil {
+				return nil, err
+			}
+			generated[k] = r
+		}
+		keys = sortedKeys(generated)
+		return generated, nil
+	}); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// randomDirectives returns the keys whose values are random directives in the .env file at path,
+// except single-quoted values.
+func randomDirectives(path string) (map[string]bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	entries, err := parseEntries(f, path)
+	if err != nil {
+		return nil, err
+	}
+	slices.Reverse(entries)
+	directives := make(map[string]bool)
+	for _, v := range ResolveVars(entries) {
+		if strings.HasPrefix(v.Value, RandomPrefix) && !v.Literal {
+			directives[v.Key] = true
+		}
+	}
+	return directives, nil
+}
+
+// Types of values generated by GenerateValue.
+const (
+	ValueTypeRandom = "random"
+	ValueTypeHex    = "hex"
+	ValueTypeBase64 = "base64"
+	ValueTypeUUID   = "uuid"
+)
+
+// ValueTypes are the types of values generated by GenerateValue.
+var ValueTypes = []string{ValueTypeRandom, ValueTypeHex, ValueTypeBase64, ValueTypeUUID}
+
+// GenerateValue returns a cryptographically random value of the type:
+// n alphanumeric characters (random), n bytes encoded in hex (hex) or base64 (base64),
+// or a version 4 UUID (uuid, n is ignored).
+func GenerateValue(typ string, n int) (string, error) {
+	if typ == ValueTypeUUID {
+		return uuid()
+	}
+	if n <= 0 || n > maxRandomLength {
+		return "", fmt.Errorf("invalid length %d (must be between 1 and %d)", n, maxRandomLength)
+	}
+	switch typ {
+	case ValueTypeRandom:
+		return randomString(n)
+	case ValueTypeHex, ValueTypeBase64:
+		b := make([]byte, n)
+		if _, err := rand.Read(b); err != nil {
+			return "", err
+		}
+		if typ == ValueTypeHex {
+			return hex.EncodeToString(b), nil
+		}
+		return base64.StdEncoding.EncodeToString(b), nil
+	default:
+		return "", fmt.Errorf("unsupported type: %s (must be one of %s)", typ, strings.Join(ValueTypes, ", "))
+	}
+}
+
+// randomString returns a cryptographically random alphanumeric string of n characters.
+func randomString(n int) (string, error) {
+	b := make([]byte, n)
+	limit := big.NewInt(int64(len(randomChars)))
+	for i := range b {
+		j, err := rand.Int(rand.Reader, limit)
+		if err != nil {
+			return "", err
+		}
+		b[i] = randomChars[j.Int64()]
+	}
+	return string(b), nil
+}
+
+func sortedKeys(envs map[string]string) []string {
+	keys := make([]string, 0, len(envs))
+	for k := range envs {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,120 @@
+package env
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"testing"
+)
+
+func TestGenerateRandomValues(t *testing.T) {
+	dir := t.TempDir()
+	createTestFile(t, dir, ".env", `# session
+SESSION_SECRET=random:32
+PORT=8080
+`)
+	path := filepath.Join(dir, ".env")
+	keys, err := GenerateRandomValues(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(keys, []string{"SESSION_SECRET"}) {
+		t.Errorf("got %v", keys)
+	}
+	envs, err := New(dir, "").LoadEnvFiles("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret := envs["SESSION_SECRET"]
+	if len(secret) != 32 {
+		t.Errorf("want 32 characters, got %q", secret)
+	}
+	if envs["PORT"] != "8080" {
+		t.Errorf("got %q", envs["PORT"])
+	}
+
+	// Generated values are persisted and not regenerated
+	keys, err = GenerateRandomValues(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 0 {
+		t.Errorf("got %v", keys)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "# session\nSESSION_SECRET=" + secret + "\nPORT=8080\n"; string(b) != want {
+		t.Errorf("got %q, want %q", b, want)
+	}
+}
+
+func TestGenerateRandomValuesInvalid(t *testing.T) {
+	for _, v := range []string{"random:", "random:0", "random:abc", "random:100000"} {
+		dir := t.TempDir()
+		createTestFile(t, dir, ".env", "KEY="+v+"\n")
+		if _, err := GenerateRandomValues(filepath.Join(dir, ".env")); err == nil {
+			t.Errorf("%s: want error", v)
+		}
+	}
+}
+
+func TestGenerateRandomValuesLiteral(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	dir := t.TempDir()
+	content := "A='random:32'\nB='random:abc'\n"
+	createTestFile(t, dir, ".env", content)
+	path := filepath.Join(dir, ".env")
+	keys, err := GenerateRandomValues(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 0 {
+		t.Errorf("got %v, want no keys", keys)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != content {
+		t.Errorf("got %q, want %q", b, content)
+	}
+	// The file without random directives is not locked
+	lp, err := lockPath(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(lp); !os.IsNotExist(err) {
+		t.Errorf("got %v, want no lock file", err)
+	}
+}
+
+func TestGenerateValue(t *testing.T) {
+	tests := []struct {
+		typ     string
+		n       int
+		pattern string
+	}{
+		{ValueTypeRandom, 32, `^[A-Za-z0-9]{32}$`},
+		{ValueTypeHex, 16, `^[0-9a-f]{32}$`},
+		{ValueTypeBase64, 32, `^[A-Za-z0-9+/]{43}=$`},
+		{ValueTypeUUID, 0, `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`},
+	}
+	for _, tt := range tests {
+		got, err := GenerateValue(tt.typ, tt.n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !regexp.MustCompile(tt.pattern).MatchString(got) {
+			t.Errorf("%s: got %q, want matching %s", tt.typ, got, tt.pattern)
+		}
+	}
+	if _, err := GenerateValue(ValueTypeHex, 0); err == nil {
+		t.Error("want error for length 0")
+	}
+	if _, err := GenerateValue("words", 8); err == nil {
+		t.Error("want error for an unsupported type")
+	}
+}
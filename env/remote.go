@@ -0,0 +1,182 @@
+package env
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/k1LoW/envdo/internal/api"
+)
+
+// URLTokenEnv is the environment variable of the bearer token sent to remote env URLs.
+const URLTokenEnv = "ENVDO_URL_TOKEN"
+
+// URLTokenHostsEnv is the environment variable of the hosts (separated by commas) $ENVDO_URL_TOKEN is sent to.
+const URLTokenHostsEnv = "ENVDO_URL_TOKEN_HOSTS"
+
+// maxRemoteSize is the maximum size of a remote env file.
+const maxRemoteSize = 10 * 1024 * 1024
+
+// maxRedirects is the maximum number of redirects followed when fetching a remote env file.
+const maxRedirects = 10
+
+// RemoteSchemes are the URI schemes of remote env files.
+var RemoteSchemes = []string{"https", "s3", "gs"}
+
+// Remote fetches env files from remote sources: HTTPS URLs, Amazon S3 (s3://bucket/key) with the AWS CLI
+// and Google Cloud Storage (gs://bucket/object) with the gcloud CLI, using their ambient credentials.
+// The content can be pinned with its SHA-256 checksum in the fragment of the URL
+// (e.g. https://config.internal/app/prod.env#sha256=<hex>).
+type Remote struct {
+	client *http.Client
+	token  string
+	hosts  []string
+	run    func(ctx context.Context, name string, args ...string) ([]byte, error)
+}
+
+// NewRemote creates a new Remote. If token is not empty, it is sent as a bearer token to the HTTPS URLs
+// of hosts (host or host:port), and not to the hosts they redirect to.
+func NewRemote(client *http.Client, token string, hosts []string) *Remote {
+	return &Remote{
+		client: client,
+		token:  token,
+		hosts:  hosts,
+		run:    api.RunCLI,
+	}
+}
+
+// urlTokenHosts returns the hosts of $ENVDO_URL_TOKEN_HOSTS.
+func urlTokenHosts() []string {
+	var hosts []string
+	for h := range strings.SplitSeq(os.Getenv(URLTokenHostsEnv), ",") {
+		if h = strings.TrimSpace(h); h != "" {
+			hosts = append(hosts, h)
+		}
+	}
+	return hosts
+}
+
+// IsRemote reports whether path is the URL of a remote env file.
+func IsRemote(path string) bool {
+	scheme, _, ok := strings.Cut(path, "://")
+	return ok && slices.Contains(RemoteSchemes, scheme)
+}
+
+// Load loads environment variables from the .env file (or the JSON, YAML, TOML or INI file by the extension of the path) at rawURL.
+func (r *Remote) Load(ctx context.Context, rawURL string) (map[string]string, error) {
+	envs := make(map[string]string)
+	if err := r.scan(ctx, rawURL, func(e Entry) {
+		if e.Unset {
+			delete(envs, e.Key)
+			return
+		}
+		envs[e.Key] = e.Value
+	}); err != nil {
+		return nil, err
+	}
+	return envs, nil
+}
+
+// Fetch returns the content of the remote env file at rawURL, verifying its checksum if pinned.
+func (r *Remote) Fetch(ctx context.Context, rawURL string) ([]byte, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL %s: %w", rawURL, err)
+	}
+	var checksum string
+	if u.Fragment != "" {
+		var ok bool
+		checksum, ok = strings.CutPrefix(u.Fragment, "sha256=")
+		if !ok {
+			return nil, fmt.Errorf("invalid URL %s: the fragment must be sha256=<hex>", rawURL)
+		}
+		u.Fragment = ""
+	}
+	var b []byte
+	switch u.Scheme {
+	case "https":
+		b, err = r.fetchHTTPS(ctx, u)
+	case "s3":
+		b, err = r.run(ctx, "aws", "s3", "cp", u.String(), "-")
+	case "gs":
+		b, err = r.run(ctx, "gcloud", "storage", "cat", u.String())
+	default:
+		return nil, fmt.Errorf("invalid URL %s: unsupported scheme (supported: %s)", rawURL, strings.Join(RemoteSchemes, ", "))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", u, err)
+	}
+	if len(b) > maxRemoteSize {
+		return nil, fmt.Errorf("failed to fetch %s: larger than %d bytes", u, maxRemoteSize)
+	}
+	if checksum != "" {
+		sum := sha256.Sum256(b)
+		if got := hex.EncodeToString(sum[:]); !strings.EqualFold(got, checksum) {
+			return nil, fmt.Errorf("checksum mismatch of %s: got sha256=%s, want sha256=%s", u, got, checksum)
+		}
+	}
+	return b, nil
+}
+
+// scan calls fn for each definition in the remote env file at rawURL.
+func (r *Remote) scan(ctx context.Context, rawURL string, fn func(Entry)) error {
+	b, err := r.Fetch(ctx, rawURL)
+	if err != nil {
+		return err
+	}
+	name, _, _ := strings.Cut(rawURL, "#")
+	if err := scanFile(bytes.NewReader(b), name, fn); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+	return nil
+}
+
+func (r *Remote) fetchHTTPS(ctx context.Context, u *url.URL) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	sendToken := r.token != "" && r.tokenHost(u)
+	if sendToken {
+		req.Header.Set("Authorization", "Bearer "+r.token)
+	}
+	client := *r.client
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= maxRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxRedirects)
+		}
+		// Do not send the token to another host or over plain HTTP
+		if req.URL.Scheme != "https" || !strings.EqualFold(req.URL.Host, via[0].URL.Host) {
+			req.Header.Del("Authorization")
+		}
+		return nil
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		if r.token != "" && !sendToken {
+			return nil, fmt.Errorf("%s ($%s is not sent to %s, which is not in $%s)", res.Status, URLTokenEnv, u.Host, URLTokenHostsEnv)
+		}
+		return nil, errors.New(res.Status)
+	}
+	return io.ReadAll(io.LimitReader(res.Body, maxRemoteSize+1))
+}
+
+// tokenHost reports whether the token is sent to the host of u.
+func (r *Remote) tokenHost(u *url.URL) bool {
+	return slices.ContainsFunc(r.hosts, func(h string) bool {
+		return strings.EqualFold(h, u.Host) || strings.EqualFold(h, u.Hostname())
+	})
+}
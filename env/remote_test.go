@@ -0,0 +1,144 @@
+package env
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRemoteHTTPS(t *testing.T) {
+	content := "API_URL=https://api.internal\nDEBUG=false\n"
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/app/prod.env":
+			_, _ = w.Write([]byte(content))
+		case "/app/prod.env.json":
+			_, _ = w.Write([]byte(`{"API": {"URL": "https://api.internal"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+	ctx := context.Background()
+	sum := sha256.Sum256([]byte(content))
+
+	host := strings.TrimPrefix(ts.URL, "https://")
+	r := NewRemote(ts.Client(), "token", []string{host})
+	envs, err := r.Load(ctx, ts.URL+"/app/prod.env#sha256="+hex.EncodeToString(sum[:]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"API_URL": "https://api.internal", "DEBUG": "false"}; !maps.Equal(envs, want) {
+		t.Errorf("got %v, want %v", envs, want)
+	}
+	envs, err = r.Load(ctx, ts.URL+"/app/prod.env.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if envs["API_URL"] != "https://api.internal" {
+		t.Errorf("got %v", envs)
+	}
+
+	for _, tt := range []struct {
+		url   string
+		token string
+		hosts []string
+		want  string
+	}{
+		{ts.URL + "/app/prod.env#sha256=0000", "token", []string{host}, "checksum mismatch"},
+		{ts.URL + "/app/prod.env#md5=0000", "token", []string{host}, "fragment"},
+		{ts.URL + "/app/prod.env", "", []string{host}, "401"},
+		// The token is sent only to the configured hosts
+		{ts.URL + "/app/prod.env", "token", nil, "not in $" + URLTokenHostsEnv},
+		{ts.URL + "/app/prod.env", "token", []string{"config.internal"}, "not in $" + URLTokenHostsEnv},
+		{ts.URL + "/app/missing.env", "token", []string{host}, "404"},
+		{strings.Replace(ts.URL, "https://", "http://", 1) + "/app/prod.env", "token", []string{host}, "unsupported scheme"},
+	} {
+		if _, err := NewRemote(ts.Client(), tt.token, tt.hosts).Load(ctx, tt.url); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: got %v, want error containing %q", tt.url, err, tt.want)
+		}
+	}
+}
+
+func TestRemoteHTTPSRedirect(t *testing.T) {
+	var got []string
+	other := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte("A=1\n"))
+	}))
+	defer other.Close()
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get("Authorization"))
+		switch r.URL.Path {
+		case "/same":
+			http.Redirect(w, r, "/other", http.StatusFound)
+		case "/other":
+			http.Redirect(w, r, other.URL+"/prod.env", http.StatusFound)
+		case "/loop":
+			http.Redirect(w, r, "/loop", http.StatusFound)
+		}
+	}))
+	defer ts.Close()
+	ctx := context.Background()
+	// Both servers are listed, but the token is not sent to the host redirected to
+	hosts := []string{strings.TrimPrefix(ts.URL, "https://"), strings.TrimPrefix(other.URL, "https://")}
+	r := NewRemote(ts.Client(), "token", hosts)
+	if _, err := r.Load(ctx, ts.URL+"/same"); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"Bearer token", "Bearer token", ""}; !slices.Equal(got, want) {
+		t.Errorf("got Authorization %q, want %q", got, want)
+	}
+	if _, err := r.Load(ctx, ts.URL+"/loop"); err == nil || !strings.Contains(err.Error(), "redirects") {
+		t.Errorf("got %v, want error of too many redirects", err)
+	}
+}
+
+func TestRemoteObjectStorage(t *testing.T) {
+	var got [][]string
+	r := &Remote{run: func(_ context.Context, name string, args ...string) ([]byte, error) {
+		got = append(got, append([]string{name}, args...))
+		return []byte("BUCKET=1\n"), nil
+	}}
+	ctx := context.Background()
+	for _, u := range []string{"s3://bucket/app/.env.prod", "gs://bucket/app/.env.prod"} {
+		envs, err := r.Load(ctx, u)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if envs["BUCKET"] != "1" {
+			t.Errorf("%s: got %v", u, envs)
+		}
+	}
+	want := [][]string{
+		{"aws", "s3", "cp", "s3://bucket/app/.env.prod", "-"},
+		{"gcloud", "storage", "cat", "gs://bucket/app/.env.prod"},
+	}
+	if !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	// Remote files are loaded in order with local files
+	dir := t.TempDir()
+	createTestFile(t, dir, "local.env", "BUCKET=0\nLOCAL=1\n")
+	e := New("", "")
+	e.SetRemote(r)
+	envs, err := e.LoadFiles([]string{filepath.Join(dir, "local.env"), "s3://bucket/app/.env.prod"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"BUCKET": "1", "LOCAL": "1"}; !maps.Equal(envs, want) {
+		t.Errorf("got %v, want %v", envs, want)
+	}
+}
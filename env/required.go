@@ -0,0 +1,52 @@
+package env
+
+import (
+	"os"
+	"strings"
+)
+
+// RequiredDirective is the comment directive to annotate the following key as required.
+const RequiredDirective = "envdo:required"
+
+// RequiredKeys returns the keys annotated as required in a .env file.
+//
+//	# envdo:required
+//	API_KEY=
+//
+// Encrypted and structured (JSON, YAML, TOML and INI) files are not checked.
+func RequiredKeys(path string) ([]string, error) {
+	if IsEncrypted(path) || IsStructured(path) {
+		return nil, nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var (
+		keys    []string
+		pending bool
+	)
+	if err := scanLogicalLines(file, func(_ int, line string) error {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+			pending = false
+		case strings.HasPrefix(line, "#"):
+			if strings.TrimSpace(strings.TrimPrefix(line, "#")) == RequiredDirective {
+				pending = true
+			}
+		case pending:
+			pending = false
+			if key, _, ok := strings.Cut(line, "="); ok {
+				key, _ = parseKey(key)
+				keys = append(keys, key)
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
@@ -0,0 +1,30 @@
+package env
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestRequiredKeys(t *testing.T) {
+	dir := t.TempDir()
+	createTestFile(t, dir, ".env", `# envdo:required
+API_KEY=
+
+# envdo:required
+# envdo:expires 2099-01-01
+TOKEN=xxx
+OPTIONAL=
+
+# envdo:required
+
+NOT_REQUIRED=
+`)
+	got, err := RequiredKeys(filepath.Join(dir, ".env"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"API_KEY", "TOKEN"}; !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
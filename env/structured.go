@@ -0,0 +1,125 @@
+package env
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+)
+
+// StructuredExtensions are the extensions of env files in JSON, YAML, TOML or INI, in priority order.
+var StructuredExtensions = []string{".json", ".yaml", ".yml", ".toml", ".ini"}
+
+// IsStructured reports whether the env file at path is in JSON, YAML, TOML or INI, by its extension.
+// The extension of encrypted files is checked without the .age suffix.
+func IsStructured(path string) bool {
+	ext := filepath.Ext(strings.TrimSuffix(path, EncryptedSuffix))
+	for _, e := range StructuredExtensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+// scanFile parses definitions of environment variables from r in the format of file
+// and calls fn for each definition in order.
+func scanFile(r io.Reader, file string, fn func(Entry)) error {
+	if IsStructured(file) {
+		return scanStructured(r, file, fn)
+	}
+	return scanEntries(r, file, fn)
+}
+
+// scanStructured parses definitions of environment variables in JSON, YAML, TOML or INI (by the extension of file)
+// from r and calls fn for each definition in order. Nested maps of JSON and YAML are flattened by joining keys
+// with "_" (PARENT_CHILD), and lists are encoded in JSON.
+func scanStructured(r io.Reader, file string, fn func(Entry)) error {
+	switch filepath.Ext(strings.TrimSuffix(file, EncryptedSuffix)) {
+	case ".toml":
+		return scanTOML(r, file, fn)
+	case ".ini":
+		return scanINI(r, file, fn)
+	}
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	f, err := parser.ParseBytes(b, 0)
+	if err != nil {
+		return err
+	}
+	for _, doc := range f.Docs {
+		if err := flattenNode(doc.Body, "", file, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// flattenNode calls fn for each scalar in n with its key prefixed with prefix.
+func flattenNode(n ast.Node, prefix, file string, fn func(Entry)) error {
+	switch n := n.(type) {
+	case nil:
+		return nil
+	case *ast.CommentGroupNode:
+		return nil
+	case *ast.DocumentNode:
+		return flattenNode(n.Body, prefix, file, fn)
+	case *ast.MappingNode:
+		for _, v := range n.Values {
+			if err := flattenNode(v, prefix, file, fn); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *ast.MappingValueNode:
+		key := n.Key.GetToken().Value
+		if prefix != "" {
+			key = prefix + "_" + key
+		}
+		if m, ok := n.Value.(*ast.MappingNode); ok {
+			return flattenNode(m, key, file, fn)
+		}
+		if m, ok := n.Value.(*ast.MappingValueNode); ok {
+			return flattenNode(m, key, file, fn)
+		}
+		value, err := scalarValue(n.Value)
+		if err != nil {
+			return err
+		}
+		fn(Entry{Key: key, Value: value, File: file, Line: n.Key.GetToken().Position.Line})
+		return nil
+	default:
+		return fmt.Errorf("line %d: top level must be a map", n.GetToken().Position.Line)
+	}
+}
+
+// scalarValue returns the value of n as a string. Lists are encoded in JSON.
+func scalarValue(n ast.Node) (string, error) {
+	switch n := n.(type) {
+	case nil, *ast.NullNode:
+		return "", nil
+	case *ast.StringNode:
+		return n.Value, nil
+	case *ast.LiteralNode:
+		return n.Value.Value, nil
+	case *ast.IntegerNode, *ast.FloatNode, *ast.BoolNode:
+		return n.GetToken().Value, nil
+	default:
+		var v any
+		if err := yaml.NodeToValue(n, &v); err != nil {
+			return "", err
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	}
+}
@@ -0,0 +1,166 @@
+package env
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadEnvFilesStructured(t *testing.T) {
+	pwd := t.TempDir()
+	configDir := t.TempDir()
+	createTestFile(t, pwd, ".env.dev.json", `{
+  "API_URL": "http://localhost:8080",
+  "PORT": 8080,
+  "DEBUG": true,
+  "DB": {"HOST": "localhost", "USER": "dev"},
+  "HOSTS": ["a", "b"],
+  "EMPTY": null
+}`)
+	createTestFile(t, pwd, ".env.dev.yaml", `# comment
+DB:
+  HOST: yaml-host
+  PASSWORD: secret
+MESSAGE: |
+  hello
+  world
+`)
+	createTestFile(t, pwd, ".env.dev", "PORT=3000\n")
+
+	envs, err := New(pwd, configDir).LoadEnvFiles("dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"API_URL":     "http://localhost:8080",
+		"PORT":        "3000",
+		"DEBUG":       "true",
+		"DB_HOST":     "localhost",
+		"DB_USER":     "dev",
+		"DB_PASSWORD": "secret",
+		"HOSTS":       `["a","b"]`,
+		"EMPTY":       "",
+		"MESSAGE":     "hello\nworld\n",
+	}
+	if len(envs) != len(want) {
+		t.Errorf("got %d variables, want %d: %v", len(envs), len(want), envs)
+	}
+	for k, v := range want {
+		if envs[k] != v {
+			t.Errorf("%s = %q, want %q", k, envs[k], v)
+		}
+	}
+}
+
+func TestEntriesStructured(t *testing.T) {
+	pwd := t.TempDir()
+	createTestFile(t, pwd, ".env.yml", "A: a\nB:\n  C: c\n")
+	entries, err := New(pwd, "").Entries("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(pwd, ".env.yml")
+	want := []Entry{
+		{Key: "B_C", Value: "c", File: path, Line: 3},
+		{Key: "A", Value: "a", File: path, Line: 1},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("got %v, want %v", entries, want)
+	}
+	for i := range want {
+		if entries[i] != want[i] {
+			t.Errorf("entries[%d] = %v, want %v", i, entries[i], want[i])
+		}
+	}
+}
+
+func TestLintStructured(t *testing.T) {
+	dir := t.TempDir()
+	createTestFile(t, dir, ".env.json", `{"api-key": "x", "OK": "y"}`)
+	createTestFile(t, dir, ".env.yaml", "- a\n- b\n")
+
+	issues, err := Lint(filepath.Join(dir, ".env.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 1 || issues[0].Key != "api-key" {
+		t.Errorf("got %v, want an issue of api-key", issues)
+	}
+	issues, err = Lint(filepath.Join(dir, ".env.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 1 || issues[0].Severity != SeverityError {
+		t.Errorf("got %v, want a syntax error", issues)
+	}
+}
+
+func TestEntriesTOMLAndINI(t *testing.T) {
+	pwd := t.TempDir()
+	createTestFile(t, pwd, ".env.dev.toml", `log-level = "debug"
+started = 2024-01-02T03:04:05Z
+
+[database]
+url = "postgres://localhost/app"
+pool = 5
+ratio = 0.5
+replica = { host = "replica", port = 5433 }
+hosts = [
+  "a",
+  "b",
+]
+description = """
+line = not a key
+"""
+
+[[servers]]
+name = "web"
+
+[[servers]]
+name = "worker"
+`)
+	createTestFile(t, pwd, ".env.dev.ini", `; comment
+debug = true
+
+[database]
+url = "postgres://ini/app"
+user: app
+`)
+	entries, err := New(pwd, "").Entries("dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+	toml := filepath.Join(pwd, ".env.dev.toml")
+	ini := filepath.Join(pwd, ".env.dev.ini")
+	want := []Entry{
+		{Key: "SERVERS", Value: `[{"name":"web"},{"name":"worker"}]`, File: toml, Line: 17},
+		{Key: "DATABASE_DESCRIPTION", Value: "line = not a key\n", File: toml, Line: 13},
+		{Key: "DATABASE_HOSTS", Value: `["a","b"]`, File: toml, Line: 9},
+		{Key: "DATABASE_REPLICA_PORT", Value: "5433", File: toml, Line: 8},
+		{Key: "DATABASE_REPLICA_HOST", Value: "replica", File: toml, Line: 8},
+		{Key: "DATABASE_RATIO", Value: "0.5", File: toml, Line: 7},
+		{Key: "DATABASE_POOL", Value: "5", File: toml, Line: 6},
+		{Key: "DATABASE_URL", Value: "postgres://localhost/app", File: toml, Line: 5},
+		{Key: "STARTED", Value: "2024-01-02T03:04:05Z", File: toml, Line: 2},
+		{Key: "LOG_LEVEL", Value: "debug", File: toml, Line: 1},
+		{Key: "DATABASE_USER", Value: "app", File: ini, Line: 6},
+		{Key: "DATABASE_URL", Value: "postgres://ini/app", File: ini, Line: 5},
+		{Key: "DEBUG", Value: "true", File: ini, Line: 2},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("got %v, want %v", entries, want)
+	}
+	for i := range want {
+		if entries[i] != want[i] {
+			t.Errorf("entries[%d] = %v, want %v", i, entries[i], want[i])
+		}
+	}
+
+	createTestFile(t, pwd, ".env.ini", "[database\n")
+	issues, err := Lint(filepath.Join(pwd, ".env.ini"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 1 || issues[0].Severity != SeverityError {
+		t.Errorf("got %v, want a syntax error", issues)
+	}
+}
@@ -0,0 +1,67 @@
+package env
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/k1LoW/exec"
+)
+
+// CommandSubstitution returns the command of a value of the form $(command).
+func CommandSubstitution(value string) (string, bool) {
+	command, ok := strings.CutPrefix(value, "$(")
+	if !ok {
+		return "", false
+	}
+	command, ok = strings.CutSuffix(command, ")")
+	command = strings.TrimSpace(command)
+	if !ok || command == "" {
+		return "", false
+	}
+	return command, true
+}
+
+// SubstituteCommands replaces the values of keys of the form $(command) in envs with the output of the commands,
+// like command substitution of shells. The caller decides which keys are allowed to run commands.
+//
+//	AWS_SESSION_TOKEN=$(aws sts get-session-token --query Credentials.SessionToken --output text)
+//
+// The commands are run in order of keys with sh -c (cmd /c on Windows) and the environment of the process
+// with envs, and trailing newlines of their stdout are removed. Keys whose values are not of the form are ignored.
+func SubstituteCommands(ctx context.Context, envs map[string]string, keys []string) error {
+	for _, k := range keys {
+		command, ok := CommandSubstitution(envs[k])
+		if !ok {
+			continue
+		}
+		out, err := runShell(ctx, command, envs)
+		if err != nil {
+			return fmt.Errorf("failed to run the command substitution of %s: %w", k, err)
+		}
+		envs[k] = strings.TrimRight(string(out), "\r\n")
+	}
+	return nil
+}
+
+// runShell runs command with the shell and the environment of the process with envs, and returns its stdout.
+func runShell(ctx context.Context, command string, envs map[string]string) ([]byte, error) {
+	c := exec.CommandContext(ctx, "sh", "-c", command)
+	if runtime.GOOS == "windows" {
+		c = exec.CommandContext(ctx, "cmd.exe", "/c", command)
+	}
+	c.Env = os.Environ()
+	for k, v := range envs {
+		c.Env = append(c.Env, k+"="+v)
+	}
+	var stderr bytes.Buffer
+	c.Stderr = &stderr
+	out, err := c.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w: %s", command, err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
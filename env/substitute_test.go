@@ -0,0 +1,54 @@
+package env
+
+import (
+	"context"
+	"runtime"
+	"testing"
+)
+
+func TestCommandSubstitution(t *testing.T) {
+	tests := []struct {
+		in     string
+		want   string
+		wantOK bool
+	}{
+		{"$(aws sts get-session-token)", "aws sts get-session-token", true},
+		{"$( echo a )", "echo a", true},
+		{"$()", "", false},
+		{"$(echo", "", false},
+		{"prefix $(echo a)", "", false},
+		{"$HOME", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := CommandSubstitution(tt.in)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("CommandSubstitution(%q) = %q, %v, want %q, %v", tt.in, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestSubstituteCommands(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("sh is not available")
+	}
+	envs := map[string]string{
+		"NAME":    "envdo",
+		"GREET":   "$(echo hello $NAME)",
+		"DENIED":  "$(echo denied)",
+		"LITERAL": "echo",
+	}
+	if err := SubstituteCommands(context.Background(), envs, []string{"GREET", "LITERAL"}); err != nil {
+		t.Fatal(err)
+	}
+	if envs["GREET"] != "hello envdo" {
+		t.Errorf("GREET = %q", envs["GREET"])
+	}
+	if envs["DENIED"] != "$(echo denied)" || envs["LITERAL"] != "echo" {
+		t.Errorf("DENIED = %q, LITERAL = %q", envs["DENIED"], envs["LITERAL"])
+	}
+
+	envs = map[string]string{"FAIL": "$(echo oops >&2; exit 3)"}
+	if err := SubstituteCommands(context.Background(), envs, []string{"FAIL"}); err == nil {
+		t.Error("want error for a failing command")
+	}
+}
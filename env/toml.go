@@ -0,0 +1,164 @@
+package env
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// scanTOML parses definitions of environment variables in TOML from r and calls fn for each definition
+// in order. Keys in tables are prefixed with the table names and upper-cased ([database] url= → DATABASE_URL),
+// and arrays are encoded in JSON.
+func scanTOML(r io.Reader, file string, fn func(Entry)) error {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	var data map[string]any
+	md, err := toml.NewDecoder(bytes.NewReader(b)).Decode(&data)
+	if err != nil {
+		return err
+	}
+	lines := tomlKeyLines(b)
+	var arrays []toml.Key
+	for _, k := range md.Keys() {
+		// Arrays of tables are listed for each table, and their elements are encoded with the arrays
+		if slices.ContainsFunc(arrays, func(a toml.Key) bool { return len(k) >= len(a) && slices.Equal(k[:len(a)], a) }) {
+			continue
+		}
+		v := lookupTOML(data, k)
+		switch v.(type) {
+		case map[string]any:
+			continue
+		case []any, []map[string]any:
+			arrays = append(arrays, k)
+		}
+		value, err := tomlValue(v)
+		if err != nil {
+			return err
+		}
+		line := 0
+		for i := len(k); i > 0 && line == 0; i-- {
+			// Keys of inline tables have the line of the table
+			line = lines[strings.Join(k[:i], ".")]
+		}
+		fn(Entry{Key: sectionKey(k...), Value: value, File: file, Line: line})
+	}
+	return nil
+}
+
+// lookupTOML returns the value of key in data decoded from TOML.
+func lookupTOML(data map[string]any, key toml.Key) any {
+	var v any = data
+	for _, k := range key {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil
+		}
+		v = m[k]
+	}
+	return v
+}
+
+// tomlValue returns v decoded from TOML as a string. Arrays are encoded in JSON.
+func tomlValue(v any) (string, error) {
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case time.Time:
+		return v.Format(time.RFC3339Nano), nil
+	case []any, []map[string]any:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	default:
+		// int64, bool and local dates and times
+		return fmt.Sprint(v), nil
+	}
+}
+
+// tomlKeyLines returns the line numbers of the tables and keys defined in TOML b by their paths joined with ".".
+// Keys of inline tables are not included.
+func tomlKeyLines(b []byte) map[string]int {
+	lines := make(map[string]int)
+	var (
+		table     []string
+		multiline string
+	)
+	for i, line := range strings.Split(string(b), "\n") {
+		line = strings.TrimSpace(line)
+		if multiline != "" {
+			if strings.Contains(line, multiline) {
+				multiline = ""
+			}
+			continue
+		}
+		switch {
+		case line == "" || line[0] == '#' || line[0] == '{' || line[0] == ']':
+			continue
+		case line[0] == '[':
+			name := strings.TrimLeft(line, "[")
+			if j := strings.Index(name, "]"); j >= 0 {
+				name = name[:j]
+			}
+			table = splitTOMLKey(name)
+			if _, ok := lines[strings.Join(table, ".")]; !ok {
+				lines[strings.Join(table, ".")] = i + 1
+			}
+		default:
+			k, v, ok := strings.Cut(line, "=")
+			if !ok {
+				continue
+			}
+			path := strings.Join(slices.Concat(table, splitTOMLKey(k)), ".")
+			if _, ok := lines[path]; !ok {
+				lines[path] = i + 1
+			}
+			v = strings.TrimSpace(v)
+			for _, d := range []string{`"""`, `'''`} {
+				if strings.HasPrefix(v, d) && !strings.Contains(v[len(d):], d) {
+					multiline = d
+				}
+			}
+		}
+	}
+	return lines
+}
+
+// splitTOMLKey splits a dotted TOML key into its parts without quotes.
+func splitTOMLKey(s string) []string {
+	var (
+		parts []string
+		part  strings.Builder
+		quote rune
+	)
+	for _, c := range s {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+				continue
+			}
+		case c == '"' || c == '\'':
+			quote = c
+			continue
+		case c == '.':
+			parts = append(parts, strings.TrimSpace(part.String()))
+			part.Reset()
+			continue
+		}
+		part.WriteRune(c)
+	}
+	return append(parts, strings.TrimSpace(part.String()))
+}
@@ -0,0 +1,95 @@
+package env
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// AddPrefix returns envs with prefix added to all keys.
+func AddPrefix(envs map[string]string, prefix string) map[string]string {
+	if prefix == "" {
+		return envs
+	}
+	transformed := make(map[string]string, len(envs))
+	for k, v := range envs {
+		transformed[prefix+k] = v
+	}
+	return transformed
+}
+
+// StripPrefix returns envs with prefix stripped from keys.
+// Keys without prefix are kept as they are, and are overridden by the stripped keys on conflicts.
+func StripPrefix(envs map[string]string, prefix string) map[string]string {
+	if prefix == "" {
+		return envs
+	}
+	transformed := make(map[string]string, len(envs))
+	for k, v := range envs {
+		if !strings.HasPrefix(k, prefix) {
+			transformed[k] = v
+		}
+	}
+	for k, v := range envs {
+		if stripped, ok := strings.CutPrefix(k, prefix); ok && stripped != "" {
+			transformed[stripped] = v
+		}
+	}
+	return transformed
+}
+
+// Rename returns envs with keys renamed by mapping (from -> to).
+// Renamed keys override existing keys with the same names.
+func Rename(envs map[string]string, mapping map[string]string) map[string]string {
+	if len(mapping) == 0 {
+		return envs
+	}
+	transformed := make(map[string]string, len(envs))
+	for k, v := range envs {
+		if _, ok := mapping[k]; !ok {
+			transformed[k] = v
+		}
+	}
+	for from, to := range mapping {
+		if v, ok := envs[from]; ok {
+			transformed[to] = v
+		}
+	}
+	return transformed
+}
+
+// Key cases of TransformCase.
+const (
+	CaseUpper    = "upper"
+	CaseLower    = "lower"
+	CaseConstant = "constant"
+)
+
+// KeyCases are the supported key cases.
+var KeyCases = []string{CaseUpper, CaseLower, CaseConstant}
+
+var nonKeyCharRe = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+// TransformCase returns envs with keys transformed to keyCase.
+// CaseConstant upcases keys and replaces characters invalid in keys (such as '.' and '-') with '_'.
+// If transformed keys conflict, the value of the key that sorts last wins.
+func TransformCase(envs map[string]string, keyCase string) (map[string]string, error) {
+	var fn func(string) string
+	switch keyCase {
+	case "":
+		return envs, nil
+	case CaseUpper:
+		fn = strings.ToUpper
+	case CaseLower:
+		fn = strings.ToLower
+	case CaseConstant:
+		fn = func(k string) string { return strings.ToUpper(nonKeyCharRe.ReplaceAllString(k, "_")) }
+	default:
+		return nil, fmt.Errorf("unsupported key case: %s", keyCase)
+	}
+	transformed := make(map[string]string, len(envs))
+	for _, k := range sortedKeys(envs) {
+		transformed[fn(k)] = envs[k]
+	}
+	return transformed, nil
+}
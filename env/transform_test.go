@@ -0,0 +1,59 @@
+package env
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestAddPrefix(t *testing.T) {
+	got := AddPrefix(map[string]string{"DB_URL": "postgres://", "PORT": "8080"}, "VITE_")
+	want := map[string]string{"VITE_DB_URL": "postgres://", "VITE_PORT": "8080"}
+	if !maps.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestStripPrefix(t *testing.T) {
+	got := StripPrefix(map[string]string{"APP_DB_URL": "app", "DB_URL": "shared", "PORT": "8080"}, "APP_")
+	want := map[string]string{"DB_URL": "app", "PORT": "8080"}
+	if !maps.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestRename(t *testing.T) {
+	got := Rename(map[string]string{"PG_PASSWORD": "pass", "TOKEN": "tok", "GITHUB_TOKEN": "old", "PORT": "8080"}, map[string]string{
+		"PG_PASSWORD": "PGPASSWORD",
+		"TOKEN":       "GITHUB_TOKEN",
+		"MISSING":     "OTHER",
+	})
+	want := map[string]string{"PGPASSWORD": "pass", "GITHUB_TOKEN": "tok", "PORT": "8080"}
+	if !maps.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestTransformCase(t *testing.T) {
+	envs := map[string]string{"db_url": "postgres://", "api.token": "xxx", "Port": "8080"}
+	tests := []struct {
+		keyCase string
+		want    map[string]string
+	}{
+		{"", envs},
+		{CaseUpper, map[string]string{"DB_URL": "postgres://", "API.TOKEN": "xxx", "PORT": "8080"}},
+		{CaseLower, map[string]string{"db_url": "postgres://", "api.token": "xxx", "port": "8080"}},
+		{CaseConstant, map[string]string{"DB_URL": "postgres://", "API_TOKEN": "xxx", "PORT": "8080"}},
+	}
+	for _, tt := range tests {
+		got, err := TransformCase(envs, tt.keyCase)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !maps.Equal(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.keyCase, got, tt.want)
+		}
+	}
+	if _, err := TransformCase(envs, "camel"); err == nil {
+		t.Error("want error")
+	}
+}
@@ -0,0 +1,147 @@
+package env
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// VaultFilename is the name of the encrypted env file of dotenv-vault and dotenvx.
+	VaultFilename = ".env.vault"
+	// DotenvKeyEnv is the environment variable of the keys decrypting .env.vault files.
+	DotenvKeyEnv = "DOTENV_KEY"
+	// vaultDefaultEnvironment is the environment of dotenv-vault for the default .env file.
+	vaultDefaultEnvironment = "development"
+)
+
+// Vault decrypts .env.vault files of dotenv-vault and dotenvx with DOTENV_KEY,
+// a comma-separated list of keys of an environment such as
+// dotenv://:key_1234…@dotenv.org/vault/.env.vault?environment=production.
+// Multiple keys are tried in order, for key rotation.
+type Vault struct {
+	environment string
+	keys        []vaultKey
+}
+
+type vaultKey struct {
+	key         []byte
+	environment string
+}
+
+// NewVault creates a new Vault with the keys in DOTENV_KEY format.
+func NewVault(dotenvKey string) (*Vault, error) {
+	v := &Vault{}
+	for s := range strings.SplitSeq(dotenvKey, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		k, err := parseVaultKey(s)
+		if err != nil {
+			return nil, err
+		}
+		if v.environment == "" {
+			v.environment = k.environment
+		}
+		if k.environment != v.environment {
+			return nil, fmt.Errorf("invalid %s: keys of different environments (%s and %s)", DotenvKeyEnv, v.environment, k.environment)
+		}
+		v.keys = append(v.keys, k)
+	}
+	if len(v.keys) == 0 {
+		return nil, fmt.Errorf("no key in %s", DotenvKeyEnv)
+	}
+	return v, nil
+}
+
+// Decrypt decrypts the environment of the keys in the content of a .env.vault file.
+// The keys are tried in order, and the plaintext of the first key that decrypts it is returned.
+func (v *Vault) Decrypt(vault []byte) ([]byte, error) {
+	envs, err := Parse(bytes.NewReader(vault))
+	if err != nil {
+		return nil, err
+	}
+	name := "DOTENV_VAULT_" + strings.ToUpper(v.environment)
+	ciphertext, ok := envs[name]
+	if !ok {
+		return nil, fmt.Errorf("%s not found", name)
+	}
+	var errs []error
+	for _, k := range v.keys {
+		plaintext, err := k.decrypt(ciphertext)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		return plaintext, nil
+	}
+	return nil, fmt.Errorf("failed to decrypt %s: %w", name, errors.Join(errs...))
+}
+
+// Has reports whether the keys are of the environment of the profile.
+// The default profile is the development environment.
+func (v *Vault) Has(profile string) bool {
+	if profile == "" {
+		return v.environment == vaultDefaultEnvironment
+	}
+	return v.environment == profile
+}
+
+// IsVault reports whether the env file at path is a .env.vault file.
+func IsVault(path string) bool {
+	return filepath.Base(path) == VaultFilename
+}
+
+// decrypt decrypts base64-encoded ciphertext of AES-256-GCM with the nonce prepended.
+func (k vaultKey) decrypt(ciphertext string) ([]byte, error) {
+	b, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(k.key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) < gcm.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	return gcm.Open(nil, b[:gcm.NonceSize()], b[gcm.NonceSize():], nil)
+}
+
+// parseVaultKey parses a key in DOTENV_KEY format.
+func parseVaultKey(s string) (vaultKey, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		// The error is not wrapped because it contains the key
+		return vaultKey{}, fmt.Errorf("invalid %s: not a URL", DotenvKeyEnv)
+	}
+	password, _ := u.User.Password()
+	if password == "" {
+		return vaultKey{}, fmt.Errorf("invalid %s: missing key", DotenvKeyEnv)
+	}
+	environment := u.Query().Get("environment")
+	if environment == "" {
+		return vaultKey{}, fmt.Errorf("invalid %s: missing environment", DotenvKeyEnv)
+	}
+	// The key is the last 64 hex characters (32 bytes) of the password, after the key_ prefix
+	if len(password) < 64 {
+		return vaultKey{}, fmt.Errorf("invalid %s: key too short", DotenvKeyEnv)
+	}
+	key, err := hex.DecodeString(password[len(password)-64:])
+	if err != nil {
+		return vaultKey{}, fmt.Errorf("invalid %s: %w", DotenvKeyEnv, err)
+	}
+	return vaultKey{key: key, environment: environment}, nil
+}
@@ -0,0 +1,115 @@
+package env
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"path/filepath"
+	"testing"
+)
+
+func encryptVault(t *testing.T, key []byte, plaintext string) string {
+	t.Helper()
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		t.Fatal(err)
+	}
+	return base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, []byte(plaintext), nil))
+}
+
+func TestVault(t *testing.T) {
+	devKey := make([]byte, 32)
+	prodKey := make([]byte, 32)
+	if _, err := rand.Read(devKey); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rand.Read(prodKey); err != nil {
+		t.Fatal(err)
+	}
+	pwd := t.TempDir()
+	createTestFile(t, pwd, VaultFilename, "#/ cloud-agnostic vaulting standard /#\n"+
+		`DOTENV_VAULT_DEVELOPMENT="`+encryptVault(t, devKey, "API_KEY=dev\nPORT=3000\n")+"\"\n"+
+		`DOTENV_VAULT_PRODUCTION="`+encryptVault(t, prodKey, "API_KEY=prod\n")+"\"\n")
+	createTestFile(t, pwd, ".env.production", "PORT=8080\n")
+
+	prod := "dotenv://:key_" + hex.EncodeToString(prodKey) + "@dotenv.org/vault/.env.vault?environment=production"
+	dev := "dotenv://:key_" + hex.EncodeToString(devKey) + "@dotenv.org/vault/.env.vault?environment=development"
+	e := New(pwd, t.TempDir())
+
+	tests := []struct {
+		dotenvKey string
+		profile   string
+		want      map[string]string
+	}{
+		{prod, "production", map[string]string{"API_KEY": "prod", "PORT": "8080"}},
+		{dev, "", map[string]string{"API_KEY": "dev", "PORT": "3000"}},
+	}
+	for _, tt := range tests {
+		v, err := NewVault(tt.dotenvKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		e.SetVault(v)
+		got, err := e.LoadEnvFiles(tt.profile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%q: got %v, want %v", tt.profile, got, tt.want)
+		}
+		for k, w := range tt.want {
+			if got[k] != w {
+				t.Errorf("%q: %s = %q, want %q", tt.profile, k, got[k], w)
+			}
+		}
+	}
+	if files := e.Files("staging"); len(files) != 0 {
+		t.Errorf("got %v, want no files for an environment without keys", files)
+	}
+
+	// The keys are tried in order
+	wrong := "dotenv://:key_" + hex.EncodeToString(devKey) + "@dotenv.org/vault/.env.vault?environment=production"
+	v, err := NewVault(wrong + "," + prod)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.SetVault(v)
+	got, err := e.LoadFiles([]string{filepath.Join(pwd, VaultFilename)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got["API_KEY"] != "prod" {
+		t.Errorf("API_KEY = %q, want prod", got["API_KEY"])
+	}
+
+	e.SetVault(nil)
+	if _, err := e.LoadFiles([]string{filepath.Join(pwd, VaultFilename)}); err == nil {
+		t.Error("want error without DOTENV_KEY")
+	}
+}
+
+func TestNewVaultError(t *testing.T) {
+	tests := []string{
+		"",
+		"dotenv://dotenv.org/vault/.env.vault?environment=production",
+		"dotenv://:key_1234@dotenv.org/vault/.env.vault?environment=production",
+		"dotenv://:key_" + hex.EncodeToString(make([]byte, 32)) + "@dotenv.org/vault/.env.vault",
+		"dotenv://:key_" + hex.EncodeToString(make([]byte, 32)) + "@dotenv.org/vault/.env.vault?environment=production," +
+			"dotenv://:key_" + hex.EncodeToString(make([]byte, 32)) + "@dotenv.org/vault/.env.vault?environment=development",
+	}
+	for _, tt := range tests {
+		if _, err := NewVault(tt); err == nil {
+			t.Errorf("NewVault(%q): want error", tt)
+		}
+	}
+}
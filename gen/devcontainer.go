@@ -0,0 +1,221 @@
+package gen
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/k1LoW/envdo/schema"
+)
+
+// Sections of devcontainer.json for environment variables.
+const (
+	DevcontainerRemoteEnv    = "remoteEnv"
+	DevcontainerContainerEnv = "containerEnv"
+)
+
+// Devcontainer returns devcontainer.json src with the section (remoteEnv or containerEnv)
+// replaced by envs. Secret keys are referenced via ${localEnv:KEY} instead of their values.
+// The rest of src, including comments, is kept as it is. If src is empty, a new file is returned.
+func Devcontainer(src []byte, envs map[string]string, s *schema.Schema, section string) ([]byte, error) {
+	if section != DevcontainerRemoteEnv && section != DevcontainerContainerEnv {
+		return nil, fmt.Errorf("unsupported section: %s (supported: %s, %s)", section, DevcontainerRemoteEnv, DevcontainerContainerEnv)
+	}
+	values := make(map[string]string, len(envs))
+	for k, v := range envs {
+		if s.IsSecret(k) {
+			v = fmt.Sprintf("${localEnv:%s}", k)
+		}
+		values[k] = v
+	}
+	b, err := json.MarshalIndent(values, "\t", "\t")
+	if err != nil {
+		return nil, err
+	}
+
+	if len(bytes.TrimSpace(src)) == 0 {
+		return fmt.Appendf(nil, "{\n\t%q: %s\n}\n", section, b), nil
+	}
+
+	start, end, closing, err := findTopLevelValue(src, section)
+	if err != nil {
+		return nil, err
+	}
+	var out bytes.Buffer
+	if start >= 0 {
+		out.Write(src[:start])
+		out.Write(b)
+		out.Write(src[end:])
+		return out.Bytes(), nil
+	}
+
+	// Insert the section before the closing brace of the top-level object
+	before := bytes.TrimRight(src[:closing], " \t\r\n")
+	out.Write(before)
+	if last := lastSignificant(before); last != '{' && last != ',' {
+		out.WriteByte(',')
+	}
+	fmt.Fprintf(&out, "\n\t%q: %s\n", section, b)
+	out.Write(src[closing:])
+	return out.Bytes(), nil
+}
+
+// findTopLevelValue returns the byte range of the value of key in the top-level object of JSONC src,
+// or -1 if the key does not exist, and the position of the closing brace of the top-level object.
+func findTopLevelValue(src []byte, key string) (int, int, int, error) {
+	blank := blankJSONComments(src)
+	start, end := -1, -1
+	depth := 0
+	expectKey := false
+	for i := 0; i < len(blank); i++ {
+		c := blank[i]
+		switch c {
+		case '"':
+			j, err := skipJSONString(blank, i)
+			if err != nil {
+				return 0, 0, 0, err
+			}
+			if depth == 1 && expectKey {
+				var k string
+				if err := json.Unmarshal(blank[i:j], &k); err != nil {
+					return 0, 0, 0, err
+				}
+				v := skipSpace(blank, j)
+				if v >= len(blank) || blank[v] != ':' {
+					return 0, 0, 0, errors.New("invalid devcontainer.json: ':' expected")
+				}
+				expectKey = false
+				if k == key {
+					start = skipSpace(blank, v+1)
+					end, err = skipJSONValue(blank, start)
+					if err != nil {
+						return 0, 0, 0, err
+					}
+					i = end - 1
+					continue
+				}
+				i = v
+				continue
+			}
+			i = j - 1
+		case '{', '[':
+			depth++
+			if depth == 1 {
+				expectKey = true
+			}
+		case '}', ']':
+			depth--
+			if depth == 0 {
+				return start, end, i, nil
+			}
+		case ',':
+			if depth == 1 {
+				expectKey = true
+			}
+		}
+	}
+	return 0, 0, 0, errors.New("invalid devcontainer.json: top-level object is not closed")
+}
+
+// blankJSONComments returns a copy of src with // and /* */ comments replaced by spaces,
+// keeping byte offsets and newlines.
+func blankJSONComments(src []byte) []byte {
+	b := bytes.Clone(src)
+	for i := 0; i < len(b); i++ {
+		switch {
+		case b[i] == '"':
+			j, err := skipJSONString(b, i)
+			if err != nil {
+				return b
+			}
+			i = j - 1
+		case b[i] == '/' && i+1 < len(b) && b[i+1] == '/':
+			for ; i < len(b) && b[i] != '\n'; i++ {
+				b[i] = ' '
+			}
+		case b[i] == '/' && i+1 < len(b) && b[i+1] == '*':
+			b[i], b[i+1] = ' ', ' '
+			i += 2
+			for ; i < len(b) && !(b[i] == '*' && i+1 < len(b) && b[i+1] == '/'); i++ {
+				if b[i] != '\n' {
+					b[i] = ' '
+				}
+			}
+			if i < len(b) {
+				b[i], b[i+1] = ' ', ' '
+				i++
+			}
+		}
+	}
+	return b
+}
+
+// skipJSONString returns the position just after the string starting at i.
+func skipJSONString(b []byte, i int) (int, error) {
+	for j := i + 1; j < len(b); j++ {
+		switch b[j] {
+		case '\\':
+			j++
+		case '"':
+			return j + 1, nil
+		}
+	}
+	return 0, errors.New("invalid devcontainer.json: unterminated string")
+}
+
+// skipJSONValue returns the position just after the value starting at i.
+func skipJSONValue(b []byte, i int) (int, error) {
+	depth := 0
+	for j := i; j < len(b); j++ {
+		switch b[j] {
+		case '"':
+			k, err := skipJSONString(b, j)
+			if err != nil {
+				return 0, err
+			}
+			if depth == 0 {
+				return k, nil
+			}
+			j = k - 1
+		case '{', '[':
+			depth++
+		case '}', ']':
+			if depth == 0 {
+				return trimRightSpace(b, i, j), nil
+			}
+			depth--
+			if depth == 0 {
+				return j + 1, nil
+			}
+		case ',':
+			if depth == 0 {
+				return trimRightSpace(b, i, j), nil
+			}
+		}
+	}
+	return 0, errors.New("invalid devcontainer.json: unterminated value")
+}
+
+func skipSpace(b []byte, i int) int {
+	for i < len(b) && (b[i] == ' ' || b[i] == '\t' || b[i] == '\r' || b[i] == '\n') {
+		i++
+	}
+	return i
+}
+
+func trimRightSpace(b []byte, start, end int) int {
+	for end > start && (b[end-1] == ' ' || b[end-1] == '\t' || b[end-1] == '\r' || b[end-1] == '\n') {
+		end--
+	}
+	return end
+}
+
+// lastSignificant returns the last byte of b that is not whitespace or a comment.
+func lastSignificant(b []byte) byte {
+	blank := bytes.TrimRight(blankJSONComments(b), " \t\r\n")
+	if len(blank) == 0 {
+		return 0
+	}
+	return blank[len(blank)-1]
+}
@@ -0,0 +1,83 @@
+package gen
+
+import (
+	"testing"
+
+	"github.com/k1LoW/envdo/schema"
+)
+
+func TestDevcontainer(t *testing.T) {
+	envs := map[string]string{
+		"PORT":      "8080",
+		"API_TOKEN": "secret",
+	}
+	tests := []struct {
+		name    string
+		src     string
+		section string
+		want    string
+	}{
+		{
+			name:    "new file",
+			src:     "",
+			section: DevcontainerRemoteEnv,
+			want: `{
+	"remoteEnv": {
+		"API_TOKEN": "${localEnv:API_TOKEN}",
+		"PORT": "8080"
+	}
+}
+`,
+		},
+		{
+			name: "insert section and keep comments",
+			src: `{
+	// Image to use
+	"image": "mcr.microsoft.com/devcontainers/go:1",
+	"features": {},
+}
+`,
+			section: DevcontainerContainerEnv,
+			want: `{
+	// Image to use
+	"image": "mcr.microsoft.com/devcontainers/go:1",
+	"features": {},
+	"containerEnv": {
+		"API_TOKEN": "${localEnv:API_TOKEN}",
+		"PORT": "8080"
+	}
+}
+`,
+		},
+		{
+			name: "replace section",
+			src: `{
+	"name": "app", /* name */
+	"remoteEnv": { "OLD": "value", "NESTED": "{}" },
+	"customizations": {"remoteEnv": "not top-level"}
+}
+`,
+			section: DevcontainerRemoteEnv,
+			want: `{
+	"name": "app", /* name */
+	"remoteEnv": {
+		"API_TOKEN": "${localEnv:API_TOKEN}",
+		"PORT": "8080"
+	},
+	"customizations": {"remoteEnv": "not top-level"}
+}
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Devcontainer([]byte(tt.src), envs, &schema.Schema{}, tt.section)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("want:\n%s\ngot:\n%s", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package gen
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/k1LoW/envdo/schema"
+)
+
+// Instructions of Dockerfile generation.
+const (
+	DockerfileEnv  = "env"
+	DockerfileArgs = "args"
+)
+
+// Dockerfile writes Dockerfile ENV or ARG instructions of the non-secret keys.
+func Dockerfile(w io.Writer, envs map[string]string, s *schema.Schema, as string) error {
+	var instruction string
+	switch as {
+	case DockerfileEnv:
+		instruction = "ENV"
+	case DockerfileArgs:
+		instruction = "ARG"
+	default:
+		return fmt.Errorf("unsupported instruction: %s (supported: %s, %s)", as, DockerfileEnv, DockerfileArgs)
+	}
+	_, _ = fmt.Fprintln(w, "# Code generated by envdo; DO NOT EDIT.")
+	for _, f := range fields(envs, s) {
+		if s.IsSecret(f.Key) {
+			continue
+		}
+		v, err := dockerfileQuote(envs[f.Key])
+		if err != nil {
+			return fmt.Errorf("failed to quote %s: %w", f.Key, err)
+		}
+		if _, err := fmt.Fprintf(w, "%s %s=%s\n", instruction, f.Key, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dockerfileQuote quotes a value for Dockerfile ENV and ARG instructions,
+// escaping characters that Dockerfile would expand or unescape.
+func dockerfileQuote(v string) (string, error) {
+	if strings.ContainsAny(v, "\r\n") {
+		return "", errors.New("multi-line values are not supported")
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`).Replace(v) + `"`, nil
+}
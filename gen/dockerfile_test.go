@@ -0,0 +1,56 @@
+package gen
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/k1LoW/envdo/schema"
+)
+
+func TestDockerfile(t *testing.T) {
+	envs := map[string]string{
+		"PORT":      "8080",
+		"API_TOKEN": "secret",
+		"GREETING":  `say "hi" to $USER`,
+	}
+	tests := []struct {
+		as      string
+		want    string
+		wantErr bool
+	}{
+		{
+			as: DockerfileEnv,
+			want: `# Code generated by envdo; DO NOT EDIT.
+ENV GREETING="say \"hi\" to \$USER"
+ENV PORT="8080"
+`,
+		},
+		{
+			as: DockerfileArgs,
+			want: `# Code generated by envdo; DO NOT EDIT.
+ARG GREETING="say \"hi\" to \$USER"
+ARG PORT="8080"
+`,
+		},
+		{
+			as:      "run",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		err := Dockerfile(&buf, envs, &schema.Schema{}, tt.as)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: want error but got none", tt.as)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := buf.String(); got != tt.want {
+			t.Errorf("%s: want:\n%s\ngot:\n%s", tt.as, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+// Package gen generates code and configuration from environment variables.
+package gen
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/k1LoW/envdo/schema"
+)
+
+var initialisms = []string{"API", "CPU", "DB", "DNS", "HTML", "HTTP", "HTTPS", "ID", "IP", "JSON", "JWT", "SQL", "SSH", "TCP", "TLS", "TTL", "UDP", "UI", "URI", "URL", "UUID", "XML"}
+
+var leadingZeroRe = regexp.MustCompile(`^[+-]?0\d`)
+
+// field represents an environment variable with its value type.
+type field struct {
+	Key  string
+	Type string
+}
+
+// fields returns the environment variables sorted by key with types from the schema,
+// inferring types from values for keys not typed in the schema.
+func fields(envs map[string]string, s *schema.Schema) []field {
+	keys := make([]string, 0, len(envs))
+	for k := range envs {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	fs := make([]field, 0, len(keys))
+	for _, k := range keys {
+		t := s.Type(k)
+		if t == "" {
+			t = inferType(envs[k])
+		}
+		fs = append(fs, field{Key: k, Type: t})
+	}
+	return fs
+}
+
+// inferType infers the type of value.
+func inferType(value string) string {
+	switch {
+	case value == "true" || value == "false":
+		return schema.TypeBool
+	case leadingZeroRe.MatchString(value):
+		// Values such as zip codes lose leading zeros when parsed as numbers
+		return schema.TypeString
+	}
+	if _, err := strconv.Atoi(value); err == nil {
+		return schema.TypeInt
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil && strings.Contains(value, ".") {
+		return schema.TypeFloat
+	}
+	return schema.TypeString
+}
+
+// camelCase converts an environment variable name such as DATABASE_URL to DatabaseURL.
+func camelCase(key string) string {
+	var b strings.Builder
+	for _, w := range strings.FieldsFunc(key, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+	}) {
+		u := strings.ToUpper(w)
+		if slices.Contains(initialisms, u) {
+			b.WriteString(u)
+			continue
+		}
+		b.WriteString(u[:1] + strings.ToLower(u[1:]))
+	}
+	s := b.String()
+	if s == "" || (s[0] >= '0' && s[0] <= '9') {
+		s = "Env" + s
+	}
+	return s
+}
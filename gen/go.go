@@ -0,0 +1,102 @@
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"strings"
+
+	"github.com/k1LoW/envdo/schema"
+)
+
+// Go writes Go source code of a Config struct with env tags and its loader.
+func Go(w io.Writer, envs map[string]string, s *schema.Schema, pkg string) error {
+	fs := fields(envs, s)
+	imports := map[string]bool{"os": true}
+	var body bytes.Buffer
+
+	_, _ = fmt.Fprintln(&body, "// Config represents the environment variables.")
+	_, _ = fmt.Fprintln(&body, "type Config struct {")
+	for _, f := range fs {
+		if d := s.Key(f.Key); d != nil && d.Description != "" {
+			_, _ = fmt.Fprintf(&body, "\t// %s\n", strings.ReplaceAll(d.Description, "\n", " "))
+		}
+		_, _ = fmt.Fprintf(&body, "\t%s %s `env:%q`\n", camelCase(f.Key), goType(f.Type), f.Key)
+	}
+	_, _ = fmt.Fprintln(&body, "}")
+	_, _ = fmt.Fprintln(&body)
+	_, _ = fmt.Fprintln(&body, "// Load loads Config from the environment variables.")
+	_, _ = fmt.Fprintln(&body, "func Load() (*Config, error) {")
+	_, _ = fmt.Fprintln(&body, "\tc := &Config{}")
+	for _, f := range fs {
+		name := camelCase(f.Key)
+		_, _ = fmt.Fprintf(&body, "\tif v, ok := os.LookupEnv(%q); ok {\n", f.Key)
+		switch f.Type {
+		case schema.TypeInt:
+			imports["strconv"] = true
+			writeGoParse(&body, f.Key, name, "strconv.Atoi(v)")
+		case schema.TypeFloat:
+			imports["strconv"] = true
+			writeGoParse(&body, f.Key, name, "strconv.ParseFloat(v, 64)")
+		case schema.TypeBool:
+			imports["strconv"] = true
+			writeGoParse(&body, f.Key, name, "strconv.ParseBool(v)")
+		case schema.TypeDuration:
+			imports["time"] = true
+			writeGoParse(&body, f.Key, name, "time.ParseDuration(v)")
+		default:
+			_, _ = fmt.Fprintf(&body, "\t\tc.%s = v\n", name)
+		}
+		_, _ = fmt.Fprintln(&body, "\t}")
+	}
+	_, _ = fmt.Fprintln(&body, "\treturn c, nil")
+	_, _ = fmt.Fprintln(&body, "}")
+
+	var src bytes.Buffer
+	_, _ = fmt.Fprintln(&src, "// Code generated by envdo; DO NOT EDIT.")
+	_, _ = fmt.Fprintln(&src)
+	_, _ = fmt.Fprintf(&src, "package %s\n\n", pkg)
+	if imports["strconv"] || imports["time"] {
+		imports["fmt"] = true
+	}
+	_, _ = fmt.Fprintln(&src, "import (")
+	for _, i := range []string{"fmt", "os", "strconv", "time"} {
+		if imports[i] {
+			_, _ = fmt.Fprintf(&src, "\t%q\n", i)
+		}
+	}
+	_, _ = fmt.Fprintln(&src, ")")
+	_, _ = fmt.Fprintln(&src)
+	_, _ = body.WriteTo(&src)
+
+	b, err := format.Source(src.Bytes())
+	if err != nil {
+		return fmt.Errorf("failed to format generated code: %w", err)
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+func writeGoParse(w io.Writer, key, name, parse string) {
+	_, _ = fmt.Fprintf(w, "\t\tp, err := %s\n", parse)
+	_, _ = fmt.Fprintln(w, "\t\tif err != nil {")
+	_, _ = fmt.Fprintf(w, "\t\t\treturn nil, fmt.Errorf(\"invalid %s: %%w\", err)\n", key)
+	_, _ = fmt.Fprintln(w, "\t\t}")
+	_, _ = fmt.Fprintf(w, "\t\tc.%s = p\n", name)
+}
+
+func goType(t string) string {
+	switch t {
+	case schema.TypeInt:
+		return "int"
+	case schema.TypeFloat:
+		return "float64"
+	case schema.TypeBool:
+		return "bool"
+	case schema.TypeDuration:
+		return "time.Duration"
+	default:
+		return "string"
+	}
+}
@@ -0,0 +1,61 @@
+package gen
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/k1LoW/envdo/schema"
+)
+
+func TestGo(t *testing.T) {
+	envs := map[string]string{
+		"DATABASE_URL": "postgres://localhost/db",
+		"PORT":         "8080",
+		"DEBUG":        "true",
+		"ZIP_CODE":     "01234",
+		"TIMEOUT":      "30s",
+	}
+	s := &schema.Schema{
+		Keys: map[string]*schema.Key{
+			"TIMEOUT": {Type: schema.TypeDuration, Description: "request timeout"},
+		},
+	}
+	var buf bytes.Buffer
+	if err := Go(&buf, envs, s, "config"); err != nil {
+		t.Fatal(err)
+	}
+	// Normalize whitespace to ignore gofmt alignment
+	got := strings.Join(strings.Fields(buf.String()), " ")
+	for _, want := range []string{
+		"package config",
+		"DatabaseURL string `env:\"DATABASE_URL\"`",
+		"Debug bool `env:\"DEBUG\"`",
+		"Port int `env:\"PORT\"`",
+		"// request timeout Timeout time.Duration `env:\"TIMEOUT\"`",
+		"ZipCode string `env:\"ZIP_CODE\"`",
+		`p, err := time.ParseDuration(v)`,
+		`return nil, fmt.Errorf("invalid PORT: %w", err)`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("generated code does not contain %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestCamelCase(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"DATABASE_URL", "DatabaseURL"},
+		{"api_key", "APIKey"},
+		{"PORT", "Port"},
+		{"1PASSWORD_TOKEN", "Env1passwordToken"},
+	}
+	for _, tt := range tests {
+		if got := camelCase(tt.in); got != tt.want {
+			t.Errorf("camelCase(%q): want %q, got %q", tt.in, tt.want, got)
+		}
+	}
+}
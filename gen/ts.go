@@ -0,0 +1,79 @@
+package gen
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/k1LoW/envdo/schema"
+)
+
+var tsIdentRe = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// TypeScript writes a TypeScript declaration file declaring the keys of process.env.
+func TypeScript(w io.Writer, envs map[string]string, s *schema.Schema) error {
+	_, _ = fmt.Fprintln(w, "// Code generated by envdo; DO NOT EDIT.")
+	_, _ = fmt.Fprintln(w)
+	_, _ = fmt.Fprintln(w, "declare global {")
+	_, _ = fmt.Fprintln(w, "  namespace NodeJS {")
+	_, _ = fmt.Fprintln(w, "    interface ProcessEnv {")
+	for _, f := range fields(envs, s) {
+		writeTSDoc(w, s, f.Key, "      ")
+		_, _ = fmt.Fprintf(w, "      %s: string;\n", tsKey(f.Key))
+	}
+	_, _ = fmt.Fprintln(w, "    }")
+	_, _ = fmt.Fprintln(w, "  }")
+	_, _ = fmt.Fprintln(w, "}")
+	_, _ = fmt.Fprintln(w)
+	_, err := fmt.Fprintln(w, "export {};")
+	return err
+}
+
+// Zod writes a TypeScript module exporting a zod schema of the environment variables
+// and the environment parsed with it.
+func Zod(w io.Writer, envs map[string]string, s *schema.Schema) error {
+	_, _ = fmt.Fprintln(w, "// Code generated by envdo; DO NOT EDIT.")
+	_, _ = fmt.Fprintln(w)
+	_, _ = fmt.Fprintln(w, `import { z } from "zod";`)
+	_, _ = fmt.Fprintln(w)
+	_, _ = fmt.Fprintln(w, "export const envSchema = z.object({")
+	for _, f := range fields(envs, s) {
+		writeTSDoc(w, s, f.Key, "  ")
+		_, _ = fmt.Fprintf(w, "  %s: %s,\n", tsKey(f.Key), zodType(f.Type))
+	}
+	_, _ = fmt.Fprintln(w, "});")
+	_, _ = fmt.Fprintln(w)
+	_, _ = fmt.Fprintln(w, "export type Env = z.infer<typeof envSchema>;")
+	_, _ = fmt.Fprintln(w)
+	_, err := fmt.Fprintln(w, "export const env = envSchema.parse(process.env);")
+	return err
+}
+
+func writeTSDoc(w io.Writer, s *schema.Schema, key, indent string) {
+	if d := s.Key(key); d != nil && d.Description != "" {
+		_, _ = fmt.Fprintf(w, "%s/** %s */\n", indent, strings.ReplaceAll(d.Description, "*/", "* /"))
+	}
+}
+
+func tsKey(key string) string {
+	if tsIdentRe.MatchString(key) {
+		return key
+	}
+	return fmt.Sprintf("%q", key)
+}
+
+func zodType(t string) string {
+	switch t {
+	case schema.TypeInt:
+		return "z.coerce.number().int()"
+	case schema.TypeFloat:
+		return "z.coerce.number()"
+	case schema.TypeBool:
+		return `z.enum(["true", "false"]).transform((v) => v === "true")`
+	case schema.TypeURL:
+		return "z.string().url()"
+	default:
+		return "z.string()"
+	}
+}
@@ -0,0 +1,57 @@
+package gen
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/k1LoW/envdo/schema"
+)
+
+func TestTypeScript(t *testing.T) {
+	envs := map[string]string{
+		"API_URL":  "https://example.com",
+		"my-key":   "value",
+		"PORT":     "3000",
+		"FEATURES": "a,b",
+	}
+	s := &schema.Schema{
+		Keys: map[string]*schema.Key{
+			"API_URL": {Type: schema.TypeURL, Description: "API endpoint"},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := TypeScript(&buf, envs, s); err != nil {
+		t.Fatal(err)
+	}
+	got := buf.String()
+	for _, want := range []string{
+		"interface ProcessEnv {",
+		"      /** API endpoint */\n      API_URL: string;",
+		"      PORT: string;",
+		`      "my-key": string;`,
+		"export {};",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("generated code does not contain %q:\n%s", want, got)
+		}
+	}
+
+	buf.Reset()
+	if err := Zod(&buf, envs, s); err != nil {
+		t.Fatal(err)
+	}
+	got = buf.String()
+	for _, want := range []string{
+		`import { z } from "zod";`,
+		"  API_URL: z.string().url(),",
+		"  FEATURES: z.string(),",
+		"  PORT: z.coerce.number().int(),",
+		"export const env = envSchema.parse(process.env);",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("generated code does not contain %q:\n%s", want, got)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+// Package gitsync stores encrypted profiles in a git repository shared by a team.
+//
+// The repository layout is:
+//
+//	recipients.txt           age public keys of the team (one per line)
+//	profiles/<name>.env.age  encrypted profiles
+//	profiles/<name>.recipients  age public keys for the profile (optional, overrides recipients.txt)
+package gitsync
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"filippo.io/age"
+	"github.com/k1LoW/envdo/crypt"
+	"github.com/k1LoW/envdo/env"
+	"github.com/k1LoW/exec"
+)
+
+const (
+	recipientsFile = "recipients.txt"
+	profilesDir    = "profiles"
+	defaultProfile = "default"
+)
+
+// Repo is a local clone of a git repository storing encrypted profiles.
+type Repo struct {
+	url string
+	dir string
+}
+
+// New creates a new Repo for the repository url cloned under cacheDir.
+func New(url, cacheDir string) *Repo {
+	sum := sha256.Sum256([]byte(url))
+	return &Repo{
+		url: url,
+		dir: filepath.Join(cacheDir, "envdo", "sync", hex.EncodeToString(sum[:])[:16]),
+	}
+}
+
+// Pull clones the repository, or pulls the latest changes if already cloned.
+func (r *Repo) Pull(ctx context.Context) error {
+	if _, err := os.Stat(filepath.Join(r.dir, ".git")); err != nil {
+		if err := os.MkdirAll(filepath.Dir(r.dir), 0700); err != nil {
+			return err
+		}
+		return git(ctx, "", "clone", "--quiet", r.url, r.dir)
+	}
+	if err := git(ctx, r.dir, "rev-parse", "--verify", "--quiet", "HEAD"); err != nil {
+		// Empty repository
+		return nil
+	}
+	return git(ctx, r.dir, "pull", "--quiet", "--ff-only")
+}
+
+// Push commits the changes and pushes them.
+func (r *Repo) Push(ctx context.Context, message string) error {
+	if err := git(ctx, r.dir, "add", "--all"); err != nil {
+		return err
+	}
+	if err := git(ctx, r.dir, "diff", "--cached", "--quiet"); err == nil {
+		// Nothing to commit
+		return nil
+	}
+	if err := git(ctx, r.dir, "commit", "--quiet", "-m", message); err != nil {
+		return err
+	}
+	return git(ctx, r.dir, "push", "--quiet", "origin", "HEAD")
+}
+
+// Recipients returns the age public keys for the profile.
+func (r *Repo) Recipients(profile string) ([]string, error) {
+	keys, err := readRecipients(filepath.Join(r.dir, profilesDir, profileName(profile)+".recipients"))
+	if err == nil {
+		return keys, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+	keys, err = readRecipients(filepath.Join(r.dir, recipientsFile))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// SetRecipients writes the age public keys of the team.
+func (r *Repo) SetRecipients(keys []string) error {
+	if _, err := crypt.ParseRecipients(keys); err != nil {
+		return err
+	}
+	return env.WriteFile(filepath.Join(r.dir, recipientsFile), []byte(strings.Join(keys, "\n")+"\n"))
+}
+
+// Profiles returns the names of profiles stored in the repository.
+func (r *Repo) Profiles() ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(r.dir, profilesDir, "*.env.age"))
+	if err != nil {
+		return nil, err
+	}
+	profiles := make([]string, 0, len(files))
+	for _, f := range files {
+		name := strings.TrimSuffix(filepath.Base(f), ".env.age")
+		if name == defaultProfile {
+			name = ""
+		}
+		profiles = append(profiles, name)
+	}
+	slices.Sort(profiles)
+	return profiles, nil
+}
+
+// ReadProfile decrypts the profile stored in the repository with identities.
+func (r *Repo) ReadProfile(profile string, identities []age.Identity) ([]byte, error) {
+	b, err := os.ReadFile(r.profilePath(profile))
+	if err != nil {
+		return nil, err
+	}
+	return crypt.Decrypt(b, identities)
+}
+
+// WriteProfile encrypts content to the recipients of the profile and stores it in the repository.
+func (r *Repo) WriteProfile(profile string, content []byte) error {
+	keys, err := r.Recipients(profile)
+	if err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		return fmt.Errorf("no recipients for profile %q: add age public keys to %s", profile, recipientsFile)
+	}
+	recipients, err := crypt.ParseRecipients(keys)
+	if err != nil {
+		return err
+	}
+	ciphertext, err := crypt.Encrypt(content, recipients)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Join(r.dir, profilesDir), 0700); err != nil {
+		return err
+	}
+	return env.WriteFile(r.profilePath(profile), ciphertext)
+}
+
+// Rekey re-encrypts all profiles to their current recipients.
+func (r *Repo) Rekey(identities []age.Identity) error {
+	profiles, err := r.Profiles()
+	if err != nil {
+		return err
+	}
+	for _, p := range profiles {
+		content, err := r.ReadProfile(p, identities)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt profile %q: %w", p, err)
+		}
+		if err := r.WriteProfile(p, content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Repo) profilePath(profile string) string {
+	return filepath.Join(r.dir, profilesDir, profileName(profile)+".env.age")
+}
+
+func profileName(profile string) string {
+	if profile == "" {
+		return defaultProfile
+	}
+	return profile
+}
+
+// readRecipients reads age public keys from a file, ignoring blank lines and comments.
+func readRecipients(path string) ([]string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var keys []string
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		keys = append(keys, line)
+	}
+	return keys, scanner.Err()
+}
+
+// git runs git in dir.
+func git(ctx context.Context, dir string, args ...string) error {
+	if dir != "" {
+		args = append([]string{"-C", dir}, args...)
+	}
+	c := exec.CommandContext(ctx, "git", args...)
+	var stderr bytes.Buffer
+	c.Stderr = &stderr
+	if err := c.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && stderr.Len() == 0 {
+			return err
+		}
+		return fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package gitsync
+
+import (
+	"context"
+	"os/exec"
+	"slices"
+	"testing"
+
+	"filippo.io/age"
+)
+
+func TestPushPull(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+	t.Setenv("GIT_AUTHOR_NAME", "envdo")
+	t.Setenv("GIT_AUTHOR_EMAIL", "envdo@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "envdo")
+	t.Setenv("GIT_COMMITTER_EMAIL", "envdo@example.com")
+	ctx := context.Background()
+	remote := t.TempDir()
+	if err := git(ctx, "", "init", "--quiet", "--bare", remote); err != nil {
+		t.Fatal(err)
+	}
+	alice, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := New(remote, t.TempDir())
+	if err := a.Pull(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.WriteProfile("staging", []byte("API_KEY=secret\n")); err == nil {
+		t.Error("want error without recipients")
+	}
+	if err := a.SetRecipients([]string{alice.Recipient().String()}); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.WriteProfile("staging", []byte("API_KEY=secret\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Push(ctx, "Update staging"); err != nil {
+		t.Fatal(err)
+	}
+
+	b := New(remote, t.TempDir())
+	if err := b.Pull(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.ReadProfile("staging", []age.Identity{bob}); err == nil {
+		t.Error("want error for a non-recipient")
+	}
+
+	// Alice adds Bob and re-encrypts
+	if err := a.SetRecipients([]string{alice.Recipient().String(), bob.Recipient().String()}); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Rekey([]age.Identity{alice}); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Push(ctx, "Add recipient"); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Pull(ctx); err != nil {
+		t.Fatal(err)
+	}
+	got, err := b.ReadProfile("staging", []age.Identity{bob})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "API_KEY=secret\n" {
+		t.Errorf("got %q", got)
+	}
+	profiles, err := b.Profiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(profiles, []string{"staging"}) {
+		t.Errorf("got %v", profiles)
+	}
+	keys, err := b.Recipients("staging")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 2 {
+		t.Errorf("got %v", keys)
+	}
+}
@@ -3,11 +3,18 @@ module github.com/k1LoW/envdo
 go 1.24.6
 
 require (
+	filippo.io/age v1.2.1
+	github.com/BurntSushi/toml v1.6.0
+	github.com/fsnotify/fsnotify v1.9.0
+	github.com/goccy/go-yaml v1.18.0
 	github.com/k1LoW/exec v0.4.0
 	github.com/spf13/cobra v1.9.1
+	golang.org/x/sys v0.21.0
+	golang.org/x/term v0.21.0
 )
 
 require (
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
 	github.com/spf13/pflag v1.0.6 // indirect
+	golang.org/x/crypto v0.24.0 // indirect
 )
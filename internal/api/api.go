@@ -0,0 +1,61 @@
+// Package api provides the helpers shared by the clients of the HTTP APIs and CLIs of external services
+// (secret managers, clouds and hosting platforms).
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/k1LoW/exec"
+)
+
+// Timeout is the timeout of requests to the HTTP APIs, so a stuck endpoint never hangs envdo.
+const Timeout = 30 * time.Second
+
+// ErrCLINotFound is returned by RunCLI if the command is not found.
+var ErrCLINotFound = errors.New("install it and configure the credentials")
+
+// NewClient returns an HTTP client with Timeout.
+func NewClient() *http.Client {
+	return &http.Client{Timeout: Timeout}
+}
+
+// DoJSON sends req with client and decodes the JSON response into v, unless v is nil.
+func DoJSON(client *http.Client, req *http.Request, v any) error {
+	req.Header.Set("Accept", "application/json")
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		b, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return fmt.Errorf("%s %s: %s: %s", req.Method, req.URL.Path, res.Status, b)
+	}
+	if v == nil {
+		return nil
+	}
+	return json.NewDecoder(res.Body).Decode(v)
+}
+
+// RunCLI runs the command of a CLI and returns its stdout.
+func RunCLI(ctx context.Context, name string, args ...string) ([]byte, error) {
+	if _, err := exec.LookPath(name); err != nil {
+		return nil, fmt.Errorf("%s is not found: %w", name, ErrCLINotFound)
+	}
+	c := exec.CommandContext(ctx, name, args...)
+	var stderr bytes.Buffer
+	c.Stderr = &stderr
+	out, err := c.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w: %s", name, strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDoJSON(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+			if got := r.Header.Get("Accept"); got != "application/json" {
+				t.Errorf("got Accept %q", got)
+			}
+			_, _ = w.Write([]byte(`{"value":"v"}`))
+		case "/slow":
+			time.Sleep(500 * time.Millisecond)
+		default:
+			http.Error(w, "denied", http.StatusForbidden)
+		}
+	}))
+	defer ts.Close()
+	do := func(client *http.Client, path string, v any) error {
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, ts.URL+path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return DoJSON(client, req, v)
+	}
+
+	var res struct {
+		Value string `json:"value"`
+	}
+	if err := do(NewClient(), "/ok", &res); err != nil || res.Value != "v" {
+		t.Errorf("got %v, %q", err, res.Value)
+	}
+	if err := do(NewClient(), "/ok", nil); err != nil {
+		t.Errorf("got %v, want nil", err)
+	}
+	if err := do(NewClient(), "/denied", &res); err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("got %v, want 403", err)
+	}
+	// A stuck endpoint fails with the timeout of the client
+	client := NewClient()
+	client.Timeout = 50 * time.Millisecond
+	if err := do(client, "/slow", &res); err == nil {
+		t.Error("got nil, want timeout")
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	if got := NewClient().Timeout; got != Timeout {
+		t.Errorf("got %s, want %s", got, Timeout)
+	}
+}
+
+func TestRunCLINotFound(t *testing.T) {
+	_, err := RunCLI(context.Background(), "envdo-test-cli-not-found")
+	if !errors.Is(err, ErrCLINotFound) {
+		t.Errorf("got %v, want ErrCLINotFound", err)
+	}
+}
@@ -0,0 +1,62 @@
+package output
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/k1LoW/envdo/env"
+)
+
+// WriteEnvFile writes envs in KEY=value form to be appended to an env file such as $GITHUB_ENV.
+// Multiline values are written in the heredoc form (KEY<<DELIMITER) with a random delimiter.
+// Keys are written in the order of order, followed by the others sorted.
+func WriteEnvFile(w io.Writer, envs map[string]string, order []string) error {
+	for _, k := range env.OrderedKeys(envs, order) {
+		v := envs[k]
+		if !strings.ContainsAny(v, "\r\n") {
+			if _, err := fmt.Fprintf(w, "%s=%s\n", k, v); err != nil {
+				return err
+			}
+			continue
+		}
+		delimiter, err := heredocDelimiter()
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "%s<<%s\n%s\n%s\n", k, delimiter, v, delimiter); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteGitHubMasks writes workflow commands to mask values of the keys masked by m in GitHub Actions logs.
+func WriteGitHubMasks(w io.Writer, envs map[string]string, m *Masker) error {
+	for _, k := range sortedKeys(envs) {
+		if !m.IsMasked(k) {
+			continue
+		}
+		for line := range strings.Lines(envs[k]) {
+			line = strings.TrimRight(line, "\r\n")
+			if line == "" {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "::add-mask::%s\n", line); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// heredocDelimiter returns a random delimiter of the heredoc form.
+func heredocDelimiter() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "ENVDO_EOF_" + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,46 @@
+package output
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+
+	"github.com/k1LoW/envdo/schema"
+)
+
+func TestWriteEnvFile(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteEnvFile(&buf, map[string]string{
+		"CERT": "line1\nline2",
+		"PORT": "8080",
+	}, nil); err != nil {
+		t.Fatal(err)
+	}
+	re := regexp.MustCompile(`^CERT<<(ENVDO_EOF_[0-9a-f]{32})\nline1\nline2\n(ENVDO_EOF_[0-9a-f]{32})\nPORT=8080\n$`)
+	m := re.FindStringSubmatch(buf.String())
+	if m == nil || m[1] != m[2] {
+		t.Errorf("got %q", buf.String())
+	}
+}
+
+func TestWriteGitHubMasks(t *testing.T) {
+	// DATABASE_URL is masked only by the mask pattern
+	m, err := NewMasker(&schema.Schema{}, []string{`^DATABASE_URL$`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := WriteGitHubMasks(&buf, map[string]string{
+		"API_TOKEN":    "secret",
+		"DATABASE_URL": "postgres://user:pass@db/app",
+		"PRIVATE_KEY":  "-----BEGIN-----\nabc\n",
+		"EMPTY_TOKEN":  "",
+		"PORT":         "8080",
+	}, m); err != nil {
+		t.Fatal(err)
+	}
+	want := "::add-mask::secret\n::add-mask::postgres://user:pass@db/app\n::add-mask::-----BEGIN-----\n::add-mask::abc\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,57 @@
+package output
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/k1LoW/envdo/schema"
+)
+
+// MaskedValue replaces values of secret keys.
+const MaskedValue = "***"
+
+// Masker masks values of secret keys.
+type Masker struct {
+	schema   *schema.Schema
+	patterns []*regexp.Regexp
+}
+
+// NewMasker creates a new Masker that masks keys classified as secrets by s
+// (keys containing TOKEN, SECRET, PASSWORD, KEY and so on, unless overridden in the schema)
+// and keys matching any of patterns.
+func NewMasker(s *schema.Schema, patterns []string) (*Masker, error) {
+	m := &Masker{schema: s}
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid mask pattern %q: %w", p, err)
+		}
+		m.patterns = append(m.patterns, re)
+	}
+	return m, nil
+}
+
+// IsMasked reports whether the value of key is masked.
+func (m *Masker) IsMasked(key string) bool {
+	if m.schema.IsSecret(key) {
+		return true
+	}
+	for _, re := range m.patterns {
+		if re.MatchString(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// Mask returns a copy of envs with values of secret keys replaced with MaskedValue.
+func (m *Masker) Mask(envs map[string]string) map[string]string {
+	masked := make(map[string]string, len(envs))
+	for k, v := range envs {
+		if m.IsMasked(k) {
+			v = MaskedValue
+		}
+		masked[k] = v
+	}
+	return masked
+}
@@ -0,0 +1,343 @@
+// Package output writes environment variables in various formats.
+package output
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/k1LoW/envdo/env"
+	"github.com/k1LoW/envdo/schema"
+)
+
+// Formats of output.
+const (
+	FormatExport       = "export"
+	FormatK8sConfigMap = "k8s-configmap"
+	FormatK8sSecret    = "k8s-secret"
+	FormatDockerEnv    = "docker-env"
+	FormatCompose      = "compose"
+	FormatTfvars       = "tfvars"
+	FormatAzure        = "azure-pipelines"
+	FormatProperties   = "properties"
+	FormatMake         = "make"
+	FormatJSON         = "json"
+	FormatDotenv       = "dotenv"
+)
+
+// Formats are the names of all supported formats.
+var Formats = []string{FormatExport, FormatK8sConfigMap, FormatK8sSecret, FormatDockerEnv, FormatCompose, FormatTfvars, FormatAzure, FormatProperties, FormatMake, FormatJSON, FormatDotenv}
+
+var yamlPlainRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+
+// yamlReserved are words that YAML 1.1 parsers do not read as strings.
+var yamlReserved = []string{"y", "yes", "n", "no", "true", "false", "on", "off", "null"}
+
+// Options are options of output.
+type Options struct {
+	// Name is the name of the resource for manifest formats.
+	Name string
+	// Schema is used to classify secret keys.
+	Schema *schema.Schema
+	// Shell is the shell of the export format (bash by default).
+	Shell string
+	// Order is the order of keys to write, such as env.DefinitionOrder of the loaded .env files.
+	// Keys not in Order are written after them sorted by key. Keys are sorted by default.
+	Order []string
+}
+
+// Write writes envs to w in the format.
+func Write(w io.Writer, format string, envs map[string]string, opts Options) error {
+	keys := env.OrderedKeys(envs, opts.Order)
+	switch format {
+	case FormatExport, "":
+		return writeExport(w, envs, keys, opts.Shell)
+	case FormatK8sConfigMap:
+		return writeK8sConfigMap(w, envs, keys, opts)
+	case FormatK8sSecret:
+		return writeK8sSecret(w, envs, keys, opts)
+	case FormatDockerEnv:
+		return writeDockerEnv(w, envs, keys)
+	case FormatCompose:
+		return writeCompose(w, envs, keys, opts)
+	case FormatTfvars:
+		return writeTfvars(w, envs, keys)
+	case FormatAzure:
+		return writeAzure(w, envs, keys, opts)
+	case FormatProperties:
+		return writeProperties(w, envs, keys)
+	case FormatMake:
+		return writeMake(w, envs, keys)
+	case FormatJSON:
+		return writeJSON(w, envs, keys)
+	case FormatDotenv:
+		return writeDotenv(w, envs, keys)
+	default:
+		return fmt.Errorf("unsupported format: %s (supported: %s)", format, strings.Join(Formats, ", "))
+	}
+}
+
+// OmittedKeys returns the keys of envs not written in the format sorted: the secret keys in the k8s-configmap format
+// and the other keys in the k8s-secret format, which are written in the other format.
+func OmittedKeys(format string, envs map[string]string, s *schema.Schema) []string {
+	var omitted []string
+	for _, k := range sortedKeys(envs) {
+		if (format == FormatK8sConfigMap && s.IsSecret(k)) || (format == FormatK8sSecret && !s.IsSecret(k)) {
+			omitted = append(omitted, k)
+		}
+	}
+	return omitted
+}
+
+// writeK8sConfigMap writes a ConfigMap manifest of the non-secret keys.
+func writeK8sConfigMap(w io.Writer, envs map[string]string, keys []string, opts Options) error {
+	if opts.Name == "" {
+		return errors.New("--name is required for the k8s-configmap format")
+	}
+	_, _ = fmt.Fprintln(w, "apiVersion: v1")
+	_, _ = fmt.Fprintln(w, "kind: ConfigMap")
+	_, _ = fmt.Fprintln(w, "metadata:")
+	_, _ = fmt.Fprintf(w, "  name: %s\n", yamlKey(opts.Name))
+	keys = slices.DeleteFunc(slices.Clone(keys), opts.Schema.IsSecret)
+	if len(keys) == 0 {
+		_, err := fmt.Fprintln(w, "data: {}")
+		return err
+	}
+	_, _ = fmt.Fprintln(w, "data:")
+	for _, k := range keys {
+		if _, err := fmt.Fprintf(w, "  %s: %s\n", yamlKey(k), yamlString(envs[k])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeK8sSecret writes a Secret manifest of the secret keys with base64-encoded values.
+// Together with the k8s-configmap format, all keys are written.
+func writeK8sSecret(w io.Writer, envs map[string]string, keys []string, opts Options) error {
+	if opts.Name == "" {
+		return errors.New("--name is required for the k8s-secret format")
+	}
+	_, _ = fmt.Fprintln(w, "apiVersion: v1")
+	_, _ = fmt.Fprintln(w, "kind: Secret")
+	_, _ = fmt.Fprintln(w, "metadata:")
+	_, _ = fmt.Fprintf(w, "  name: %s\n", yamlKey(opts.Name))
+	_, _ = fmt.Fprintln(w, "type: Opaque")
+	keys = slices.DeleteFunc(slices.Clone(keys), func(k string) bool { return !opts.Schema.IsSecret(k) })
+	if len(keys) == 0 {
+		_, err := fmt.Fprintln(w, "data: {}")
+		return err
+	}
+	_, _ = fmt.Fprintln(w, "data:")
+	for _, k := range keys {
+		if _, err := fmt.Fprintf(w, "  %s: %s\n", yamlKey(k), base64.StdEncoding.EncodeToString([]byte(envs[k]))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeDockerEnv writes a file for docker run --env-file, which takes values literally without quotes.
+func writeDockerEnv(w io.Writer, envs map[string]string, keys []string) error {
+	for _, k := range keys {
+		v := envs[k]
+		if strings.ContainsAny(v, "\r\n") {
+			return fmt.Errorf("multi-line value of %s is not supported in the docker-env format", k)
+		}
+		if _, err := fmt.Fprintf(w, "%s=%s\n", k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeCompose writes the environment of the service of a Docker Compose file.
+// "$" is escaped as "$$" so that Compose does not interpolate values.
+func writeCompose(w io.Writer, envs map[string]string, keys []string, opts Options) error {
+	if opts.Name == "" {
+		return errors.New("--name is required for the compose format")
+	}
+	_, _ = fmt.Fprintln(w, "services:")
+	_, _ = fmt.Fprintf(w, "  %s:\n", yamlKey(opts.Name))
+	if len(envs) == 0 {
+		_, err := fmt.Fprintln(w, "    environment: {}")
+		return err
+	}
+	_, _ = fmt.Fprintln(w, "    environment:")
+	for _, k := range keys {
+		if _, err := fmt.Fprintf(w, "      %s: %s\n", yamlKey(k), yamlString(strings.ReplaceAll(envs[k], "$", "$$"))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeTfvars writes a Terraform .tfvars file of string variables.
+func writeTfvars(w io.Writer, envs map[string]string, keys []string) error {
+	for _, k := range keys {
+		if _, err := fmt.Fprintf(w, "%s = %s\n", k, hclString(envs[k])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hclString returns s as an HCL quoted string. Template sequences (${ and %{) are escaped.
+func hclString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`, "${", "$${", "%{", "%%{").Replace(s) + `"`
+}
+
+// writeAzure writes Azure Pipelines logging commands setting variables.
+// Secret keys are set with issecret=true so that they are masked in logs.
+func writeAzure(w io.Writer, envs map[string]string, keys []string, opts Options) error {
+	for _, k := range keys {
+		props := "variable=" + azureEscapeProperty(k)
+		if opts.Schema.IsSecret(k) {
+			props += ";issecret=true"
+		}
+		if _, err := fmt.Fprintf(w, "##vso[task.setvariable %s]%s\n", props, azureEscapeData(envs[k])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// azureEscapeData escapes the data of an Azure Pipelines logging command.
+func azureEscapeData(s string) string {
+	return strings.NewReplacer("%", "%AZP25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// azureEscapeProperty escapes a property value of an Azure Pipelines logging command.
+func azureEscapeProperty(s string) string {
+	return strings.NewReplacer("%", "%AZP25", "\r", "%0D", "\n", "%0A", ";", "%3B", "]", "%5D").Replace(s)
+}
+
+// writeProperties writes a Java .properties file.
+func writeProperties(w io.Writer, envs map[string]string, keys []string) error {
+	for _, k := range keys {
+		if _, err := fmt.Fprintf(w, "%s=%s\n", propertiesEscape(k, true), propertiesEscape(envs[k], false)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// propertiesEscape escapes s like java.util.Properties#store does.
+// Non-ASCII characters are written as \uXXXX escapes so that the file is valid ISO 8859-1.
+func propertiesEscape(s string, isKey bool) string {
+	var b strings.Builder
+	for i, r := range s {
+		switch r {
+		case '\\':
+			b.WriteString(`\\`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '=', ':', '#', '!':
+			b.WriteRune('\\')
+			b.WriteRune(r)
+		case ' ':
+			if i == 0 || isKey {
+				b.WriteRune('\\')
+			}
+			b.WriteRune(r)
+		default:
+			if r < 0x20 || r > 0x7e {
+				for _, u := range utf16.Encode([]rune{r}) {
+					fmt.Fprintf(&b, `\u%04X`, u)
+				}
+				continue
+			}
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// writeMake writes `export KEY := value` lines to be included in Makefiles.
+func writeMake(w io.Writer, envs map[string]string, keys []string) error {
+	for _, k := range keys {
+		v := envs[k]
+		if strings.ContainsAny(v, "\r\n") {
+			return fmt.Errorf("multi-line value of %s is not supported in the make format", k)
+		}
+		v = strings.NewReplacer("$", "$$", "#", `\#`).Replace(v)
+		if _, err := fmt.Fprintf(w, "export %s := %s\n", k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeJSON writes envs as an indented JSON object in the order of keys.
+func writeJSON(w io.Writer, envs map[string]string, keys []string) error {
+	if len(keys) == 0 {
+		_, err := fmt.Fprintln(w, "{}")
+		return err
+	}
+	var b strings.Builder
+	b.WriteString("{\n")
+	for i, k := range keys {
+		kb, err := json.Marshal(k)
+		if err != nil {
+			return err
+		}
+		vb, err := json.Marshal(envs[k])
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&b, "  %s: %s", kb, vb)
+		if i < len(keys)-1 {
+			b.WriteByte(',')
+		}
+		b.WriteByte('\n')
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeDotenv writes envs in .env format, which envdo --env-file and other dotenv tools load.
+func writeDotenv(w io.Writer, envs map[string]string, keys []string) error {
+	b, err := env.MarshalKeys(envs, keys)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+// yamlKey returns s as a plain YAML scalar if possible, otherwise as a double-quoted scalar.
+func yamlKey(s string) string {
+	if yamlPlainRe.MatchString(s) && !slices.Contains(yamlReserved, strings.ToLower(s)) {
+		return s
+	}
+	return yamlString(s)
+}
+
+// yamlString returns s as a YAML double-quoted scalar.
+func yamlString(s string) string {
+	// JSON strings are valid YAML double-quoted scalars
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+func sortedKeys(envs map[string]string) []string {
+	keys := make([]string, 0, len(envs))
+	for k := range envs {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
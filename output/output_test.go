@@ -0,0 +1,286 @@
+package output
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+
+	"github.com/k1LoW/envdo/schema"
+)
+
+func TestWrite(t *testing.T) {
+	no := false
+	envs := map[string]string{
+		"PORT":         "8080",
+		"API_TOKEN":    "secret",
+		"PUBLIC_KEY":   "ssh-ed25519 AAAA",
+		"DATABASE_URL": "postgres://localhost/db",
+	}
+	s := &schema.Schema{Keys: map[string]*schema.Key{"PUBLIC_KEY": {Secret: &no}}}
+
+	tests := []struct {
+		name    string
+		format  string
+		opts    Options
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "export",
+			format: FormatExport,
+			opts:   Options{Shell: ShellBash},
+			want: `export API_TOKEN=secret
+export DATABASE_URL=postgres://localhost/db
+export PORT=8080
+export PUBLIC_KEY='ssh-ed25519 AAAA'
+`,
+		},
+		{
+			name:   "k8s-configmap",
+			format: FormatK8sConfigMap,
+			opts:   Options{Name: "app-config", Schema: s},
+			want: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+data:
+  DATABASE_URL: "postgres://localhost/db"
+  PORT: "8080"
+  PUBLIC_KEY: "ssh-ed25519 AAAA"
+`,
+		},
+		{
+			name:   "k8s-secret",
+			format: FormatK8sSecret,
+			opts:   Options{Name: "app-secret", Schema: s},
+			want: `apiVersion: v1
+kind: Secret
+metadata:
+  name: app-secret
+type: Opaque
+data:
+  API_TOKEN: c2VjcmV0
+`,
+		},
+		{
+			name:   "docker-env",
+			format: FormatDockerEnv,
+			want: `API_TOKEN=secret
+DATABASE_URL=postgres://localhost/db
+PORT=8080
+PUBLIC_KEY=ssh-ed25519 AAAA
+`,
+		},
+		{
+			name:   "compose",
+			format: FormatCompose,
+			opts:   Options{Name: "app"},
+			want: `services:
+  app:
+    environment:
+      API_TOKEN: "secret"
+      DATABASE_URL: "postgres://localhost/db"
+      PORT: "8080"
+      PUBLIC_KEY: "ssh-ed25519 AAAA"
+`,
+		},
+		{
+			name:   "tfvars",
+			format: FormatTfvars,
+			want: `API_TOKEN = "secret"
+DATABASE_URL = "postgres://localhost/db"
+PORT = "8080"
+PUBLIC_KEY = "ssh-ed25519 AAAA"
+`,
+		},
+		{
+			name:   "azure-pipelines",
+			format: FormatAzure,
+			opts:   Options{Schema: s},
+			want: `##vso[task.setvariable variable=API_TOKEN;issecret=true]secret
+##vso[task.setvariable variable=DATABASE_URL]postgres://localhost/db
+##vso[task.setvariable variable=PORT]8080
+##vso[task.setvariable variable=PUBLIC_KEY]ssh-ed25519 AAAA
+`,
+		},
+		{
+			name:   "properties",
+			format: FormatProperties,
+			want: `API_TOKEN=secret
+DATABASE_URL=postgres\://localhost/db
+PORT=8080
+PUBLIC_KEY=ssh-ed25519 AAAA
+`,
+		},
+		{
+			name:   "make",
+			format: FormatMake,
+			want: `export API_TOKEN := secret
+export DATABASE_URL := postgres://localhost/db
+export PORT := 8080
+export PUBLIC_KEY := ssh-ed25519 AAAA
+`,
+		},
+		{
+			name:    "k8s-configmap without name",
+			format:  FormatK8sConfigMap,
+			wantErr: true,
+		},
+		{
+			name:    "k8s-secret without name",
+			format:  FormatK8sSecret,
+			wantErr: true,
+		},
+		{
+			name:    "compose without name",
+			format:  FormatCompose,
+			wantErr: true,
+		},
+		{
+			name:    "unsupported format",
+			format:  "xml",
+			wantErr: true,
+		},
+		{
+			name:   "json",
+			format: FormatJSON,
+			want: `{
+  "API_TOKEN": "secret",
+  "DATABASE_URL": "postgres://localhost/db",
+  "PORT": "8080",
+  "PUBLIC_KEY": "ssh-ed25519 AAAA"
+}
+`,
+		},
+		{
+			name:   "export in order",
+			format: FormatExport,
+			opts:   Options{Shell: ShellBash, Order: []string{"PORT", "DATABASE_URL", "UNDEFINED"}},
+			want: `export PORT=8080
+export DATABASE_URL=postgres://localhost/db
+export API_TOKEN=secret
+export PUBLIC_KEY='ssh-ed25519 AAAA'
+`,
+		},
+		{
+			name:   "json in order",
+			format: FormatJSON,
+			opts:   Options{Order: []string{"PORT", "API_TOKEN"}},
+			want: `{
+  "PORT": "8080",
+  "API_TOKEN": "secret",
+  "DATABASE_URL": "postgres://localhost/db",
+  "PUBLIC_KEY": "ssh-ed25519 AAAA"
+}
+`,
+		},
+		{
+			name:   "dotenv",
+			format: FormatDotenv,
+			want: `API_TOKEN=secret
+DATABASE_URL=postgres://localhost/db
+PORT=8080
+PUBLIC_KEY="ssh-ed25519 AAAA"
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := Write(&buf, tt.format, envs, tt.opts)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("want error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("want:\n%s\ngot:\n%s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestAzureEscape(t *testing.T) {
+	if got, want := azureEscapeData("100%\nline2"), "100%AZP25%0Aline2"; got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+	if got, want := azureEscapeProperty("a;b]"), "a%3Bb%5D"; got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+}
+
+func TestPropertiesEscape(t *testing.T) {
+	tests := []struct {
+		in    string
+		isKey bool
+		want  string
+	}{
+		{"a b", true, `a\ b`},
+		{" a b", false, `\ a b`},
+		{`C:\path`, false, `C\:\\path`},
+		{"line1\nline2", false, `line1\nline2`},
+		{"#!=", false, `\#\!\=`},
+		{"caf\u00e9 \U0001F600", false, `caf\u00E9 \uD83D\uDE00`},
+	}
+	for _, tt := range tests {
+		if got := propertiesEscape(tt.in, tt.isKey); got != tt.want {
+			t.Errorf("propertiesEscape(%q, %v): want %q, got %q", tt.in, tt.isKey, tt.want, got)
+		}
+	}
+}
+
+func TestOmittedKeys(t *testing.T) {
+	envs := map[string]string{"PORT": "8080", "API_TOKEN": "secret", "DATABASE_URL": "postgres://localhost/db"}
+	s := &schema.Schema{}
+	tests := []struct {
+		format string
+		want   []string
+	}{
+		{FormatK8sConfigMap, []string{"API_TOKEN"}},
+		{FormatK8sSecret, []string{"DATABASE_URL", "PORT"}},
+		{FormatExport, nil},
+	}
+	for _, tt := range tests {
+		if got := OmittedKeys(tt.format, envs, s); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.format, got, tt.want)
+		}
+	}
+}
+
+func TestWriteMakeEscape(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatMake, map[string]string{"PASS": "pa$$#word"}, Options{}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := buf.String(), "export PASS := pa$$$$\\#word\n"; got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+	if err := Write(&buf, FormatMake, map[string]string{"PEM": "a\nb"}, Options{}); err == nil {
+		t.Error("want error but got none")
+	}
+}
+
+func TestEscapeComposeAndTfvars(t *testing.T) {
+	envs := map[string]string{"PASS": `p$a"ss`, "TPL": "${var}\n%{x}"}
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatCompose, envs, Options{Name: "app"}); err != nil {
+		t.Fatal(err)
+	}
+	if want := "      PASS: \"p$$a\\\"ss\"\n"; !bytes.Contains(buf.Bytes(), []byte(want)) {
+		t.Errorf("want %q in:\n%s", want, buf.String())
+	}
+	buf.Reset()
+	if err := Write(&buf, FormatTfvars, envs, Options{}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := buf.String(), "PASS = \"p$a\\\"ss\"\nTPL = \"$${var}\\n%%{x}\"\n"; got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+	if err := Write(&buf, FormatDockerEnv, map[string]string{"PEM": "a\nb"}, Options{}); err == nil {
+		t.Error("want error but got none")
+	}
+}
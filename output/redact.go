@@ -0,0 +1,88 @@
+package output
+
+import (
+	"bytes"
+	"cmp"
+	"io"
+	"slices"
+)
+
+// DefaultRedactMinLength is the default minimum length of values redacted by Redactor.
+// Shorter values such as "1" or "true" would redact unrelated output.
+const DefaultRedactMinLength = 6
+
+// Redactor is a writer replacing occurrences of secret values in the output written through it
+// with [REDACTED:KEY]. Bytes that may be the beginning of a secret are held until the rest is written,
+// so secrets split across writes are redacted too. Flush writes the held bytes.
+type Redactor struct {
+	w       io.Writer
+	secrets []redactedSecret
+	buf     []byte
+}
+
+type redactedSecret struct {
+	value       []byte
+	replacement []byte
+}
+
+// NewRedactor creates a new Redactor writing to w, redacting the values of secrets (key to value)
+// of at least minLength bytes.
+func NewRedactor(w io.Writer, secrets map[string]string, minLength int) *Redactor {
+	r := &Redactor{w: w}
+	for _, k := range sortedKeys(secrets) {
+		v := secrets[k]
+		if len(v) < max(minLength, 1) || slices.ContainsFunc(r.secrets, func(s redactedSecret) bool { return string(s.value) == v }) {
+			continue
+		}
+		r.secrets = append(r.secrets, redactedSecret{value: []byte(v), replacement: []byte("[REDACTED:" + k + "]")})
+	}
+	// Longer secrets first, so that a secret containing another one is redacted as a whole
+	slices.SortStableFunc(r.secrets, func(a, b redactedSecret) int {
+		return cmp.Compare(len(b.value), len(a.value))
+	})
+	return r
+}
+
+// Write writes p to the underlying writer with secret values redacted.
+func (r *Redactor) Write(p []byte) (int, error) {
+	if len(r.secrets) == 0 {
+		return r.w.Write(p)
+	}
+	r.buf = append(r.buf, p...)
+	var out []byte
+	i := 0
+scan:
+	for i < len(r.buf) {
+		rest := r.buf[i:]
+		for _, s := range r.secrets {
+			if bytes.HasPrefix(rest, s.value) {
+				out = append(out, s.replacement...)
+				i += len(s.value)
+				continue scan
+			}
+		}
+		for _, s := range r.secrets {
+			if len(rest) < len(s.value) && bytes.HasPrefix(s.value, rest) {
+				// Hold the rest, which may be the beginning of the secret
+				break scan
+			}
+		}
+		out = append(out, r.buf[i])
+		i++
+	}
+	r.buf = slices.Clone(r.buf[i:])
+	if _, err := r.w.Write(out); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush writes the bytes held as the possible beginning of a secret.
+func (r *Redactor) Flush() error {
+	if len(r.buf) == 0 {
+		return nil
+	}
+	_, err := r.w.Write(r.buf)
+	r.buf = nil
+	return err
+}
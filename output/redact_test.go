@@ -0,0 +1,49 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRedactor(t *testing.T) {
+	secrets := map[string]string{
+		"API_TOKEN":   "tok_123456",
+		"API_TOKEN_2": "tok_123456",
+		"DB_PASSWORD": "hunter22",
+		"DB_URL":      "postgres://app:hunter22@db",
+		"SHORT":       "abc",
+	}
+	tests := []struct {
+		name   string
+		writes []string
+		want   string
+	}{
+		{"plain", []string{"hello\n"}, "hello\n"},
+		{"one write", []string{"token=tok_123456 pw=hunter22\n"}, "token=[REDACTED:API_TOKEN] pw=[REDACTED:DB_PASSWORD]\n"},
+		{"split", []string{"token=tok_1", "23", "456!"}, "token=[REDACTED:API_TOKEN]!"},
+		{"longest first", []string{"url=postgres://app:hunter22@db"}, "url=[REDACTED:DB_URL]"},
+		{"partial at the end", []string{"tok_12"}, "tok_12"},
+		{"short values are kept", []string{"abc"}, "abc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			r := NewRedactor(&buf, secrets, DefaultRedactMinLength)
+			for _, w := range tt.writes {
+				n, err := r.Write([]byte(w))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if n != len(w) {
+					t.Errorf("wrote %d bytes, want %d", n, len(w))
+				}
+			}
+			if err := r.Flush(); err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// Shells of the export format.
+const (
+	ShellBash       = "bash"
+	ShellZsh        = "zsh"
+	ShellFish       = "fish"
+	ShellPowerShell = "powershell"
+)
+
+// Shells are the names of all supported shells.
+var Shells = []string{ShellBash, ShellZsh, ShellFish, ShellPowerShell}
+
+// shellSafeRe matches values that need no quoting in any supported shell.
+var shellSafeRe = regexp.MustCompile(`^[A-Za-z0-9_./:@%+,=-]+$`)
+
+// writeExport writes statements to set envs in the shell, so that `eval "$(envdo)"` is safe.
+// An empty shell means DefaultShell.
+func writeExport(w io.Writer, envs map[string]string, keys []string, shell string) error {
+	if shell == "" {
+		shell = DefaultShell
+	}
+	var format func(k, v string) string
+	switch shell {
+	case ShellBash, ShellZsh:
+		format = func(k, v string) string { return fmt.Sprintf("export %s=%s", k, posixQuote(v)) }
+	case ShellFish:
+		format = func(k, v string) string { return fmt.Sprintf("set -gx %s %s", k, fishQuote(v)) }
+	case ShellPowerShell:
+		format = func(k, v string) string { return fmt.Sprintf("$env:%s = %s", k, powerShellQuote(v)) }
+	default:
+		return fmt.Errorf("unsupported shell: %s (supported: %s)", shell, strings.Join(Shells, ", "))
+	}
+	for _, k := range keys {
+		if _, err := fmt.Fprintln(w, format(k, envs[k])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteEnvChanges writes statements to unset the keys and then set envs in the shell,
+// to be evaluated by shell hooks. An empty shell means DefaultShell.
+func WriteEnvChanges(w io.Writer, shell string, envs map[string]string, unset []string) error {
+	if shell == "" {
+		shell = DefaultShell
+	}
+	var format func(k string) string
+	switch shell {
+	case ShellBash, ShellZsh:
+		format = func(k string) string { return "unset " + k }
+	case ShellFish:
+		format = func(k string) string { return "set -e " + k }
+	case ShellPowerShell:
+		format = func(k string) string { return fmt.Sprintf("Remove-Item Env:%s -ErrorAction SilentlyContinue", k) }
+	default:
+		return fmt.Errorf("unsupported shell: %s (supported: %s)", shell, strings.Join(Shells, ", "))
+	}
+	for _, k := range unset {
+		if _, err := fmt.Fprintln(w, format(k)); err != nil {
+			return err
+		}
+	}
+	return writeExport(w, envs, sortedKeys(envs), shell)
+}
+
+// posixQuote quotes s with single quotes for POSIX shells.
+func posixQuote(s string) string {
+	if shellSafeRe.MatchString(s) {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// fishQuote quotes s with single quotes for fish, in which only \ and ' are escaped.
+func fishQuote(s string) string {
+	if shellSafeRe.MatchString(s) {
+		return s
+	}
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(s) + "'"
+}
+
+// powerShellQuote quotes s with single quotes for PowerShell, in which ' is doubled.
+func powerShellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
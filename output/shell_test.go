@@ -0,0 +1,82 @@
+package output
+
+import (
+	"bytes"
+	"os/exec"
+	"testing"
+)
+
+func TestWriteExportShells(t *testing.T) {
+	envs := map[string]string{
+		"PLAIN": "postgres://localhost:5432/db",
+		"QUOTE": "it's $HOME `date`",
+		"MULTI": "line1\nline2",
+		"EMPTY": "",
+	}
+	tests := []struct {
+		shell string
+		want  string
+	}{
+		{ShellBash, "export EMPTY=''\nexport MULTI='line1\nline2'\nexport PLAIN=postgres://localhost:5432/db\nexport QUOTE='it'\\''s $HOME `date`'\n"},
+		{ShellFish, "set -gx EMPTY ''\nset -gx MULTI 'line1\nline2'\nset -gx PLAIN postgres://localhost:5432/db\nset -gx QUOTE 'it\\'s $HOME `date`'\n"},
+		{ShellPowerShell, "$env:EMPTY = ''\n$env:MULTI = 'line1\nline2'\n$env:PLAIN = 'postgres://localhost:5432/db'\n$env:QUOTE = 'it''s $HOME `date`'\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.shell, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Write(&buf, FormatExport, envs, Options{Shell: tt.shell}); err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+	if err := Write(&bytes.Buffer{}, FormatExport, envs, Options{Shell: "csh"}); err == nil {
+		t.Error("want error for unsupported shell")
+	}
+}
+
+func TestWriteExportEval(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not found")
+	}
+	value := "it's $HOME `date` \"quoted\"\nline2\\"
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatExport, map[string]string{"VALUE": value}, Options{}); err != nil {
+		t.Fatal(err)
+	}
+	out, err := exec.Command(sh, "-c", buf.String()+`printf %s "$VALUE"`).Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != value {
+		t.Errorf("got %q, want %q", out, value)
+	}
+}
+
+func TestWriteEnvChanges(t *testing.T) {
+	envs := map[string]string{"A": "1"}
+	unset := []string{"B", "C"}
+	tests := []struct {
+		shell string
+		want  string
+	}{
+		{ShellZsh, "unset B\nunset C\nexport A=1\n"},
+		{ShellFish, "set -e B\nset -e C\nset -gx A 1\n"},
+		{ShellPowerShell, "Remove-Item Env:B -ErrorAction SilentlyContinue\nRemove-Item Env:C -ErrorAction SilentlyContinue\n$env:A = '1'\n"},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := WriteEnvChanges(&buf, tt.shell, envs, unset); err != nil {
+			t.Fatal(err)
+		}
+		if got := buf.String(); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.shell, got, tt.want)
+		}
+	}
+	if err := WriteEnvChanges(&bytes.Buffer{}, "csh", envs, unset); err == nil {
+		t.Error("want error for unsupported shell")
+	}
+}
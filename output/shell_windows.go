@@ -0,0 +1,6 @@
+//go:build windows
+
+package output
+
+// DefaultShell is the default shell of the export format.
+const DefaultShell = ShellPowerShell
@@ -0,0 +1,52 @@
+package output
+
+import (
+	"io"
+	"os"
+	"text/template"
+
+	"github.com/k1LoW/envdo/env"
+)
+
+// templateFuncs are the functions available in templates in addition to the built-in ones.
+func templateFuncs(envs map[string]string, order []string) template.FuncMap {
+	return template.FuncMap{
+		// keys returns the keys in the order of order, followed by the others sorted.
+		"keys": func() []string {
+			return env.OrderedKeys(envs, order)
+		},
+		// env returns the value of the key, or an empty string if it is not defined.
+		"env": func(key string) string {
+			return envs[key]
+		},
+		// default returns def if v is empty.
+		"default": func(def, v string) string {
+			if v == "" {
+				return def
+			}
+			return v
+		},
+	}
+}
+
+// Template renders the Go text/template text with envs as data to w.
+// Values are referred by {{ .KEY }}, which fails if the key is not defined,
+// or by {{ env "KEY" }}, which is empty if the key is not defined.
+// {{ env "KEY" | default "value" }} falls back to the value.
+// {{ range keys }} iterates the keys in the order of order (see Options.Order).
+func Template(w io.Writer, name, text string, envs map[string]string, order []string) error {
+	tmpl, err := template.New(name).Option("missingkey=error").Funcs(templateFuncs(envs, order)).Parse(text)
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(w, envs)
+}
+
+// Envsubst writes text with $VAR and ${VAR} replaced with values of envs to w, like envsubst.
+// Undefined variables are replaced with empty strings.
+func Envsubst(w io.Writer, text string, envs map[string]string) error {
+	_, err := io.WriteString(w, os.Expand(text, func(key string) string {
+		return envs[key]
+	}))
+	return err
+}
@@ -0,0 +1,44 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestTemplate(t *testing.T) {
+	envs := map[string]string{"HOST": "example.com", "PORT": "8080"}
+	tests := []struct {
+		text    string
+		want    string
+		wantErr bool
+	}{
+		{"server {{ .HOST }}:{{ .PORT }};", "server example.com:8080;", false},
+		{`timeout {{ env "TIMEOUT" | default "30s" }};`, "timeout 30s;", false},
+		{`{{ if env "DEBUG" }}debug{{ else }}release{{ end }}`, "release", false},
+		{"{{ .UNDEFINED }}", "", true},
+		{"{{ range keys }}{{ . }};{{ end }}", "PORT;HOST;", false},
+		{"{{ .HOST", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := Template(&buf, "test", tt.text, envs, []string{"PORT", "HOST"})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && buf.String() != tt.want {
+				t.Errorf("got %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestEnvsubst(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Envsubst(&buf, "listen ${PORT}; host $HOST; missing [$MISSING]", map[string]string{"PORT": "8080", "HOST": "example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := buf.String(), "listen 8080; host example.com; missing []"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
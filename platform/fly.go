@@ -0,0 +1,124 @@
+package platform
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/k1LoW/exec"
+)
+
+// Fly is a client of flyctl for Fly.io app secrets.
+type Fly struct {
+	run func(ctx context.Context, stdin io.Reader, args ...string) ([]byte, error)
+}
+
+type flySecret struct {
+	Name string `json:"name"`
+}
+
+// NewFly creates a new Fly client using flyctl in $PATH.
+func NewFly() *Fly {
+	return &Fly{
+		run: runFlyctl,
+	}
+}
+
+// Secrets returns the secrets of the app.
+// Fly.io does not expose secret values through its API, so values are read
+// from the environment of a running machine via `flyctl ssh console`.
+func (f *Fly) Secrets(ctx context.Context, app string) (map[string]string, error) {
+	if app == "" {
+		return nil, errors.New("fly app name is required")
+	}
+	out, err := f.run(ctx, nil, "secrets", "list", "--app", app, "--json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list fly secrets: %w", err)
+	}
+	var secrets []flySecret
+	if err := json.Unmarshal(out, &secrets); err != nil {
+		return nil, fmt.Errorf("failed to parse fly secrets: %w", err)
+	}
+	if len(secrets) == 0 {
+		return map[string]string{}, nil
+	}
+
+	out, err = f.run(ctx, nil, "ssh", "console", "--app", app, "--command", "env")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fly machine environment: %w", err)
+	}
+	machineEnvs := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		k, v, ok := strings.Cut(strings.TrimRight(scanner.Text(), "\r"), "=")
+		if !ok {
+			continue
+		}
+		machineEnvs[k] = v
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	envs := make(map[string]string)
+	for _, s := range secrets {
+		v, ok := machineEnvs[s.Name]
+		if !ok {
+			return nil, fmt.Errorf("secret %s is not set in the running machine (not deployed yet?)", s.Name)
+		}
+		envs[s.Name] = v
+	}
+	return envs, nil
+}
+
+// SetSecrets sets envs as secrets of the app.
+// If stage is true, the secrets are staged without restarting machines.
+func (f *Fly) SetSecrets(ctx context.Context, app string, envs map[string]string, stage bool) error {
+	if app == "" {
+		return errors.New("fly app name is required")
+	}
+	keys := make([]string, 0, len(envs))
+	for k := range envs {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	var buf bytes.Buffer
+	for _, k := range keys {
+		v := envs[k]
+		if strings.ContainsAny(v, "\r\n") {
+			v = `"""` + v + `"""`
+		}
+		fmt.Fprintf(&buf, "%s=%s\n", k, v)
+	}
+	args := []string{"secrets", "import", "--app", app}
+	if stage {
+		args = append(args, "--stage")
+	}
+	if _, err := f.run(ctx, &buf, args...); err != nil {
+		return fmt.Errorf("failed to import fly secrets: %w", err)
+	}
+	return nil
+}
+
+// runFlyctl runs flyctl with args and returns its stdout.
+func runFlyctl(ctx context.Context, stdin io.Reader, args ...string) ([]byte, error) {
+	bin := "flyctl"
+	if _, err := exec.LookPath(bin); err != nil {
+		bin = "fly"
+	}
+	c := exec.CommandContext(ctx, bin, args...)
+	c.Stdin = stdin
+	var stderr bytes.Buffer
+	c.Stderr = &stderr
+	out, err := c.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w: %s", bin, strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
@@ -0,0 +1,71 @@
+package platform
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestFlySecrets(t *testing.T) {
+	f := &Fly{
+		run: func(ctx context.Context, stdin io.Reader, args ...string) ([]byte, error) {
+			switch strings.Join(args[:2], " ") {
+			case "secrets list":
+				return []byte(`[{"Name":"DATABASE_URL","Digest":"xxx"},{"name":"API_KEY"}]`), nil
+			case "ssh console":
+				return []byte("PATH=/usr/bin\nDATABASE_URL=postgres://db\nAPI_KEY=a=b\n"), nil
+			}
+			t.Fatalf("unexpected args: %v", args)
+			return nil, nil
+		},
+	}
+	got, err := f.Secrets(context.Background(), "app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"DATABASE_URL": "postgres://db",
+		"API_KEY":      "a=b",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("key %q: want %q, got %q", k, v, got[k])
+		}
+	}
+}
+
+func TestFlySetSecrets(t *testing.T) {
+	var (
+		gotArgs  []string
+		gotStdin string
+	)
+	f := &Fly{
+		run: func(ctx context.Context, stdin io.Reader, args ...string) ([]byte, error) {
+			gotArgs = args
+			b, err := io.ReadAll(stdin)
+			if err != nil {
+				return nil, err
+			}
+			gotStdin = string(b)
+			return nil, nil
+		},
+	}
+	envs := map[string]string{
+		"B":   "b",
+		"A":   "a",
+		"PEM": "line1\nline2",
+	}
+	if err := f.SetSecrets(context.Background(), "app", envs, true); err != nil {
+		t.Fatal(err)
+	}
+	if want := "secrets import --app app --stage"; strings.Join(gotArgs, " ") != want {
+		t.Errorf("want args %q, got %q", want, strings.Join(gotArgs, " "))
+	}
+	if want := "A=a\nB=b\nPEM=\"\"\"line1\nline2\"\"\"\n"; gotStdin != want {
+		t.Errorf("want stdin %q, got %q", want, gotStdin)
+	}
+}
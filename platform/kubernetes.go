@@ -0,0 +1,86 @@
+package platform
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/k1LoW/exec"
+)
+
+// Kubernetes is a client of kubectl for the data of Secrets and ConfigMaps,
+// using the kubeconfig of kubectl ($KUBECONFIG or ~/.kube/config).
+type Kubernetes struct {
+	run func(ctx context.Context, args ...string) ([]byte, error)
+}
+
+type k8sObject struct {
+	Data map[string]string `json:"data"`
+}
+
+// NewKubernetes creates a new Kubernetes client using kubectl in $PATH.
+func NewKubernetes() *Kubernetes {
+	return &Kubernetes{
+		run: runKubectl,
+	}
+}
+
+// Data returns the data of the resource, secret/NAME or configmap/NAME (or cm/NAME), in the namespace.
+// Values of Secrets are decoded from base64. The current namespace of the kubeconfig is used if namespace is empty.
+func (k *Kubernetes) Data(ctx context.Context, namespace, resource string) (map[string]string, error) {
+	kind, name, ok := strings.Cut(resource, "/")
+	if !ok || name == "" || strings.Contains(name, "/") {
+		return nil, fmt.Errorf("invalid Kubernetes resource %q: must be secret/NAME or configmap/NAME", resource)
+	}
+	var secret bool
+	switch strings.ToLower(kind) {
+	case "secret", "secrets":
+		secret = true
+	case "configmap", "configmaps", "cm":
+	default:
+		return nil, fmt.Errorf("invalid Kubernetes resource %q: must be secret/NAME or configmap/NAME", resource)
+	}
+	args := []string{"get", kind, name, "--output", "json"}
+	if namespace != "" {
+		args = append(args, "--namespace", namespace)
+	}
+	out, err := k.run(ctx, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s: %w", resource, err)
+	}
+	var obj k8sObject
+	if err := json.Unmarshal(out, &obj); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", resource, err)
+	}
+	data := make(map[string]string, len(obj.Data))
+	for key, v := range obj.Data {
+		if !secret {
+			data[key] = v
+			continue
+		}
+		b, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s of %s: %w", key, resource, err)
+		}
+		data[key] = string(b)
+	}
+	return data, nil
+}
+
+func runKubectl(ctx context.Context, args ...string) ([]byte, error) {
+	if _, err := exec.LookPath("kubectl"); err != nil {
+		return nil, errors.New("kubectl is not found: install it to read Kubernetes resources")
+	}
+	c := exec.CommandContext(ctx, "kubectl", args...)
+	var stderr bytes.Buffer
+	c.Stderr = &stderr
+	out, err := c.Output()
+	if err != nil {
+		return nil, fmt.Errorf("kubectl %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
@@ -0,0 +1,48 @@
+package platform
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestKubernetesData(t *testing.T) {
+	var got [][]string
+	k := &Kubernetes{run: func(_ context.Context, args ...string) ([]byte, error) {
+		got = append(got, args)
+		if args[1] == "secret" {
+			return []byte(`{"kind":"Secret","data":{"DB_PASSWORD":"czNjcjN0","API_KEY":"a2V5"}}`), nil
+		}
+		return []byte(`{"kind":"ConfigMap","data":{"LOG_LEVEL":"debug"}}`), nil
+	}}
+	ctx := context.Background()
+
+	data, err := k.Data(ctx, "prod", "secret/app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"DB_PASSWORD": "s3cr3t", "API_KEY": "key"}; !maps.Equal(data, want) {
+		t.Errorf("got %v, want %v", data, want)
+	}
+	data, err = k.Data(ctx, "", "cm/app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"LOG_LEVEL": "debug"}; !maps.Equal(data, want) {
+		t.Errorf("got %v, want %v", data, want)
+	}
+	wantArgs := [][]string{
+		{"get", "secret", "app", "--output", "json", "--namespace", "prod"},
+		{"get", "cm", "app", "--output", "json"},
+	}
+	if !slices.EqualFunc(got, wantArgs, slices.Equal) {
+		t.Errorf("got %v, want %v", got, wantArgs)
+	}
+
+	for _, r := range []string{"app", "deployment/app", "secret/", "secret/a/b"} {
+		if _, err := k.Data(ctx, "", r); err == nil {
+			t.Errorf("%s: want error", r)
+		}
+	}
+}
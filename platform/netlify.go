@@ -0,0 +1,103 @@
+package platform
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/k1LoW/envdo/internal/api"
+)
+
+const netlifyEndpoint = "https://api.netlify.com/api/v1"
+
+// Netlify is a client of the Netlify API for site environment variables.
+type Netlify struct {
+	endpoint string
+	token    string
+	client   *http.Client
+}
+
+type netlifySite struct {
+	AccountID string `json:"account_id"`
+}
+
+type netlifyEnv struct {
+	Key    string             `json:"key"`
+	Values []netlifyEnvValues `json:"values"`
+}
+
+type netlifyEnvValues struct {
+	Value   string `json:"value"`
+	Context string `json:"context"`
+}
+
+// NewNetlify creates a new Netlify client with a personal access token.
+func NewNetlify(token string) *Netlify {
+	return &Netlify{
+		endpoint: netlifyEndpoint,
+		token:    token,
+		client:   api.NewClient(),
+	}
+}
+
+// Envs returns the environment variables of the site for the deploy context.
+// Values set for the deploy context take priority over values set for all contexts.
+// If accountID is empty, it is looked up from the site.
+func (n *Netlify) Envs(ctx context.Context, siteID, accountID, deployContext string) (map[string]string, error) {
+	if siteID == "" {
+		return nil, errors.New("netlify site id is required")
+	}
+	if accountID == "" {
+		var site netlifySite
+		if err := getJSON(ctx, n.client, fmt.Sprintf("%s/sites/%s", n.endpoint, url.PathEscape(siteID)), n.header(), &site); err != nil {
+			return nil, fmt.Errorf("failed to get netlify site: %w", err)
+		}
+		accountID = site.AccountID
+	}
+
+	q := url.Values{}
+	q.Set("site_id", siteID)
+	q.Set("context_name", deployContext)
+	var vars []netlifyEnv
+	if err := getJSON(ctx, n.client, fmt.Sprintf("%s/accounts/%s/env?%s", n.endpoint, url.PathEscape(accountID), q.Encode()), n.header(), &vars); err != nil {
+		return nil, fmt.Errorf("failed to get netlify environment variables: %w", err)
+	}
+
+	envs := make(map[string]string)
+	for _, v := range vars {
+		value, ok := netlifyContextValue(v.Values, deployContext)
+		if !ok {
+			continue
+		}
+		envs[v.Key] = value
+	}
+	return envs, nil
+}
+
+func (n *Netlify) header() http.Header {
+	h := http.Header{}
+	if n.token != "" {
+		h.Set("Authorization", "Bearer "+n.token)
+	}
+	return h
+}
+
+// netlifyContextValue returns the value for the deploy context, falling back to the "all" context.
+func netlifyContextValue(values []netlifyEnvValues, deployContext string) (string, bool) {
+	var (
+		fallback string
+		found    bool
+	)
+	for _, v := range values {
+		switch v.Context {
+		case deployContext:
+			return v.Value, true
+		case "all":
+			fallback = v.Value
+			found = true
+		}
+	}
+	return fallback, found
+}
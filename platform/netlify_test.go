@@ -0,0 +1,49 @@
+package platform
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNetlifyEnvs(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/sites/site-1", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer token" {
+			t.Errorf("unexpected Authorization header: %q", got)
+		}
+		_, _ = w.Write([]byte(`{"account_id":"acc-1"}`))
+	})
+	mux.HandleFunc("/accounts/acc-1/env", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("site_id"); got != "site-1" {
+			t.Errorf("unexpected site_id: %q", got)
+		}
+		_, _ = w.Write([]byte(`[
+  {"key":"API_URL","values":[{"value":"https://dev.example.com","context":"all"},{"value":"https://example.com","context":"production"}]},
+  {"key":"COMMON","values":[{"value":"common","context":"all"}]},
+  {"key":"PREVIEW_ONLY","values":[{"value":"preview","context":"deploy-preview"}]}
+]`))
+	})
+	ts := httptest.NewServer(mux)
+	t.Cleanup(ts.Close)
+
+	n := NewNetlify("token")
+	n.endpoint = ts.URL
+	got, err := n.Envs(context.Background(), "site-1", "", "production")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"API_URL": "https://example.com",
+		"COMMON":  "common",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("key %q: want %q, got %q", k, v, got[k])
+		}
+	}
+}
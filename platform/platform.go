@@ -0,0 +1,44 @@
+// Package platform provides clients for hosting platforms that keep environment variables.
+package platform
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/k1LoW/envdo/internal/api"
+)
+
+// getJSON sends a GET request to url and decodes the JSON response into v.
+func getJSON(ctx context.Context, client *http.Client, url string, header http.Header, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	return doJSON(client, req, header, v)
+}
+
+// postJSON sends a POST request with body encoded as JSON to url and decodes the JSON response into v.
+func postJSON(ctx context.Context, client *http.Client, url string, header http.Header, body, v any) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return doJSON(client, req, header, v)
+}
+
+// doJSON sends req with header added and decodes the JSON response into v.
+func doJSON(client *http.Client, req *http.Request, header http.Header, v any) error {
+	for k, vv := range header {
+		for _, vvv := range vv {
+			req.Header.Add(k, vvv)
+		}
+	}
+	return api.DoJSON(client, req, v)
+}
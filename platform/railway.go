@@ -0,0 +1,90 @@
+package platform
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/k1LoW/envdo/internal/api"
+)
+
+const railwayEndpoint = "https://backboard.railway.com/graphql/v2"
+
+const railwayVariablesQuery = `query variables($projectId: String!, $environmentId: String!, $serviceId: String) {
+  variables(projectId: $projectId, environmentId: $environmentId, serviceId: $serviceId)
+}`
+
+// Railway is a client of the Railway public GraphQL API for variables.
+type Railway struct {
+	endpoint     string
+	token        string
+	projectToken string
+	client       *http.Client
+}
+
+type railwayRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables"`
+}
+
+type railwayResponse struct {
+	Data struct {
+		Variables map[string]string `json:"variables"`
+	} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// NewRailway creates a new Railway client.
+// token is an account or team token and projectToken is a project token; one of them is required.
+func NewRailway(token, projectToken string) *Railway {
+	return &Railway{
+		endpoint:     railwayEndpoint,
+		token:        token,
+		projectToken: projectToken,
+		client:       api.NewClient(),
+	}
+}
+
+// Variables returns the variables of the project environment.
+// If serviceID is empty, the shared variables of the environment are returned.
+func (r *Railway) Variables(ctx context.Context, projectID, environmentID, serviceID string) (map[string]string, error) {
+	if projectID == "" || environmentID == "" {
+		return nil, errors.New("railway project id and environment id are required")
+	}
+	vars := map[string]any{
+		"projectId":     projectID,
+		"environmentId": environmentID,
+	}
+	if serviceID != "" {
+		vars["serviceId"] = serviceID
+	}
+	var res railwayResponse
+	if err := postJSON(ctx, r.client, r.endpoint, r.header(), railwayRequest{Query: railwayVariablesQuery, Variables: vars}, &res); err != nil {
+		return nil, fmt.Errorf("failed to get railway variables: %w", err)
+	}
+	if len(res.Errors) > 0 {
+		msgs := make([]string, 0, len(res.Errors))
+		for _, e := range res.Errors {
+			msgs = append(msgs, e.Message)
+		}
+		return nil, fmt.Errorf("failed to get railway variables: %s", strings.Join(msgs, "; "))
+	}
+	if res.Data.Variables == nil {
+		return map[string]string{}, nil
+	}
+	return res.Data.Variables, nil
+}
+
+func (r *Railway) header() http.Header {
+	h := http.Header{}
+	if r.projectToken != "" {
+		h.Set("Project-Access-Token", r.projectToken)
+	} else if r.token != "" {
+		h.Set("Authorization", "Bearer "+r.token)
+	}
+	return h
+}
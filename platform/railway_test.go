@@ -0,0 +1,49 @@
+package platform
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRailwayVariables(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Project-Access-Token"); got != "ptoken" {
+			t.Errorf("unexpected Project-Access-Token header: %q", got)
+		}
+		var req railwayRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatal(err)
+		}
+		if req.Variables["serviceId"] != "svc" {
+			t.Errorf("unexpected serviceId: %v", req.Variables["serviceId"])
+		}
+		_, _ = w.Write([]byte(`{"data":{"variables":{"DATABASE_URL":"postgres://db","PORT":"3000"}}}`))
+	}))
+	t.Cleanup(ts.Close)
+
+	r := NewRailway("", "ptoken")
+	r.endpoint = ts.URL
+	got, err := r.Variables(context.Background(), "prj", "env", "svc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got["DATABASE_URL"] != "postgres://db" || got["PORT"] != "3000" {
+		t.Errorf("unexpected variables: %v", got)
+	}
+}
+
+func TestRailwayVariablesError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"errors":[{"message":"Not Authorized"}]}`))
+	}))
+	t.Cleanup(ts.Close)
+
+	r := NewRailway("token", "")
+	r.endpoint = ts.URL
+	if _, err := r.Variables(context.Background(), "prj", "env", ""); err == nil {
+		t.Error("want error but got none")
+	}
+}
@@ -0,0 +1,183 @@
+// Package schema provides the schema of environment variables defined in .envdo.schema.yml.
+package schema
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-yaml"
+)
+
+// Filename is the default schema filename.
+const Filename = ".envdo.schema.yml"
+
+// Types of environment variable values.
+const (
+	TypeString   = "string"
+	TypeInt      = "int"
+	TypeFloat    = "float"
+	TypeBool     = "bool"
+	TypeURL      = "url"
+	TypeDuration = "duration"
+)
+
+// secretKeyRe matches names of keys that commonly hold secrets.
+var secretKeyRe = regexp.MustCompile(`(?i)(TOKEN|SECRET|PASSWORD|PASSWD|PASS|KEY|CREDENTIAL|PRIVATE|AUTH)`)
+
+var types = []string{TypeString, TypeInt, TypeFloat, TypeBool, TypeURL, TypeDuration}
+
+// Schema represents the schema of environment variables.
+type Schema struct {
+	Keys map[string]*Key `yaml:"keys"`
+}
+
+// Key represents the schema of an environment variable.
+type Key struct {
+	Type        string `yaml:"type,omitempty"`
+	Description string `yaml:"description,omitempty"`
+	Secret      *bool  `yaml:"secret,omitempty"`
+	Required    bool   `yaml:"required,omitempty"`
+	// Enum is the list of allowed values.
+	Enum []string `yaml:"enum,omitempty"`
+	// Pattern is the regular expression that values must match.
+	Pattern string `yaml:"pattern,omitempty"`
+
+	patternRe *regexp.Regexp
+}
+
+// Load loads a schema file.
+func Load(path string) (*Schema, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	s := &Schema{}
+	if err := yaml.Unmarshal(b, s); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	for name, k := range s.Keys {
+		if k == nil {
+			s.Keys[name] = &Key{}
+			continue
+		}
+		if k.Type != "" && !slices.Contains(types, k.Type) {
+			return nil, fmt.Errorf("invalid type of %s in %s: %s", name, path, k.Type)
+		}
+		if k.Pattern != "" {
+			re, err := regexp.Compile(k.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern of %s in %s: %w", name, path, err)
+			}
+			k.patternRe = re
+		}
+	}
+	return s, nil
+}
+
+// Find loads the schema file in dir.
+// If the schema file does not exist, it returns an empty schema.
+func Find(dir string) (*Schema, error) {
+	path := filepath.Join(dir, Filename)
+	if _, err := os.Stat(path); err != nil {
+		if os.IsNotExist(err) {
+			return &Schema{}, nil
+		}
+		return nil, err
+	}
+	return Load(path)
+}
+
+// Key returns the schema of the key, or nil if the key is not defined.
+func (s *Schema) Key(name string) *Key {
+	if s == nil {
+		return nil
+	}
+	return s.Keys[name]
+}
+
+// Type returns the type of the key defined in the schema, or an empty string if not defined.
+func (s *Schema) Type(name string) string {
+	k := s.Key(name)
+	if k == nil {
+		return ""
+	}
+	return k.Type
+}
+
+// RequiredKeys returns the keys marked as required in the schema, sorted by name.
+func (s *Schema) RequiredKeys() []string {
+	if s == nil {
+		return nil
+	}
+	var keys []string
+	for name, k := range s.Keys {
+		if k.Required {
+			keys = append(keys, name)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// IsSecret reports whether the key holds a secret.
+// The secret field in the schema takes priority, otherwise keys are classified by their names.
+func (s *Schema) IsSecret(name string) bool {
+	if k := s.Key(name); k != nil && k.Secret != nil {
+		return *k.Secret
+	}
+	return secretKeyRe.MatchString(name)
+}
+
+// Validate validates the value of the key against its type, enum and pattern defined in the schema.
+func (s *Schema) Validate(name, value string) error {
+	switch s.Type(name) {
+	case TypeInt:
+		if _, err := strconv.Atoi(value); err != nil {
+			return fmt.Errorf("%s must be an integer: %q", name, value)
+		}
+	case TypeFloat:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return fmt.Errorf("%s must be a number: %q", name, value)
+		}
+	case TypeBool:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("%s must be a boolean: %q", name, value)
+		}
+	case TypeURL:
+		u, err := url.Parse(value)
+		if err != nil || u.Scheme == "" || (u.Host == "" && u.Opaque == "") {
+			return fmt.Errorf("%s must be a URL: %q", name, value)
+		}
+	case TypeDuration:
+		if _, err := time.ParseDuration(value); err != nil {
+			return fmt.Errorf("%s must be a duration: %q", name, value)
+		}
+	}
+	k := s.Key(name)
+	if k == nil {
+		return nil
+	}
+	if len(k.Enum) > 0 && !slices.Contains(k.Enum, value) {
+		return fmt.Errorf("%s must be one of %s: %q", name, strings.Join(k.Enum, ", "), value)
+	}
+	if k.Pattern != "" {
+		re := k.patternRe
+		if re == nil {
+			var err error
+			if re, err = regexp.Compile(k.Pattern); err != nil {
+				return fmt.Errorf("invalid pattern of %s: %w", name, err)
+			}
+		}
+		if !re.MatchString(value) {
+			return fmt.Errorf("%s must match %s: %q", name, k.Pattern, value)
+		}
+	}
+	return nil
+}
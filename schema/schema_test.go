@@ -0,0 +1,172 @@
+package schema
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		wantErr  bool
+		wantType map[string]string
+	}{
+		{
+			name: "valid schema",
+			content: `keys:
+  PORT:
+    type: int
+    description: listen port
+  DATABASE_URL:
+    type: url
+  NAME:
+`,
+			wantType: map[string]string{
+				"PORT":         TypeInt,
+				"DATABASE_URL": TypeURL,
+				"NAME":         "",
+				"UNDEFINED":    "",
+			},
+		},
+		{
+			name: "invalid type",
+			content: `keys:
+  PORT:
+    type: integer
+`,
+			wantErr: true,
+		},
+		{
+			name: "invalid pattern",
+			content: `keys:
+  VERSION:
+    pattern: "v[0-9"
+`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), Filename)
+			if err := os.WriteFile(path, []byte(tt.content), 0600); err != nil {
+				t.Fatal(err)
+			}
+			s, err := Load(path)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("want error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			for key, want := range tt.wantType {
+				if got := s.Type(key); got != want {
+					t.Errorf("key %q: want type %q, got %q", key, want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestFind(t *testing.T) {
+	s, err := Find(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Key("ANY") != nil {
+		t.Error("want empty schema")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	s := &Schema{
+		Keys: map[string]*Key{
+			"PORT":    {Type: TypeInt},
+			"RATIO":   {Type: TypeFloat},
+			"DEBUG":   {Type: TypeBool},
+			"API_URL": {Type: TypeURL},
+			"TIMEOUT": {Type: TypeDuration},
+			"NAME":    {},
+			"APP_ENV": {Enum: []string{"development", "staging", "production"}},
+			"VERSION": {Pattern: `^v[0-9]+\.[0-9]+$`},
+			"LEVEL":   {Type: TypeInt, Enum: []string{"1", "2"}},
+		},
+	}
+	tests := []struct {
+		key     string
+		value   string
+		wantErr bool
+	}{
+		{"PORT", "8080", false},
+		{"PORT", "http", true},
+		{"RATIO", "0.5", false},
+		{"RATIO", "half", true},
+		{"DEBUG", "true", false},
+		{"DEBUG", "yes", true},
+		{"API_URL", "https://example.com/api", false},
+		{"API_URL", "example.com", true},
+		{"TIMEOUT", "30s", false},
+		{"TIMEOUT", "30", true},
+		{"NAME", "anything", false},
+		{"UNDEFINED", "anything", false},
+		{"APP_ENV", "staging", false},
+		{"APP_ENV", "prod", true},
+		{"VERSION", "v1.2", false},
+		{"VERSION", "1.2", true},
+		{"LEVEL", "2", false},
+		{"LEVEL", "3", true},
+		{"LEVEL", "x", true},
+	}
+	for _, tt := range tests {
+		err := s.Validate(tt.key, tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Validate(%q, %q): want error %v, got %v", tt.key, tt.value, tt.wantErr, err)
+		}
+	}
+}
+
+func TestIsSecret(t *testing.T) {
+	yes, no := true, false
+	s := &Schema{
+		Keys: map[string]*Key{
+			"DATABASE_URL":    {Secret: &yes},
+			"PUBLIC_KEY_PATH": {Secret: &no},
+		},
+	}
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{"DATABASE_URL", true},
+		{"PUBLIC_KEY_PATH", false},
+		{"API_TOKEN", true},
+		{"DB_PASSWORD", true},
+		{"PORT", false},
+	}
+	for _, tt := range tests {
+		if got := s.IsSecret(tt.key); got != tt.want {
+			t.Errorf("IsSecret(%q): want %v, got %v", tt.key, tt.want, got)
+		}
+	}
+}
+
+func TestRequiredKeys(t *testing.T) {
+	s := &Schema{Keys: map[string]*Key{
+		"PORT":         {Required: true},
+		"DATABASE_URL": {Required: true},
+		"DEBUG":        {},
+	}}
+	got := s.RequiredKeys()
+	if len(got) != 2 || got[0] != "DATABASE_URL" || got[1] != "PORT" {
+		t.Errorf("got %v", got)
+	}
+	var nilSchema *Schema
+	if got := nilSchema.RequiredKeys(); got != nil {
+		t.Errorf("got %v", got)
+	}
+}
@@ -0,0 +1,108 @@
+// Package trust records the files allowed to be loaded automatically by the shell hook of envdo
+// and to run their command substitutions.
+package trust
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// Store is a set of allowed files with the SHA-256 digests of their contents.
+// A file is not allowed anymore once its content changes, so it must be allowed again after review.
+type Store struct {
+	path  string
+	files map[string]string
+}
+
+// DefaultPath returns the path of the store in $XDG_STATE_HOME/envdo, falling back to ~/.local/state/envdo.
+func DefaultPath() string {
+	stateDir := os.Getenv("XDG_STATE_HOME")
+	if stateDir == "" {
+		if homeDir, err := os.UserHomeDir(); err == nil {
+			stateDir = filepath.Join(homeDir, ".local", "state")
+		}
+	}
+	return filepath.Join(stateDir, "envdo", "allowed.json")
+}
+
+// Open opens the store at path. The store is empty if the file does not exist.
+func Open(path string) (*Store, error) {
+	s := &Store{path: path, files: make(map[string]string)}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &s.files); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Allow allows the file with its current content.
+func (s *Store) Allow(file string) error {
+	file, err := filepath.Abs(file)
+	if err != nil {
+		return err
+	}
+	sum, err := digest(file)
+	if err != nil {
+		return err
+	}
+	s.files[file] = sum
+	return nil
+}
+
+// Deny removes the file from the store.
+func (s *Store) Deny(file string) error {
+	file, err := filepath.Abs(file)
+	if err != nil {
+		return err
+	}
+	delete(s.files, file)
+	return nil
+}
+
+// Allowed reports whether the file is allowed and has not changed since then.
+func (s *Store) Allowed(file string) (bool, error) {
+	file, err := filepath.Abs(file)
+	if err != nil {
+		return false, err
+	}
+	want, ok := s.files[file]
+	if !ok {
+		return false, nil
+	}
+	sum, err := digest(file)
+	if err != nil {
+		return false, err
+	}
+	return sum == want, nil
+}
+
+// Save writes the store to its path with 0600 permissions.
+func (s *Store) Save() error {
+	b, err := json.MarshalIndent(s.files, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, append(b, '\n'), 0600)
+}
+
+// digest returns the SHA-256 digest of the content of the file in hex.
+func digest(file string) (string, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,53 @@
+package trust
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStore(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "state", "allowed.json")
+	file := filepath.Join(dir, ".env")
+	if err := os.WriteFile(file, []byte("A=1\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := s.Allowed(file); err != nil || ok {
+		t.Fatalf("got %v, %v, want not allowed before Allow", ok, err)
+	}
+	if err := s.Allow(file); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err = Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := s.Allowed(file); err != nil || !ok {
+		t.Fatalf("got %v, %v, want allowed after Allow", ok, err)
+	}
+	if err := os.WriteFile(file, []byte("A=2\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := s.Allowed(file); err != nil || ok {
+		t.Errorf("got %v, %v, want not allowed after the change", ok, err)
+	}
+	if err := s.Allow(file); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Deny(file); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := s.Allowed(file); err != nil || ok {
+		t.Errorf("got %v, %v, want not allowed after Deny", ok, err)
+	}
+}